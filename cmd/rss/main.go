@@ -1,26 +1,37 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
 	"slices"
 	"strconv"
 	"strings"
 	"syscall"
 	"text/tabwriter"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	charmlog "github.com/charmbracelet/log"
+	"github.com/muesli/termenv"
 	"github.com/pders01/dotlocal/mdns"
 	"github.com/pders01/dotlocal/port80"
 	"github.com/pders01/fwrd/internal/audit"
@@ -72,12 +83,21 @@ func loadLuaPlugins(m *feed.Manager) {
 // Version is the version of the application, set at build time
 var Version = "dev"
 
+// Commit is the VCS revision the binary was built from, set at build
+// time. When unset, the revision Go stamped into the build info is used.
+var Commit = ""
+
 var (
 	cfgFile        string
 	dbPath         string
 	debugFlag      bool
+	logLevel       string
+	printOpen      bool
+	migrateConfig  bool
+	permissive     bool
 	quiet          bool
 	forceRefresh   bool
+	ephemeral      bool
 	serveAddr      string
 	serveMDNS      bool
 	serveMDNSName  string
@@ -107,6 +127,14 @@ var (
 	logsFollow     bool
 	logsLines      int
 	logsService    bool
+	statsTop       int
+	versionJSON    bool
+	deleteMatch    string
+	deleteYes      bool
+	filterInclude  []string
+	filterExclude  []string
+	filterClear    bool
+	noColor        bool
 )
 
 var rootCmd = &cobra.Command{
@@ -123,10 +151,16 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/fwrd/config.toml)")
 	rootCmd.PersistentFlags().StringVar(&dbPath, "db", "", "database file path (overrides config)")
 	rootCmd.PersistentFlags().BoolVar(&debugFlag, "debug", false, "enable debug logging to ~/.fwrd/fwrd.log")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "log to ~/.fwrd/fwrd.log at this level and above: debug | info | warn | error (overrides --debug)")
+	rootCmd.PersistentFlags().BoolVar(&printOpen, "print-open", false, "show the command that opening a link would run instead of running it")
+	rootCmd.PersistentFlags().BoolVar(&migrateConfig, "migrate-config", false, "upgrade an older config file to the current format (rewrites it without comments; keeps a .bak copy)")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output in the CLI and TUI (also NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVar(&permissive, "permissive", false, "allow localhost and private-network feed URLs for local development (also FWRD_PERMISSIVE=1); relaxes SSRF protection")
 
 	// TUI-specific flags
 	rootCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "skip startup banner")
 	rootCmd.Flags().BoolVar(&forceRefresh, "force", false, "ignore ETag/Last-Modified headers on refresh")
+	rootCmd.Flags().BoolVar(&ephemeral, "ephemeral", false, "start with an empty database in a temp file that is deleted on exit")
 	rootCmd.Flags().BoolVar(&forceRefresh, "force-refresh", false, "deprecated alias for --force")
 	_ = rootCmd.Flags().MarkDeprecated("force-refresh", "use --force")
 
@@ -174,15 +208,19 @@ func init() {
 	serviceCmd.AddCommand(serviceInstallCmd)
 	serviceCmd.AddCommand(serviceUninstallCmd)
 
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "print version, commit, Go version, OS and arch as JSON")
+
 	// Add commands
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(feedCmd)
+	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(pluginsCmd)
 	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(serviceCmd)
 	rootCmd.AddCommand(netCmd)
 	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(statsCmd)
 }
 
 var serveCmd = &cobra.Command{
@@ -190,7 +228,9 @@ var serveCmd = &cobra.Command{
 	Short: "Serve a read-only web view of stored feeds and articles",
 	Long: `serve starts an HTTP server rendering the same feeds, articles, and
 search backing the TUI. Article content is served as sanitized HTML rather
-than the lossy terminal markdown the TUI must use.
+than the lossy terminal markdown the TUI must use. Read-only JSON endpoints
+under /api (/api/feeds, /api/feeds/{id}/articles, /api/search?q=) serve the
+same data to other frontends.
 
 The web server holds the database open for its lifetime, so it cannot run
 against the same --db as a concurrent TUI (BoltDB is single-process).`,
@@ -265,12 +305,66 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
 	Run: func(_ *cobra.Command, _ []string) {
+		if versionJSON {
+			if err := printVersionJSON(os.Stdout); err != nil {
+				exitWithError(err)
+			}
+			return
+		}
 		fmt.Printf("fwrd %s\n", Version)
 		fmt.Println("RSS aggregator")
 		fmt.Println("github.com/pders01/fwrd")
 	},
 }
 
+// versionInfo is what version --json prints.
+type versionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"goVersion"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+func printVersionJSON(out io.Writer) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(versionInfo{
+		Version:   Version,
+		Commit:    buildCommit(),
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	})
+}
+
+// buildCommit returns Commit, or else the vcs.revision Go records for
+// builds from a checkout, or "unknown".
+func buildCommit() string {
+	if Commit != "" {
+		return Commit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" && s.Value != "" {
+				return s.Value
+			}
+		}
+	}
+	return "unknown"
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show feed and article statistics",
+	Long: `stats prints database-wide numbers: feed, article and unread counts, the
+database size, the oldest and newest article dates, and the feeds holding the
+most articles. Counts come from the storage indexes, so it stays fast on large
+databases.`,
+	Args: cobra.NoArgs,
+	Run:  showStats,
+}
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Configuration management",
@@ -280,8 +374,10 @@ var configGenCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generate default configuration file",
 	Run: func(_ *cobra.Command, _ []string) {
-		home, _ := os.UserHomeDir()
-		configDir := filepath.Join(home, ".config", "fwrd")
+		configDir, err := validation.ConfigDir()
+		if err != nil {
+			logger.Fatal("failed to locate config directory", "err", err)
+		}
 		configFile := filepath.Join(configDir, "config.toml")
 
 		if err := config.GenerateDefaultConfig(configFile); err != nil {
@@ -291,6 +387,18 @@ var configGenCmd = &cobra.Command{
 	},
 }
 
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Search history commands",
+}
+
+var searchClearHistoryCmd = &cobra.Command{
+	Use:   "clear-history",
+	Short: "Forget the recent searches the TUI offers for recall",
+	Args:  cobra.NoArgs,
+	Run:   clearSearchHistory,
+}
+
 var feedCmd = &cobra.Command{
 	Use:   "feed",
 	Short: "Feed management commands",
@@ -312,8 +420,120 @@ var feedAddCmd = &cobra.Command{
 var feedDeleteCmd = &cobra.Command{
 	Use:   "delete [URL or ID]",
 	Short: "Delete a feed",
-	Args:  cobra.ExactArgs(1),
-	Run:   deleteFeed,
+	Long: `delete removes one feed by URL or ID. With --match it instead removes every
+feed whose URL or title matches the regular expression, after asking for
+confirmation. More than 10 matches always require --yes.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if deleteMatch != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: deleteFeed,
+}
+
+var feedRenameCmd = &cobra.Command{
+	Use:   "rename [ID|URL] [title]",
+	Short: "Rename a feed",
+	Args:  cobra.ExactArgs(2),
+	Run:   renameFeed,
+}
+
+var feedSetIconCmd = &cobra.Command{
+	Use:   "set-icon [ID|URL] [icon]",
+	Short: "Set the emoji or symbol shown before a feed's title",
+	Long: `Set the emoji or symbol shown before a feed's title in the TUI feed list.
+Pass an empty icon ("") to go back to the default, the first letter of
+the feed's domain.`,
+	Args: cobra.ExactArgs(2),
+	Run:  setIcon,
+}
+
+var feedSetFormatCmd = &cobra.Command{
+	Use:   "set-format [ID|URL] [rss|atom|json|auto]",
+	Short: "Force the format a feed is parsed as",
+	Long: `Force the format a feed is parsed as instead of detecting it from the
+body, for feeds that detection gets wrong. "auto" (or "") goes back to
+detecting. The Content-Type a server sends is never consulted either way.`,
+	Args: cobra.ExactArgs(2),
+	Run:  setFormat,
+}
+
+var feedSetUACmd = &cobra.Command{
+	Use:   "set-ua [ID|URL] [preset]",
+	Short: "Pick the User-Agent a feed is fetched with",
+	Long: `Pick the User-Agent a feed is fetched with, by the name of an entry in
+[feed.user_agents] ("browser" and "bot" out of the box), for servers that
+answer some clients differently. "default" goes back to feed.user_agent.`,
+	Args: cobra.ExactArgs(2),
+	Run:  setUserAgent,
+}
+
+var feedTagCmd = &cobra.Command{
+	Use:   "tag [ID|URL] [tag]",
+	Short: "Label a feed with a tag",
+	Long: `Label a feed with a tag. A feed can carry any number of tags, and the
+TUI feed list can be narrowed to the feeds with one of them. Tags match
+case-insensitively, so adding one a feed already has does nothing.`,
+	Args: cobra.ExactArgs(2),
+	Run:  tagFeed,
+}
+
+var feedUntagCmd = &cobra.Command{
+	Use:   "untag [ID|URL] [tag]",
+	Short: "Remove a tag from a feed",
+	Args:  cobra.ExactArgs(2),
+	Run:   untagFeed,
+}
+
+var feedFilterCmd = &cobra.Command{
+	Use:   "filter [ID|URL]",
+	Short: "Keep only the articles of a feed that match keywords",
+	Long: `Set the keyword rules that decide which of a feed's articles are kept.
+Each rule is a case-insensitive regular expression, so a plain word
+matches anywhere in an article's title, description, content or tags.
+With --include set, only articles matching one of its rules are stored;
+an article matching any --exclude rule never is. Articles already stored
+stay.
+
+Both flags repeat, and each one given replaces that list; --clear drops
+both. With no flags the feed's current rules are shown.
+
+  fwrd feed filter <id> --include golang --include 'rust' --exclude crypto`,
+	Args: cobra.ExactArgs(1),
+	Run:  filterFeed,
+}
+
+var feedReorderCmd = &cobra.Command{
+	Use:   "reorder [ID|URL...]",
+	Short: "Pin feeds to the top of the list in the given order",
+	Long: `Pin feeds to the top of the feed list in the order given. Feeds not
+named follow, sorted by title. With no arguments the manual order is
+cleared and every feed sorts by title again.`,
+	Args: cobra.ArbitraryArgs,
+	Run:  reorderFeeds,
+}
+
+var feedInfoCmd = &cobra.Command{
+	Use:   "info [ID|URL]",
+	Short: "Show everything stored about a feed",
+	Long: `Show everything stored about one feed: its URLs, description, article
+and unread counts, fetch state (last attempt, last success, ETag,
+Last-Modified, last error), display settings, and the WebSub hubs and self
+URL it advertised when last fetched. A self URL that differs from the
+subscribed URL usually means the feed has moved.`,
+	Args: cobra.ExactArgs(1),
+	Run:  showFeedInfo,
+}
+
+var feedReparseCmd = &cobra.Command{
+	Use:   "reparse [ID|URL]",
+	Short: "Parse a feed's cached body again without refetching",
+	Long: `reparse runs the parser again over the last body fetched for a feed and
+saves the articles, without touching the network. Bodies are only kept
+while feed.cache_bodies is on, so turn it on and refresh once first.`,
+	Args: cobra.ExactArgs(1),
+	Run:  reparseFeed,
 }
 
 var feedRefreshCmd = &cobra.Command{
@@ -322,11 +542,24 @@ var feedRefreshCmd = &cobra.Command{
 	Run:   refreshFeeds,
 }
 
+var feedCheckCmd = &cobra.Command{
+	Use:   "check [ID|URL|all]",
+	Short: "Check feeds for reachability without saving anything",
+	Long: `check fetches each feed the way a refresh would (a conditional GET using
+the stored ETag/Last-Modified) and reports the HTTP status, response time,
+detected format, and how many new articles a refresh would add. Nothing is
+written to the database. With no argument, or "all", every feed is checked.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  checkFeeds,
+}
+
 var feedExportCmd = &cobra.Command{
 	Use:   "export [path]",
 	Short: "Export feeds to an OPML file",
 	Long: `export writes all stored feeds to an OPML 2.0 file other readers can
-import. Pass "-" as the path to write to stdout.`,
+import. Tagged feeds are nested under a category outline per tag, which
+other readers import as folders; untagged feeds are listed at the top
+level. Pass "-" as the path to write to stdout.`,
 	Args: cobra.ExactArgs(1),
 	Run:  exportFeeds,
 }
@@ -335,13 +568,24 @@ var feedImportCmd = &cobra.Command{
 	Use:   "import [path]",
 	Short: "Import feeds from an OPML file",
 	Long: `import reads an OPML file and adds each listed feed, fetching it once
-so its articles are available immediately. Feeds that are already present or
+so its articles are available immediately. The categories (folders) a feed
+is filed under become its tags. Feeds that are already present or
 fail to fetch are reported and skipped; the rest still import. Pass "-" to
 read from stdin.`,
 	Args: cobra.ExactArgs(1),
 	Run:  importFeeds,
 }
 
+var feedImportURLsCmd = &cobra.Command{
+	Use:   "import-urls [path]",
+	Short: "Import feeds from a plain list of URLs",
+	Long: `import-urls reads one feed URL per line and adds each, like import does
+for OPML. Blank lines and lines starting with # are ignored. Pass "-" to
+read from stdin.`,
+	Args: cobra.ExactArgs(1),
+	Run:  importURLList,
+}
+
 var pluginsCmd = &cobra.Command{
 	Use:   "plugins",
 	Short: "Inspect installed plugins",
@@ -358,26 +602,47 @@ func init() {
 	feedCmd.AddCommand(feedListCmd)
 	feedCmd.AddCommand(feedAddCmd)
 	feedCmd.AddCommand(feedDeleteCmd)
+	feedCmd.AddCommand(feedRenameCmd)
+	feedCmd.AddCommand(feedSetIconCmd)
+	feedCmd.AddCommand(feedSetFormatCmd)
+	feedCmd.AddCommand(feedSetUACmd)
+	feedCmd.AddCommand(feedTagCmd)
+	feedCmd.AddCommand(feedUntagCmd)
+	feedCmd.AddCommand(feedFilterCmd)
+	feedCmd.AddCommand(feedReorderCmd)
+	feedCmd.AddCommand(feedInfoCmd)
+	feedCmd.AddCommand(feedReparseCmd)
 	feedCmd.AddCommand(feedRefreshCmd)
+	feedCmd.AddCommand(feedCheckCmd)
 	feedCmd.AddCommand(feedExportCmd)
 	feedCmd.AddCommand(feedImportCmd)
+	feedCmd.AddCommand(feedImportURLsCmd)
 	pluginsCmd.AddCommand(pluginsListCmd)
+	searchCmd.AddCommand(searchClearHistoryCmd)
 
 	// Add force flag to refresh command (with a deprecated alias matching
 	// the root TUI flag, so the same name works in both contexts).
 	feedRefreshCmd.Flags().BoolVar(&forceRefresh, "force", false, "ignore ETag/Last-Modified headers")
 	feedRefreshCmd.Flags().BoolVar(&forceRefresh, "force-refresh", false, "deprecated alias for --force")
 	_ = feedRefreshCmd.Flags().MarkDeprecated("force-refresh", "use --force")
+	feedCheckCmd.Flags().BoolVar(&forceRefresh, "force", false, "ignore ETag/Last-Modified headers")
+	feedAddCmd.Flags().BoolVar(&forceRefresh, "force", false, "re-add a feed that is already subscribed")
+	feedDeleteCmd.Flags().StringVar(&deleteMatch, "match", "", "delete every feed whose URL or title matches this regular expression")
+	feedDeleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "delete matches without asking")
+	feedFilterCmd.Flags().StringArrayVar(&filterInclude, "include", nil, "keep only articles matching this keyword or pattern (repeatable)")
+	feedFilterCmd.Flags().StringArrayVar(&filterExclude, "exclude", nil, "drop articles matching this keyword or pattern (repeatable)")
+	feedFilterCmd.Flags().BoolVar(&filterClear, "clear", false, "remove every include and exclude rule")
+	statsCmd.Flags().IntVarP(&statsTop, "top", "n", 10, "number of feeds to list by article count (0 hides the list)")
 }
 
 func initConfig() {
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
 	} else {
-		home, err := os.UserHomeDir()
+		configDir, err := validation.ConfigDir()
 		cobra.CheckErr(err)
 
-		viper.AddConfigPath(filepath.Join(home, ".config", "fwrd"))
+		viper.AddConfigPath(configDir)
 		viper.AddConfigPath(".")
 		viper.SetConfigName("config")
 		viper.SetConfigType("toml")
@@ -385,9 +650,69 @@ func initConfig() {
 
 	viper.SetEnvPrefix("FWRD")
 	viper.AutomaticEnv()
+
+	// NO_COLOR disables color whenever it is set to anything non-empty
+	// (https://no-color.org).
+	if os.Getenv("NO_COLOR") != "" {
+		noColor = true
+	}
+	if noColor {
+		disableColor()
+	}
+
+	if env, err := strconv.ParseBool(os.Getenv("FWRD_PERMISSIVE")); err == nil && env {
+		permissive = true
+	}
+	if permissive {
+		logger.Warn("permissive mode: localhost and private-network feed URLs are allowed; SSRF protection is relaxed")
+	}
+
+	cobra.CheckErr(setupLogging())
+}
+
+// disableColor switches lipgloss and the CLI logger to the plain ASCII
+// profile, so the banner, styled CLI output and the TUI emit no color
+// escape codes.
+func disableColor() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	logger.SetColorProfile(termenv.Ascii)
+}
+
+// setupLogging opens ~/.fwrd/fwrd.log at the --log-level threshold, or at
+// debug level for a bare --debug. Without either flag nothing is logged.
+func setupLogging() error {
+	switch {
+	case logLevel != "":
+		level, ok := debuglog.LookupLogLevel(logLevel)
+		if !ok {
+			return fmt.Errorf("invalid --log-level %q: want debug, info, warn, error or off", logLevel)
+		}
+		return debuglog.Setup(level)
+	case debugFlag:
+		return debuglog.Setup(debuglog.LevelDebug)
+	}
+	return nil
+}
+
+// newManager builds a feed manager honouring --permissive.
+func newManager(store *storage.Store, cfg *config.Config) *feed.Manager {
+	manager := feed.NewManager(store, cfg)
+	manager.SetPermissiveValidation(permissive)
+	return manager
 }
 
 func loadConfig() (*config.Config, error) {
+	if migrateConfig {
+		file, from, err := config.Migrate(cfgFile)
+		if err != nil {
+			return nil, err
+		}
+		if file != "" {
+			fmt.Fprintf(os.Stderr, "fwrd: migrated %s from config version %d to %d (original saved as %s.bak)\n", file, from, config.CurrentVersion, file)
+		}
+		// Only the first load in a process needs to rewrite the file.
+		migrateConfig = false
+	}
 	cfg, err := config.Load(cfgFile)
 	if err != nil {
 		return nil, err
@@ -399,6 +724,14 @@ func loadConfig() (*config.Config, error) {
 	if dbPath != "" {
 		cfg.Database.SearchIndex = deriveIndexPath(dbPath)
 	}
+	// --ephemeral leaves nothing behind: the database is a temp file, the
+	// TUI gives a MemoryPath database a throwaway index, and no feed
+	// bodies are cached.
+	if ephemeral {
+		cfg.Database.Path = storage.MemoryPath
+		cfg.Database.SearchIndex = ""
+		cfg.Feed.CacheBodies = false
+	}
 	return cfg, nil
 }
 
@@ -414,6 +747,10 @@ func deriveIndexPath(dbFilePath string) string {
 }
 
 func getStore(cfg *config.Config) (*storage.Store, error) {
+	if ephemeral {
+		return storage.NewTempStore()
+	}
+
 	// Override database path if provided via flag
 	dbFilePath := cfg.Database.Path
 	if dbPath != "" {
@@ -473,11 +810,6 @@ func runTUI(_ *cobra.Command, _ []string) {
 		tui.ShowBanner(Version)
 	}
 
-	// Setup debug logging if requested
-	if debugFlag {
-		debuglog.SetupWithBool(true)
-	}
-
 	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
 		for _, w := range config.Warnings(cfg) {
 			logger.Warn(w)
@@ -489,6 +821,15 @@ func runTUI(_ *cobra.Command, _ []string) {
 		if forceRefresh {
 			app.SetForceRefresh(true)
 		}
+		if printOpen {
+			app.SetPrintOpen(true)
+		}
+		if permissive {
+			app.SetPermissiveValidation(true)
+		}
+		if noColor {
+			app.SetNoColor(true)
+		}
 
 		p := tea.NewProgram(app, tea.WithAltScreen())
 
@@ -519,14 +860,10 @@ func buildSearcher(store *storage.Store, cfg *config.Config) (search.Searcher, e
 	if errors.Is(err, search.ErrIndexLocked) {
 		return nil, err
 	}
-	return search.NewEngine(store), nil
+	return search.NewEngine(store, cfg.Search.ScanLimit), nil
 }
 
 func runServe(cmd *cobra.Command, _ []string) {
-	if debugFlag {
-		debuglog.SetupWithBool(true)
-	}
-
 	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
 		for _, w := range config.Warnings(cfg) {
 			logger.Warn(w)
@@ -539,7 +876,7 @@ func runServe(cmd *cobra.Command, _ []string) {
 
 		// Wire the manager exactly as the TUI does so feeds added or
 		// refreshed via the web UI are indexed for search.
-		manager := feed.NewManager(store, cfg)
+		manager := newManager(store, cfg)
 		loadLuaPlugins(manager)
 		if dl, ok := searcher.(feed.DataListener); ok {
 			manager.RegisterDataListener(dl)
@@ -1016,6 +1353,11 @@ func exitWithError(err error) {
 		fmt.Fprintln(os.Stderr, "Hint: close the other instance, or pass --db to use a different file (the index follows it).")
 		os.Exit(1)
 	}
+	if errors.Is(err, storage.ErrFeedNotFound) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Hint: run `fwrd feed list` to see subscribed feeds and their IDs.")
+		os.Exit(1)
+	}
 	if errors.Is(err, syscall.EADDRINUSE) {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		fmt.Fprintln(os.Stderr, "Hint: another process is already on that port. Pick a free one with --addr, "+
@@ -1027,7 +1369,7 @@ func exitWithError(err error) {
 }
 
 func listFeeds(_ *cobra.Command, _ []string) {
-	if err := withStore(func(store *storage.Store) error {
+	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
 		feeds, err := store.GetAllFeeds()
 		if err != nil {
 			return fmt.Errorf("failed to get feeds: %w", err)
@@ -1038,17 +1380,20 @@ func listFeeds(_ *cobra.Command, _ []string) {
 			return nil
 		}
 
+		timeLayout := cfg.UI.TimeLayout("2006-01-02 15:04:05")
 		fmt.Printf("Found %d feeds:\n\n", len(feeds))
 		for _, feed := range feeds {
 			fmt.Printf("Title: %s\n", feed.Title)
 			fmt.Printf("URL:   %s\n", feed.URL)
 			fmt.Printf("ID:    %s\n", feed.ID)
 
-			// Get article count
-			articles, _ := store.GetArticles(feed.ID, 0)
-			fmt.Printf("Articles: %d\n", len(articles))
+			count, err := store.CountArticles(feed.ID)
+			if err != nil {
+				return fmt.Errorf("failed to count articles: %w", err)
+			}
+			fmt.Printf("Articles: %d\n", count)
 
-			fmt.Printf("Last Fetched: %s\n", feed.LastFetched.Format("2006-01-02 15:04:05"))
+			fmt.Printf("Last Fetched: %s\n", feed.LastSuccess.Format(timeLayout))
 			if feed.ETag != "" {
 				fmt.Printf("ETag: %s\n", feed.ETag)
 			}
@@ -1067,11 +1412,17 @@ func addFeed(_ *cobra.Command, args []string) {
 	url := args[0]
 
 	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
-		manager := feed.NewManager(store, cfg)
+		manager := newManager(store, cfg)
 		loadLuaPlugins(manager)
 
+		manager.SetAllowResubscribe(forceRefresh)
+
+		var subscribed *feed.AlreadySubscribedError
 		fmt.Printf("Adding feed: %s\n", url)
 		feed, err := manager.AddFeed(url)
+		if errors.As(err, &subscribed) {
+			return fmt.Errorf("%w (ID %s); use --force to fetch and save it again", err, subscribed.Feed.ID)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to add feed: %w", err)
 		}
@@ -1089,41 +1440,552 @@ func addFeed(_ *cobra.Command, args []string) {
 	}
 }
 
+// findFeed looks a feed up by its ID or URL.
+func findFeed(store *storage.Store, urlOrID string) (*storage.Feed, error) {
+	feeds, err := store.GetAllFeeds()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feeds: %w", err)
+	}
+	for _, feed := range feeds {
+		if feed.ID == urlOrID || feed.URL == urlOrID {
+			return feed, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", storage.ErrFeedNotFound, urlOrID)
+}
+
+// bulkDeleteLimit is the most feeds delete --match removes on an
+// interactive yes; anything broader must be confirmed up front with --yes
+// so a loose pattern can't wipe the list by a stray keypress.
+const bulkDeleteLimit = 10
+
 func deleteFeed(_ *cobra.Command, args []string) {
+	if deleteMatch != "" {
+		deleteMatchingFeeds()
+		return
+	}
 	urlOrID := args[0]
 
 	if err := withStore(func(store *storage.Store) error {
-		// Find feed by URL or ID
+		targetFeed, err := findFeed(store, urlOrID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleting feed: %s (%s)\n", targetFeed.Title, targetFeed.URL)
+
+		if err := store.DeleteFeed(targetFeed.ID); err != nil {
+			return fmt.Errorf("failed to delete feed: %w", err)
+		}
+
+		fmt.Println("Feed deleted successfully.")
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
+
+func deleteMatchingFeeds() {
+	re, err := regexp.Compile(deleteMatch)
+	if err != nil {
+		exitWithError(fmt.Errorf("invalid --match pattern: %w", err))
+	}
+	if err := withStore(func(store *storage.Store) error {
 		feeds, err := store.GetAllFeeds()
 		if err != nil {
 			return fmt.Errorf("failed to get feeds: %w", err)
 		}
+		matches := matchFeeds(feeds, re)
+		if len(matches) == 0 {
+			fmt.Println("No feeds match.")
+			return nil
+		}
+		if !confirmBulkDelete(os.Stdin, os.Stdout, matches, deleteYes) {
+			fmt.Println("Aborted; nothing deleted.")
+			return nil
+		}
+		for _, f := range matches {
+			if err := store.DeleteFeed(f.ID); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", f.URL, err)
+			}
+		}
+		fmt.Printf("Deleted %d feed(s).\n", len(matches))
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
 
-		var targetFeed *storage.Feed
-		for _, feed := range feeds {
-			if feed.ID == urlOrID || feed.URL == urlOrID {
-				targetFeed = feed
-				break
+// matchFeeds returns the feeds whose URL or title matches re.
+func matchFeeds(feeds []*storage.Feed, re *regexp.Regexp) []*storage.Feed {
+	var matches []*storage.Feed
+	for _, f := range feeds {
+		if re.MatchString(f.URL) || re.MatchString(f.Title) {
+			matches = append(matches, f)
+		}
+	}
+	return matches
+}
+
+// confirmBulkDelete lists matches on out and reports whether they may be
+// deleted. yes skips the question; without it, more than bulkDeleteLimit
+// matches are refused outright and fewer need a "y" answer on in.
+func confirmBulkDelete(in io.Reader, out io.Writer, matches []*storage.Feed, yes bool) bool {
+	for _, f := range matches {
+		fmt.Fprintf(out, "  %s  %s\n", f.Title, f.URL)
+	}
+	if yes {
+		return true
+	}
+	if len(matches) > bulkDeleteLimit {
+		fmt.Fprintf(out, "%d feeds match; pass --yes to delete more than %d at once.\n", len(matches), bulkDeleteLimit)
+		return false
+	}
+	fmt.Fprintf(out, "Delete %d feed(s)? [y/N] ", len(matches))
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func renameFeed(_ *cobra.Command, args []string) {
+	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
+		f, err := setFeedTitle(store, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Renamed feed %s to %q\n", f.ID, f.Title)
+
+		// Keep the index's copy of the feed title in step. A TUI or
+		// server holding the index lock will pick the new title up the
+		// next time it reindexes.
+		searcher, err := buildSearcher(store, cfg)
+		if err != nil {
+			logger.Warn("search index not updated", "err", err)
+			return nil
+		}
+		if ul, ok := searcher.(search.UpdateListener); ok {
+			ul.OnDataUpdated(f, nil)
+		}
+		if c, ok := searcher.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				logger.Warn("closing search index", "err", err)
 			}
 		}
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
 
-		if targetFeed == nil {
-			return fmt.Errorf("feed not found: %s", urlOrID)
+// setFeedTitle sets the title of the feed identified by urlOrID. Like the
+// TUI's rename, surrounding whitespace is dropped and an empty title is
+// rejected.
+func setFeedTitle(store *storage.Store, urlOrID, title string) (*storage.Feed, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("title cannot be empty")
+	}
+	f, err := findFeed(store, urlOrID)
+	if err != nil {
+		return nil, err
+	}
+	f.Title = title
+	f.UpdatedAt = time.Now()
+	if err := store.SaveFeed(f); err != nil {
+		return nil, fmt.Errorf("failed to save feed: %w", err)
+	}
+	return f, nil
+}
+
+func setIcon(_ *cobra.Command, args []string) {
+	if err := withStore(func(store *storage.Store) error {
+		f, err := setFeedIcon(store, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		if f.Icon == "" {
+			fmt.Printf("Cleared icon for feed %s\n", f.ID)
+		} else {
+			fmt.Printf("Set icon for feed %s to %s\n", f.ID, f.Icon)
 		}
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
 
-		fmt.Printf("Deleting feed: %s (%s)\n", targetFeed.Title, targetFeed.URL)
+// maxFeedIconRunes bounds a feed icon. It leaves room for emoji built
+// from several code points (skin tones, ZWJ sequences) while keeping
+// words out of the feed list's prefix column.
+const maxFeedIconRunes = 8
 
-		if err := store.DeleteFeed(targetFeed.ID); err != nil {
-			return fmt.Errorf("failed to delete feed: %w", err)
+// setFeedIcon sets the icon of the feed identified by urlOrID. An empty
+// icon clears it.
+func setFeedIcon(store *storage.Store, urlOrID, icon string) (*storage.Feed, error) {
+	icon = strings.TrimSpace(icon)
+	if utf8.RuneCountInString(icon) > maxFeedIconRunes || strings.ContainsFunc(icon, unicode.IsSpace) {
+		return nil, fmt.Errorf("icon %q must be a single emoji or symbol", icon)
+	}
+	f, err := findFeed(store, urlOrID)
+	if err != nil {
+		return nil, err
+	}
+	f.Icon = icon
+	f.UpdatedAt = time.Now()
+	if err := store.SaveFeed(f); err != nil {
+		return nil, fmt.Errorf("failed to save feed: %w", err)
+	}
+	return f, nil
+}
+
+func tagFeed(_ *cobra.Command, args []string) {
+	if err := withStore(func(store *storage.Store) error {
+		f, err := setFeedTag(store, args[0], args[1], true)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Feed %s is tagged %s\n", f.ID, strings.Join(f.Tags, ", "))
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
+
+func untagFeed(_ *cobra.Command, args []string) {
+	if err := withStore(func(store *storage.Store) error {
+		f, err := setFeedTag(store, args[0], args[1], false)
+		if err != nil {
+			return err
 		}
+		if len(f.Tags) == 0 {
+			fmt.Printf("Feed %s has no tags\n", f.ID)
+		} else {
+			fmt.Printf("Feed %s is tagged %s\n", f.ID, strings.Join(f.Tags, ", "))
+		}
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
 
-		fmt.Println("Feed deleted successfully.")
+// setFeedTag adds tag to, or with add false removes it from, the feed
+// identified by urlOrID. Tags compare case-insensitively; an added tag
+// keeps the case it was given.
+func setFeedTag(store *storage.Store, urlOrID, tag string, add bool) (*storage.Feed, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || strings.ContainsFunc(tag, unicode.IsControl) {
+		return nil, fmt.Errorf("tag %q must be non-empty text", tag)
+	}
+	f, err := findFeed(store, urlOrID)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(f.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+	switch {
+	case add && i < 0:
+		f.Tags = append(f.Tags, tag)
+	case !add && i >= 0:
+		f.Tags = slices.Delete(f.Tags, i, i+1)
+	case add:
+		return f, nil
+	default:
+		return nil, fmt.Errorf("feed %s is not tagged %q", f.ID, tag)
+	}
+	f.UpdatedAt = time.Now()
+	if err := store.SaveFeed(f); err != nil {
+		return nil, fmt.Errorf("failed to save feed: %w", err)
+	}
+	return f, nil
+}
+
+func setFormat(_ *cobra.Command, args []string) {
+	if err := withStore(func(store *storage.Store) error {
+		f, err := setFeedFormat(store, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		if f.ForceFormat == "" {
+			fmt.Printf("Feed %s will be parsed as whatever format it looks like\n", f.ID)
+		} else {
+			fmt.Printf("Feed %s will be parsed as %s\n", f.ID, f.ForceFormat)
+		}
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
+
+// setFeedFormat sets the ForceFormat of the feed identified by urlOrID.
+// "auto" or an empty format clears it.
+func setFeedFormat(store *storage.Store, urlOrID, format string) (*storage.Feed, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "auto" {
+		format = ""
+	}
+	if format != "" && !slices.Contains(feed.FeedFormats, format) {
+		return nil, fmt.Errorf("unknown format %q: want %s or auto", format, strings.Join(feed.FeedFormats, ", "))
+	}
+	f, err := findFeed(store, urlOrID)
+	if err != nil {
+		return nil, err
+	}
+	f.ForceFormat = format
+	f.UpdatedAt = time.Now()
+	if err := store.SaveFeed(f); err != nil {
+		return nil, fmt.Errorf("failed to save feed: %w", err)
+	}
+	return f, nil
+}
+
+func showFeedInfo(_ *cobra.Command, args []string) {
+	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
+		f, err := findFeed(store, args[0])
+		if err != nil {
+			return err
+		}
+		var stat storage.FeedStat
+		if stat.Total, err = store.CountArticles(f.ID); err != nil {
+			return fmt.Errorf("failed to count articles: %w", err)
+		}
+		if stat.Unread, err = store.CountUnread(f.ID); err != nil {
+			return fmt.Errorf("failed to count unread articles: %w", err)
+		}
+		printFeedInfo(os.Stdout, f, stat, cfg.UI.TimeLayout("2006-01-02 15:04:05"))
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
+
+// printFeedInfo renders everything stored about one feed. Unset fields
+// are spelled out rather than left blank so the report reads the same
+// for every feed.
+func printFeedInfo(out io.Writer, f *storage.Feed, stat storage.FeedStat, timeLayout string) {
+	orNone := func(s string) string { return firstNonEmpty(s, "none") }
+	when := func(t time.Time) string {
+		if t.IsZero() {
+			return "never"
+		}
+		return t.Local().Format(timeLayout)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", f.ID)
+	fmt.Fprintf(w, "Title:\t%s\n", orNone(f.Title))
+	fmt.Fprintf(w, "URL:\t%s\n", f.URL)
+	fmt.Fprintf(w, "Site:\t%s\n", orNone(f.SiteURL))
+	fmt.Fprintf(w, "Description:\t%s\n", orNone(strings.Join(strings.Fields(f.Description), " ")))
+	fmt.Fprintf(w, "Articles:\t%d\n", stat.Total)
+	fmt.Fprintf(w, "Unread:\t%d\n", stat.Unread)
+	fmt.Fprintf(w, "Last attempt:\t%s\n", when(f.LastAttempt))
+	fmt.Fprintf(w, "Last success:\t%s\n", when(f.LastSuccess))
+	fmt.Fprintf(w, "ETag:\t%s\n", orNone(f.ETag))
+	fmt.Fprintf(w, "Last-Modified:\t%s\n", orNone(f.LastModified))
+	if f.LastError != "" {
+		fmt.Fprintf(w, "Last error:\t%s (%s)\n", f.LastError, when(f.LastErrorAt))
+	} else {
+		fmt.Fprintf(w, "Last error:\t%s\n", "none")
+	}
+	fmt.Fprintf(w, "Format:\t%s\n", firstNonEmpty(f.ForceFormat, "auto"))
+	fmt.Fprintf(w, "User-Agent:\t%s\n", firstNonEmpty(f.UserAgentPreset, "default"))
+	fmt.Fprintf(w, "Icon:\t%s\n", orNone(f.Icon))
+	fmt.Fprintf(w, "Tags:\t%s\n", orNone(strings.Join(f.Tags, ", ")))
+	fmt.Fprintf(w, "Pinned:\t%t\n", f.Pinned)
+	fmt.Fprintf(w, "Reading order:\t%s\n", firstNonEmpty(f.ReadingOrder, storage.ReadingOrderNewest))
+	if len(f.Include) > 0 || len(f.Exclude) > 0 {
+		fmt.Fprintf(w, "Include:\t%s\n", orNone(strings.Join(f.Include, ", ")))
+		fmt.Fprintf(w, "Exclude:\t%s\n", orNone(strings.Join(f.Exclude, ", ")))
+	}
+	if f.Order > 0 {
+		fmt.Fprintf(w, "Manual order:\t%d\n", f.Order)
+	}
+	fmt.Fprintf(w, "Self:\t%s\n", firstNonEmpty(f.SelfURL, "none advertised"))
+	if len(f.HubURLs) == 0 {
+		fmt.Fprintf(w, "Hubs:\t%s\n", "none advertised")
+	}
+	for i, hub := range f.HubURLs {
+		label := ""
+		if i == 0 {
+			label = "Hubs:"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", label, hub)
+	}
+	_ = w.Flush()
+	if f.SelfURL != "" && f.SelfURL != f.URL {
+		fmt.Fprintf(out, "\nThe feed gives its own URL as %s; it may have moved.\n", f.SelfURL)
+	}
+}
+
+func setUserAgent(_ *cobra.Command, args []string) {
+	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
+		f, err := setFeedUserAgent(store, &cfg.Feed, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		if f.UserAgentPreset == "" {
+			fmt.Printf("Feed %s will send the default User-Agent\n", f.ID)
+		} else {
+			fmt.Printf("Feed %s will send the %s User-Agent: %s\n", f.ID, f.UserAgentPreset, cfg.Feed.UserAgents[f.UserAgentPreset])
+		}
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
+
+// setFeedUserAgent sets the UserAgentPreset of the feed identified by
+// urlOrID. "default" or an empty preset clears it.
+func setFeedUserAgent(store *storage.Store, cfg *config.FeedConfig, urlOrID, preset string) (*storage.Feed, error) {
+	preset = strings.ToLower(strings.TrimSpace(preset))
+	if preset == "default" {
+		preset = ""
+	}
+	if _, ok := cfg.UserAgents[preset]; preset != "" && !ok {
+		names := slices.Sorted(maps.Keys(cfg.UserAgents))
+		return nil, fmt.Errorf("unknown user agent preset %q: want default or one of %s", preset, strings.Join(names, ", "))
+	}
+	f, err := findFeed(store, urlOrID)
+	if err != nil {
+		return nil, err
+	}
+	f.UserAgentPreset = preset
+	f.UpdatedAt = time.Now()
+	if err := store.SaveFeed(f); err != nil {
+		return nil, fmt.Errorf("failed to save feed: %w", err)
+	}
+	return f, nil
+}
+
+func clearSearchHistory(_ *cobra.Command, _ []string) {
+	if err := withStore(func(store *storage.Store) error {
+		if err := store.ClearSearchHistory(); err != nil {
+			return fmt.Errorf("failed to clear search history: %w", err)
+		}
+		fmt.Println("Cleared search history")
 		return nil
 	}); err != nil {
 		exitWithError(err)
 	}
 }
 
+func filterFeed(cmd *cobra.Command, args []string) {
+	if err := withStore(func(store *storage.Store) error {
+		f, err := findFeed(store, args[0])
+		if err != nil {
+			return err
+		}
+		include, exclude := f.Include, f.Exclude
+		switch {
+		case filterClear:
+			include, exclude = nil, nil
+		case cmd.Flags().Changed("include") || cmd.Flags().Changed("exclude"):
+			if cmd.Flags().Changed("include") {
+				include = filterInclude
+			}
+			if cmd.Flags().Changed("exclude") {
+				exclude = filterExclude
+			}
+		default:
+			printFeedFilter(f)
+			return nil
+		}
+		if f, err = setFeedFilter(store, f.ID, include, exclude); err != nil {
+			return err
+		}
+		printFeedFilter(f)
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
+
+func printFeedFilter(f *storage.Feed) {
+	if len(f.Include) == 0 && len(f.Exclude) == 0 {
+		fmt.Printf("Feed %s keeps every article\n", f.ID)
+		return
+	}
+	fmt.Printf("Include: %s\n", firstNonEmpty(strings.Join(f.Include, ", "), "none"))
+	fmt.Printf("Exclude: %s\n", firstNonEmpty(strings.Join(f.Exclude, ", "), "none"))
+}
+
+// setFeedFilter replaces the Include and Exclude rules of the feed
+// identified by urlOrID. Blank rules are dropped; a rule that is not a
+// valid pattern is an error and nothing is saved.
+func setFeedFilter(store *storage.Store, urlOrID string, include, exclude []string) (*storage.Feed, error) {
+	clean := func(flag string, rules []string) ([]string, error) {
+		var out []string
+		for _, rule := range rules {
+			rule = strings.TrimSpace(rule)
+			if rule == "" {
+				continue
+			}
+			if _, err := storage.CompileFilterRule(rule); err != nil {
+				return nil, fmt.Errorf("invalid --%s rule %q: %w", flag, rule, err)
+			}
+			out = append(out, rule)
+		}
+		return out, nil
+	}
+	include, err := clean("include", include)
+	if err != nil {
+		return nil, err
+	}
+	if exclude, err = clean("exclude", exclude); err != nil {
+		return nil, err
+	}
+	f, err := findFeed(store, urlOrID)
+	if err != nil {
+		return nil, err
+	}
+	f.Include, f.Exclude = include, exclude
+	f.UpdatedAt = time.Now()
+	if err := store.SaveFeed(f); err != nil {
+		return nil, fmt.Errorf("failed to save feed: %w", err)
+	}
+	return f, nil
+}
+
+func reorderFeeds(_ *cobra.Command, args []string) {
+	if err := withStore(func(store *storage.Store) error {
+		if err := setFeedOrder(store, args); err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			fmt.Println("Cleared manual feed order")
+		} else {
+			fmt.Printf("Pinned %d feed(s) to the top of the list\n", len(args))
+		}
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
+
+// setFeedOrder resolves each URL or ID and stores them as the manual
+// feed order.
+func setFeedOrder(store *storage.Store, urlOrIDs []string) error {
+	ids := make([]string, 0, len(urlOrIDs))
+	seen := make(map[string]bool, len(urlOrIDs))
+	for _, urlOrID := range urlOrIDs {
+		f, err := findFeed(store, urlOrID)
+		if err != nil {
+			return err
+		}
+		if seen[f.ID] {
+			return fmt.Errorf("feed %s listed twice", urlOrID)
+		}
+		seen[f.ID] = true
+		ids = append(ids, f.ID)
+	}
+	if err := store.SetFeedOrder(ids); err != nil {
+		return fmt.Errorf("failed to save feed order: %w", err)
+	}
+	return nil
+}
+
 func exportFeeds(_ *cobra.Command, args []string) {
 	path := args[0]
 	if err := withStore(func(store *storage.Store) error {
@@ -1152,15 +2014,9 @@ func exportFeeds(_ *cobra.Command, args []string) {
 func importFeeds(_ *cobra.Command, args []string) {
 	path := args[0]
 	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
-		var data []byte
-		var err error
-		if path == "-" {
-			data, err = io.ReadAll(os.Stdin)
-		} else {
-			data, err = os.ReadFile(path)
-		}
+		data, err := readImportInput(path)
 		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", path, err)
+			return err
 		}
 
 		feeds, err := opml.Parse(bytes.NewReader(data))
@@ -1172,38 +2028,116 @@ func importFeeds(_ *cobra.Command, args []string) {
 			return nil
 		}
 
-		manager := feed.NewManager(store, cfg)
-		loadLuaPlugins(manager)
-
-		// Snapshot existing URLs so already-subscribed feeds are skipped
-		// rather than re-fetched.
-		existing, _ := store.GetAllFeeds()
-		have := make(map[string]bool, len(existing))
-		for _, f := range existing {
-			have[f.URL] = true
+		urls := make([]string, len(feeds))
+		tags := make(map[string][]string)
+		for i, f := range feeds {
+			urls[i] = f.URL
+			if len(f.Categories) > 0 {
+				tags[f.URL] = f.Categories
+			}
 		}
+		importFeedURLs(store, cfg, urls, tags)
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
 
-		var added, skipped, failed int
-		for _, f := range feeds {
-			if have[f.URL] {
-				skipped++
-				continue
-			}
-			fmt.Printf("Adding %s\n", f.URL)
-			if _, err := manager.AddFeed(f.URL); err != nil {
-				fmt.Fprintf(os.Stderr, "  failed: %v\n", err)
-				failed++
-				continue
-			}
-			added++
+func importURLList(_ *cobra.Command, args []string) {
+	path := args[0]
+	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
+		data, err := readImportInput(path)
+		if err != nil {
+			return err
+		}
+		urls := parseURLList(string(data))
+		if len(urls) == 0 {
+			fmt.Println("No feed URLs found.")
+			return nil
 		}
-		fmt.Printf("Imported %d feed(s); %d skipped (already present); %d failed.\n", added, skipped, failed)
+		importFeedURLs(store, cfg, urls, nil)
 		return nil
 	}); err != nil {
 		exitWithError(err)
 	}
 }
 
+// readImportInput reads an import file, or stdin when path is "-".
+func readImportInput(path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// parseURLList returns the URLs in a newline-delimited list, skipping
+// blank lines and lines starting with #.
+func parseURLList(data string) []string {
+	var urls []string
+	for line := range strings.Lines(data) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	return urls
+}
+
+// importFeedURLs adds each URL as a feed, fetching it once, and prints a
+// line per feed plus a summary. Feeds already subscribed are skipped and
+// failures reported without stopping the rest. tags, keyed by URL, are
+// set on the feeds that get added; it may be nil.
+func importFeedURLs(store *storage.Store, cfg *config.Config, urls []string, tags map[string][]string) {
+	manager := newManager(store, cfg)
+	loadLuaPlugins(manager)
+
+	// Snapshot existing URLs so already-subscribed feeds are skipped
+	// rather than re-fetched.
+	existing, _ := store.GetAllFeeds()
+	have := make(map[string]bool, len(existing))
+	for _, f := range existing {
+		have[f.URL] = true
+	}
+
+	var added, skipped, failed int
+	for _, u := range urls {
+		if have[u] {
+			skipped++
+			continue
+		}
+		have[u] = true
+		fmt.Printf("Adding %s\n", u)
+		f, err := manager.AddFeed(u)
+		var subscribed *feed.AlreadySubscribedError
+		if errors.As(err, &subscribed) {
+			fmt.Printf("  already subscribed as %s\n", subscribed.Feed.URL)
+			skipped++
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  failed: %v\n", err)
+			failed++
+			continue
+		}
+		added++
+		if len(tags[u]) > 0 {
+			f.Tags = tags[u]
+			if err := store.SaveFeed(f); err != nil {
+				fmt.Fprintf(os.Stderr, "  failed to save tags: %v\n", err)
+			}
+		}
+	}
+	fmt.Printf("Imported %d feed(s); %d skipped (already present); %d failed.\n", added, skipped, failed)
+}
+
 func listPlugins(_ *cobra.Command, _ []string) {
 	cfg, err := loadConfig()
 	if err != nil {
@@ -1241,7 +2175,7 @@ func listPlugins(_ *cobra.Command, _ []string) {
 
 func refreshFeeds(_ *cobra.Command, _ []string) {
 	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
-		manager := feed.NewManager(store, cfg)
+		manager := newManager(store, cfg)
 		loadLuaPlugins(manager)
 
 		// Set force refresh if requested
@@ -1264,6 +2198,170 @@ func refreshFeeds(_ *cobra.Command, _ []string) {
 	}
 }
 
+func reparseFeed(_ *cobra.Command, args []string) {
+	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
+		f, err := findFeed(store, args[0])
+		if err != nil {
+			return err
+		}
+		saved, err := newManager(store, cfg).ReparseFeed(f.ID)
+		if errors.Is(err, feed.ErrNoCachedBody) {
+			return fmt.Errorf("%w %s; set feed.cache_bodies = true and refresh it first", err, f.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reparse feed: %w", err)
+		}
+		fmt.Printf("Reparsed %s: %d new or changed article(s).\n", firstNonEmpty(f.Title, f.URL), len(saved))
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
+
+func checkFeeds(_ *cobra.Command, args []string) {
+	target := "all"
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	if err := withStoreAndConfig(func(store *storage.Store, cfg *config.Config) error {
+		manager := newManager(store, cfg)
+		manager.SetForceRefresh(forceRefresh)
+
+		var checks []feed.FeedCheck
+		if target == "all" {
+			var err error
+			if checks, err = manager.CheckAllFeeds(context.Background()); err != nil {
+				return fmt.Errorf("failed to check feeds: %w", err)
+			}
+		} else {
+			feeds, err := store.GetAllFeeds()
+			if err != nil {
+				return fmt.Errorf("failed to get feeds: %w", err)
+			}
+			id := ""
+			for _, f := range feeds {
+				if f.ID == target || f.URL == target {
+					id = f.ID
+					break
+				}
+			}
+			if id == "" {
+				return fmt.Errorf("%w: %s", storage.ErrFeedNotFound, target)
+			}
+			check, err := manager.CheckFeed(context.Background(), id)
+			if err != nil {
+				return fmt.Errorf("failed to check feed: %w", err)
+			}
+			checks = []feed.FeedCheck{check}
+		}
+
+		if len(checks) == 0 {
+			fmt.Println("No feeds found.")
+			return nil
+		}
+
+		failed := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STATUS\tTIME\tFORMAT\tARTICLES\tNEW\tFEED\tERROR")
+		for _, c := range checks {
+			status := "error"
+			if c.Status != 0 {
+				status = strconv.Itoa(c.Status)
+			}
+			format, articles, added := "-", "-", "-"
+			if c.Format != "" {
+				format = c.Format
+				articles = strconv.Itoa(c.Articles)
+				added = "+" + strconv.Itoa(c.New)
+			}
+			errText := ""
+			if c.Err != nil {
+				failed++
+				errText = c.Err.Error()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				status, c.Duration.Round(time.Millisecond), format, articles, added, c.Feed.Title, errText)
+		}
+		_ = w.Flush()
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d feed(s) failed", failed, len(checks))
+		}
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
+
+func showStats(_ *cobra.Command, _ []string) {
+	if err := withStore(func(store *storage.Store) error {
+		counts, err := store.Counts()
+		if err != nil {
+			return fmt.Errorf("failed to count articles: %w", err)
+		}
+		feeds, err := store.GetAllFeeds()
+		if err != nil {
+			return fmt.Errorf("failed to get feeds: %w", err)
+		}
+		printStats(os.Stdout, counts, feeds, statsTop)
+		return nil
+	}); err != nil {
+		exitWithError(err)
+	}
+}
+
+// printStats renders the stats report. Feeds are ranked by article count,
+// ties broken by title, and only the first top are listed.
+func printStats(out io.Writer, counts storage.Counts, feeds []*storage.Feed, top int) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Feeds:\t%d\n", counts.Feeds)
+	fmt.Fprintf(w, "Articles:\t%d\n", counts.Articles)
+	fmt.Fprintf(w, "Unread:\t%d\n", counts.Unread)
+	fmt.Fprintf(w, "Database size:\t%s\n", formatSize(counts.Size))
+	if counts.Articles > 0 {
+		fmt.Fprintf(w, "Oldest article:\t%s\n", counts.Oldest.Local().Format("2006-01-02 15:04"))
+		fmt.Fprintf(w, "Newest article:\t%s\n", counts.Newest.Local().Format("2006-01-02 15:04"))
+	}
+	_ = w.Flush()
+
+	if top <= 0 || len(feeds) == 0 {
+		return
+	}
+	ranked := slices.Clone(feeds)
+	slices.SortStableFunc(ranked, func(a, b *storage.Feed) int {
+		return counts.ByFeed[b.ID].Total - counts.ByFeed[a.ID].Total
+	})
+	ranked = ranked[:min(top, len(ranked))]
+
+	fmt.Fprintf(out, "\nTop %d feeds by article count:\n", len(ranked))
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ARTICLES\tUNREAD\tFEED")
+	for _, f := range ranked {
+		title := f.Title
+		if title == "" {
+			title = f.URL
+		}
+		st := counts.ByFeed[f.ID]
+		fmt.Fprintf(w, "%d\t%d\t%s\n", st.Total, st.Unread, title)
+	}
+	_ = w.Flush()
+}
+
+// formatSize renders a byte count with a binary unit, e.g. "3.2 MiB".
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -2,12 +2,29 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/storage"
+	"github.com/pders01/fwrd/internal/tui"
 )
 
 func TestPluginsListCommand(t *testing.T) {
@@ -42,6 +59,44 @@ func TestPluginsListCommand(t *testing.T) {
 	}
 }
 
+// captureBanner returns what tui.ShowBanner prints to stdout.
+func captureBanner(t *testing.T) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	outC := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		outC <- buf.String()
+	}()
+	tui.ShowBanner("1.0.0")
+	w.Close()
+	return <-outC
+}
+
+func TestDisableColor_BannerHasNoEscapeCodes(t *testing.T) {
+	prev := lipgloss.ColorProfile()
+	t.Cleanup(func() { lipgloss.SetColorProfile(prev) })
+
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	if out := captureBanner(t); !strings.Contains(out, "\x1b[") {
+		t.Fatalf("expected a colored banner before disabling color, got: %q", out)
+	}
+
+	disableColor()
+	out := captureBanner(t)
+	if strings.Contains(out, "\x1b") {
+		t.Errorf("banner still has escape codes with color disabled: %q", out)
+	}
+	if !strings.Contains(out, "RSS Feed Aggregator v1.0.0") {
+		t.Errorf("banner lost its text: %q", out)
+	}
+}
+
 func TestVersionCommand(t *testing.T) {
 	// Capture stdout
 	old := os.Stdout
@@ -80,6 +135,31 @@ func TestVersionCommand(t *testing.T) {
 	}
 }
 
+func TestPrintVersionJSON(t *testing.T) {
+	oldVersion, oldCommit := Version, Commit
+	Version, Commit = "1.2.3", "abc1234"
+	defer func() { Version, Commit = oldVersion, oldCommit }()
+
+	var out bytes.Buffer
+	if err := printVersionJSON(&out); err != nil {
+		t.Fatalf("printVersionJSON() error = %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	want := map[string]string{
+		"version":   "1.2.3",
+		"commit":    "abc1234",
+		"goVersion": runtime.Version(),
+		"os":        runtime.GOOS,
+		"arch":      runtime.GOARCH,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("printVersionJSON() = %v, want %v", got, want)
+	}
+}
+
 func TestGenerateConfigCommand(t *testing.T) {
 	// Create temp directory for test
 	tmpDir := t.TempDir()
@@ -89,6 +169,7 @@ func TestGenerateConfigCommand(t *testing.T) {
 	oldHome := os.Getenv("HOME")
 	os.Setenv("HOME", tmpDir)
 	defer os.Setenv("HOME", oldHome)
+	t.Setenv("XDG_CONFIG_HOME", "")
 
 	// Capture stdout
 	old := os.Stdout
@@ -214,3 +295,440 @@ func TestNetPorts(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatSize(t *testing.T) {
+	cases := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, c := range cases {
+		if got := formatSize(c.n); got != c.want {
+			t.Errorf("formatSize(%d) = %q, want %q", c.n, got, c.want)
+		}
+	}
+}
+
+func TestPrintStats_RanksFeedsByArticleCount(t *testing.T) {
+	feeds := []*storage.Feed{
+		{ID: "a", Title: "Alpha"},
+		{ID: "b", Title: "Beta"},
+		{ID: "c", URL: "https://example.com/c.xml"},
+	}
+	counts := storage.Counts{
+		Feeds:    3,
+		Articles: 12,
+		Unread:   4,
+		Size:     64 << 10,
+		Oldest:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local),
+		Newest:   time.Date(2025, 3, 4, 0, 0, 0, 0, time.Local),
+		ByFeed: map[string]storage.FeedStat{
+			"a": {Total: 2},
+			"b": {Total: 7, Unread: 3},
+			"c": {Total: 3, Unread: 1},
+		},
+	}
+
+	var buf bytes.Buffer
+	printStats(&buf, counts, feeds, 2)
+	out := buf.String()
+
+	for _, want := range []string{"Articles:", "12", "64.0 KiB", "2024-01-02", "2025-03-04", "Top 2 feeds"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	beta, url := strings.Index(out, "Beta"), strings.Index(out, "https://example.com/c.xml")
+	if beta < 0 || url < 0 || beta > url {
+		t.Errorf("expected Beta ranked above the untitled feed:\n%s", out)
+	}
+	if strings.Contains(out, "Alpha") {
+		t.Errorf("--top 2 should drop the smallest feed:\n%s", out)
+	}
+}
+
+func TestPrintFeedInfo(t *testing.T) {
+	f := &storage.Feed{
+		ID:           "f1",
+		URL:          "https://example.com/feed.xml",
+		Title:        "Example",
+		SiteURL:      "https://example.com/",
+		Description:  "A feed\n  about examples",
+		LastSuccess:  time.Date(2025, 3, 4, 5, 6, 7, 0, time.Local),
+		LastAttempt:  time.Date(2025, 3, 6, 7, 8, 9, 0, time.Local),
+		ETag:         `"abc"`,
+		LastModified: "Tue, 04 Mar 2025 05:06:07 GMT",
+		LastError:    "HTTP 503",
+		LastErrorAt:  time.Date(2025, 3, 5, 0, 0, 0, 0, time.Local),
+		SelfURL:      "https://example.com/new-feed.xml",
+		HubURLs:      []string{"https://hub.example/", "https://other-hub.example/"},
+	}
+
+	var buf bytes.Buffer
+	printFeedInfo(&buf, f, storage.FeedStat{Total: 12, Unread: 5}, "2006-01-02 15:04")
+	out := buf.String()
+	for _, want := range []string{
+		"Example", "f1", "https://example.com/", "A feed about examples",
+		"12", "5", "Last attempt:   2025-03-06 07:08", "Last success:   2025-03-04 05:06", `"abc"`, "Tue, 04 Mar 2025",
+		"HTTP 503 (2025-03-05 00:00)", "auto",
+		"https://hub.example/", "https://other-hub.example/", "it may have moved",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printFeedInfo(&buf, &storage.Feed{ID: "f2", URL: "https://example.com/b.xml", SelfURL: "https://example.com/b.xml"}, storage.FeedStat{}, "2006-01-02")
+	out = buf.String()
+	if strings.Contains(out, "moved") {
+		t.Errorf("a matching self URL should not be flagged:\n%s", out)
+	}
+	for _, want := range []string{"never", "none advertised"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSetFeedTitle(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	orig := &storage.Feed{ID: "f1", URL: "https://example.com/feed.xml", Title: "Old"}
+	if err := store.SaveFeed(orig); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := setFeedTitle(store, orig.URL, "  New Title  ")
+	if err != nil {
+		t.Fatalf("setFeedTitle() by URL error = %v", err)
+	}
+	if f.Title != "New Title" {
+		t.Errorf("Title = %q, want trimmed %q", f.Title, "New Title")
+	}
+	stored, err := store.GetFeed("f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Title != "New Title" || stored.UpdatedAt.IsZero() {
+		t.Errorf("stored feed = %q updated %v, want renamed with UpdatedAt set", stored.Title, stored.UpdatedAt)
+	}
+
+	if _, err := setFeedTitle(store, "f1", "   "); err == nil {
+		t.Error("expected an empty title to be rejected")
+	}
+	if _, err := setFeedTitle(store, "missing", "Title"); !errors.Is(err, storage.ErrFeedNotFound) {
+		t.Errorf("setFeedTitle() on a missing feed error = %v, want ErrFeedNotFound", err)
+	}
+}
+
+func TestSetFeedIcon(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.SaveFeed(&storage.Feed{ID: "f1", URL: "https://example.com/feed.xml", Title: "Example"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := setFeedIcon(store, "f1", " 🦀 "); err != nil {
+		t.Fatalf("setFeedIcon() error = %v", err)
+	}
+	stored, err := store.GetFeed("f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Icon != "🦀" {
+		t.Errorf("Icon = %q, want trimmed %q", stored.Icon, "🦀")
+	}
+
+	for _, bad := range []string{"not an icon", "muchtoolong"} {
+		if _, err := setFeedIcon(store, "f1", bad); err == nil {
+			t.Errorf("setFeedIcon(%q) should be rejected", bad)
+		}
+	}
+
+	if _, err := setFeedIcon(store, "f1", ""); err != nil {
+		t.Fatalf("clearing icon error = %v", err)
+	}
+	if stored, _ = store.GetFeed("f1"); stored.Icon != "" {
+		t.Errorf("Icon = %q after clearing, want empty", stored.Icon)
+	}
+}
+
+func TestSetFeedTag(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.SaveFeed(&storage.Feed{ID: "f1", URL: "https://example.com/feed.xml", Title: "Example"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tag := range []string{" Go ", "news", "go"} {
+		if _, err := setFeedTag(store, "f1", tag, true); err != nil {
+			t.Fatalf("setFeedTag(%q) error = %v", tag, err)
+		}
+	}
+	stored, err := store.GetFeed("f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Go", "news"}; !slices.Equal(stored.Tags, want) {
+		t.Errorf("Tags = %v, want %v", stored.Tags, want)
+	}
+
+	if _, err := setFeedTag(store, "f1", "", true); err == nil {
+		t.Error("an empty tag should be rejected")
+	}
+	if _, err := setFeedTag(store, "f1", "missing", false); err == nil {
+		t.Error("removing a tag the feed lacks should be an error")
+	}
+
+	if _, err := setFeedTag(store, "f1", "GO", false); err != nil {
+		t.Fatalf("removing tag error = %v", err)
+	}
+	if stored, _ = store.GetFeed("f1"); !slices.Equal(stored.Tags, []string{"news"}) {
+		t.Errorf("Tags = %v after untag, want [news]", stored.Tags)
+	}
+}
+
+func TestSetFeedFormat(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.SaveFeed(&storage.Feed{ID: "f1", URL: "https://example.com/feed.xml", Title: "Example"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := setFeedFormat(store, "f1", " Atom "); err != nil {
+		t.Fatalf("setFeedFormat() error = %v", err)
+	}
+	stored, err := store.GetFeed("f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.ForceFormat != "atom" {
+		t.Errorf("ForceFormat = %q, want %q", stored.ForceFormat, "atom")
+	}
+
+	if _, err := setFeedFormat(store, "f1", "yaml"); err == nil {
+		t.Error("setFeedFormat(yaml) should be rejected")
+	}
+
+	if _, err := setFeedFormat(store, "f1", "auto"); err != nil {
+		t.Fatalf("clearing format error = %v", err)
+	}
+	if stored, _ = store.GetFeed("f1"); stored.ForceFormat != "" {
+		t.Errorf("ForceFormat = %q after auto, want empty", stored.ForceFormat)
+	}
+}
+
+func TestSetFeedUserAgent(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.SaveFeed(&storage.Feed{ID: "f1", URL: "https://example.com/feed.xml", Title: "Example"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.FeedConfig{UserAgents: map[string]string{"browser": "Mozilla/5.0", "bot": "bot/1.0"}}
+
+	if _, err := setFeedUserAgent(store, cfg, "f1", " Browser "); err != nil {
+		t.Fatalf("setFeedUserAgent() error = %v", err)
+	}
+	stored, err := store.GetFeed("f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.UserAgentPreset != "browser" {
+		t.Errorf("UserAgentPreset = %q, want %q", stored.UserAgentPreset, "browser")
+	}
+
+	_, err = setFeedUserAgent(store, cfg, "f1", "curl")
+	if err == nil || !strings.Contains(err.Error(), "bot, browser") {
+		t.Errorf("unknown preset error = %v, want one listing the presets", err)
+	}
+
+	if _, err := setFeedUserAgent(store, cfg, "f1", "default"); err != nil {
+		t.Fatalf("clearing preset error = %v", err)
+	}
+	if stored, _ = store.GetFeed("f1"); stored.UserAgentPreset != "" {
+		t.Errorf("UserAgentPreset = %q after default, want empty", stored.UserAgentPreset)
+	}
+}
+
+func TestSetFeedOrder(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for _, f := range []*storage.Feed{
+		{ID: "f1", URL: "https://a.example/feed", Title: "A"},
+		{ID: "f2", URL: "https://b.example/feed", Title: "B"},
+	} {
+		if err := store.SaveFeed(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := setFeedOrder(store, []string{"https://b.example/feed", "f1"}); err != nil {
+		t.Fatalf("setFeedOrder() error = %v", err)
+	}
+	feeds, err := store.GetAllFeeds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if feeds[0].ID != "f2" || feeds[1].ID != "f1" {
+		t.Errorf("order = %s,%s; want f2,f1", feeds[0].ID, feeds[1].ID)
+	}
+
+	if err := setFeedOrder(store, []string{"f1", "f1"}); err == nil {
+		t.Error("listing a feed twice should be rejected")
+	}
+	if err := setFeedOrder(store, []string{"nope"}); !errors.Is(err, storage.ErrFeedNotFound) {
+		t.Errorf("expected ErrFeedNotFound, got %v", err)
+	}
+}
+
+func TestParseURLList(t *testing.T) {
+	input := "# my feeds\nhttps://a.example/feed\n\n   \n  https://b.example/rss  \r\n#https://skipped.example\nhttps://c.example/atom"
+	got := parseURLList(input)
+	want := []string{"https://a.example/feed", "https://b.example/rss", "https://c.example/atom"}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseURLList() = %q, want %q", got, want)
+	}
+	if got := parseURLList(""); len(got) != 0 {
+		t.Errorf("parseURLList(\"\") = %q, want none", got)
+	}
+}
+
+func TestNewManager_PermissiveAllowsLocalhost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Local</title></channel></rss>`)
+	}))
+	defer server.Close()
+
+	store, err := storage.NewStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	cfg := config.TestConfig()
+
+	defer func(prev bool) { permissive = prev }(permissive)
+
+	permissive = false
+	if _, err := newManager(store, cfg).AddFeed(server.URL); err == nil {
+		t.Fatal("expected a localhost feed to be rejected by default")
+	}
+
+	permissive = true
+	if _, err := newManager(store, cfg).AddFeed(server.URL); err != nil {
+		t.Fatalf("AddFeed() with --permissive error = %v", err)
+	}
+}
+
+func TestMatchFeeds(t *testing.T) {
+	feeds := []*storage.Feed{
+		{ID: "1", URL: "https://blog.example.com/feed", Title: "Example Blog"},
+		{ID: "2", URL: "https://news.test/rss", Title: "Daily News"},
+		{ID: "3", URL: "https://other.org/atom", Title: "example digest"},
+	}
+	got := matchFeeds(feeds, regexp.MustCompile(`(?i)example`))
+	if len(got) != 2 || got[0].ID != "1" || got[1].ID != "3" {
+		t.Errorf("matchFeeds() = %v, want feeds 1 and 3", got)
+	}
+	if got := matchFeeds(feeds, regexp.MustCompile(`nomatch`)); len(got) != 0 {
+		t.Errorf("matchFeeds() = %v, want none", got)
+	}
+}
+
+func TestConfirmBulkDelete(t *testing.T) {
+	many := make([]*storage.Feed, bulkDeleteLimit+1)
+	for i := range many {
+		many[i] = &storage.Feed{ID: strconv.Itoa(i)}
+	}
+	few := many[:3]
+
+	cases := []struct {
+		name    string
+		matches []*storage.Feed
+		input   string
+		yes     bool
+		want    bool
+	}{
+		{"answered yes", few, "y\n", false, true},
+		{"answered no", few, "n\n", false, false},
+		{"no answer", few, "", false, false},
+		{"--yes skips prompt", few, "", true, true},
+		{"too many without --yes", many, "y\n", false, false},
+		{"too many with --yes", many, "", true, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if got := confirmBulkDelete(strings.NewReader(tc.input), &out, tc.matches, tc.yes); got != tc.want {
+				t.Errorf("confirmBulkDelete() = %v, want %v\n%s", got, tc.want, out.String())
+			}
+		})
+	}
+}
+
+func TestSetFeedFilter(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if err := store.SaveFeed(&storage.Feed{ID: "f1", URL: "https://example.com/feed.xml", Title: "Example"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := setFeedFilter(store, "f1", []string{" golang ", ""}, []string{"crypto"}); err != nil {
+		t.Fatalf("setFeedFilter() error = %v", err)
+	}
+	stored, err := store.GetFeed("f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(stored.Include, []string{"golang"}) || !slices.Equal(stored.Exclude, []string{"crypto"}) {
+		t.Errorf("rules = %v / %v, want [golang] / [crypto]", stored.Include, stored.Exclude)
+	}
+
+	if _, err := setFeedFilter(store, "f1", []string{"go("}, nil); err == nil || !strings.Contains(err.Error(), "--include") {
+		t.Errorf("invalid pattern error = %v, want one naming --include", err)
+	}
+	if stored, _ = store.GetFeed("f1"); !slices.Equal(stored.Include, []string{"golang"}) {
+		t.Errorf("invalid rule replaced the stored ones: %v", stored.Include)
+	}
+
+	if _, err := setFeedFilter(store, "f1", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ = store.GetFeed("f1"); len(stored.Include)+len(stored.Exclude) != 0 {
+		t.Errorf("rules = %v / %v after clearing, want none", stored.Include, stored.Exclude)
+	}
+}
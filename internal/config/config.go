@@ -2,9 +2,11 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -21,18 +23,54 @@ const (
 	// DefaultSearchDebounceMs is the delay between the last keystroke
 	// in the search input and firing a query against the index.
 	DefaultSearchDebounceMs = 200
+	// DefaultStatusTimeoutMs is how long a TUI status bar message stays
+	// up when the caller does not ask for a specific duration.
+	DefaultStatusTimeoutMs = 2000
+	// DefaultRenderCacheSize is how many rendered articles the reader
+	// keeps for instant reopening.
+	DefaultRenderCacheSize = 32
 	// DefaultMaxConcurrentRefreshes is the worker count used by the
 	// feed manager when no override is configured.
 	DefaultMaxConcurrentRefreshes = 5
+	// DefaultMaxRedirects is how many redirects one feed fetch follows.
+	DefaultMaxRedirects = 5
+	// DefaultBodyCacheMaxFiles is how many raw feed bodies the body
+	// cache keeps before pruning the oldest.
+	DefaultBodyCacheMaxFiles = 200
+	// DefaultArticleListLimit is how many articles the article list
+	// loads per page.
+	DefaultArticleListLimit = 50
+	// DefaultSearchScanLimit caps how many articles the basic search
+	// engine examines for one query.
+	DefaultSearchScanLimit = 5000
+	// DefaultSearchResultLimit is how many results one TUI search shows.
+	DefaultSearchResultLimit = 20
+	// DefaultSearchHistorySize is how many recent TUI search queries are
+	// kept for recall.
+	DefaultSearchHistorySize = 20
 )
 
+// DefaultAllowedMediaSchemes are the URL schemes fwrd hands to a player
+// or opener when media.allowed_schemes is empty.
+var DefaultAllowedMediaSchemes = []string{"http", "https", "magnet", "mailto"}
+
 type Config struct {
+	// Version is the schema version of the file; see CurrentVersion.
+	// Older files are migrated on load.
+	Version  int            `mapstructure:"version"`
 	Database DatabaseConfig `mapstructure:"database"`
+	Search   SearchConfig   `mapstructure:"search"`
 	Feed     FeedConfig     `mapstructure:"feed"`
 	UI       UIConfig       `mapstructure:"ui"`
 	Media    MediaConfig    `mapstructure:"media"`
 	Keys     KeyConfig      `mapstructure:"keys"`
 	Web      WebConfig      `mapstructure:"web"`
+	// Integrations holds credentials for services fwrd sends articles to.
+	Integrations IntegrationsConfig `mapstructure:"integrations"`
+
+	// loadWarnings collects problems spotted while reading the file
+	// (e.g. unknown keys); Warnings reports them alongside its own checks.
+	loadWarnings []string
 }
 
 type WebConfig struct {
@@ -65,6 +103,23 @@ type WebAuditConfig struct {
 	Path string `mapstructure:"path"`
 }
 
+// IntegrationsConfig configures the read-it-later service the reader's
+// save key sends articles to. Only one is supported so far.
+type IntegrationsConfig struct {
+	Wallabag WallabagConfig `mapstructure:"wallabag"`
+}
+
+// WallabagConfig signs in to a Wallabag instance. ClientID and
+// ClientSecret come from an API client created in Wallabag's "API
+// clients management" page. An empty URL leaves the integration off.
+type WallabagConfig struct {
+	URL          string `mapstructure:"url"`
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"`
+	Username     string `mapstructure:"username"`
+	Password     string `mapstructure:"password"`
+}
+
 // WebTLSConfig configures HTTPS for the web view. The `serve` flags
 // (--tls/--tls-mode/--tls-cert/--tls-key) override these at runtime.
 type WebTLSConfig struct {
@@ -96,15 +151,66 @@ type DatabaseConfig struct {
 	SearchIndex string        `mapstructure:"search_index"`
 }
 
+// SearchConfig bounds the work one search query does. Limits <= 0 fall
+// back to DefaultSearchScanLimit and DefaultSearchResultLimit.
+type SearchConfig struct {
+	// ScanLimit caps how many articles, newest first, the basic engine
+	// reads per query. The bleve index is not affected.
+	ScanLimit int `mapstructure:"scan_limit"`
+	// ResultLimit is how many results a TUI search lists.
+	ResultLimit int `mapstructure:"result_limit"`
+	// HistorySize caps the recent queries kept for recall in the TUI
+	// search view. 0 keeps none.
+	HistorySize int `mapstructure:"history_size"`
+	// GroupByFeed lists each feed's article hits under the feed instead
+	// of interleaving them by score, dropping near-identical titles.
+	GroupByFeed bool `mapstructure:"group_by_feed"`
+}
+
 type FeedConfig struct {
 	HTTPTimeout       time.Duration `mapstructure:"http_timeout"`
 	RefreshInterval   time.Duration `mapstructure:"refresh_interval"`
 	DefaultRetryAfter time.Duration `mapstructure:"default_retry_after"`
 	UserAgent         string        `mapstructure:"user_agent"`
+	// RefreshJitter stretches each feed's RefreshInterval by up to this
+	// much, a different amount per feed and per refresh, so feeds
+	// refreshed together do not all fall due together again. 0 disables.
+	RefreshJitter time.Duration `mapstructure:"refresh_jitter"`
+	// RefreshOnStart refreshes every feed as soon as the TUI opens, as if
+	// ctrl+r had been pressed. Off by default so fwrd starts offline.
+	RefreshOnStart bool `mapstructure:"refresh_on_start"`
 	// MaxConcurrentRefreshes caps the number of feeds refreshed in
 	// parallel during RefreshAllFeeds. Set <= 0 to fall back to
-	// DefaultMaxConcurrentRefreshes.
+	// DefaultMaxConcurrentRefreshes. Also read from refresh_concurrency.
 	MaxConcurrentRefreshes int `mapstructure:"max_concurrent_refreshes"`
+	// MaxArticlesPerFeed caps how many articles one fetch saves, keeping
+	// the most recently published. 0 (the default) saves them all.
+	MaxArticlesPerFeed int `mapstructure:"max_articles_per_feed"`
+	// FetchRetries is how many more times a fetch is tried after a
+	// timeout or 5xx answer. Other failures, 4xx included, are final.
+	// 0 (the default) never retries: each retry of a hung host holds a
+	// refresh worker for another HTTPTimeout.
+	FetchRetries int `mapstructure:"fetch_retries"`
+	// FetchRetryBackoff is the wait before the first retry; it doubles
+	// for each one after that.
+	FetchRetryBackoff time.Duration `mapstructure:"fetch_retry_backoff"`
+	// MaxRedirects caps how many redirects one fetch follows. Set <= 0
+	// to fall back to DefaultMaxRedirects.
+	MaxRedirects int `mapstructure:"max_redirects"`
+	// CacheBodies keeps the last raw body fetched for each feed under
+	// BodyCacheDir, so `fwrd feed reparse` can parse it again without
+	// refetching. Off by default.
+	CacheBodies bool `mapstructure:"cache_bodies"`
+	// BodyCacheDir is where cached bodies are written, one file per feed.
+	BodyCacheDir string `mapstructure:"body_cache_dir"`
+	// BodyCacheMaxFiles bounds the cache; past it the least recently
+	// written bodies are removed. Set <= 0 to fall back to
+	// DefaultBodyCacheMaxFiles.
+	BodyCacheMaxFiles int `mapstructure:"body_cache_max_files"`
+	// UserAgents are named User-Agent strings a feed can be set to send
+	// instead of UserAgent, for servers that answer some clients
+	// differently. The name "default" is reserved for UserAgent itself.
+	UserAgents map[string]string `mapstructure:"user_agents"`
 }
 
 type UIConfig struct {
@@ -118,6 +224,43 @@ type UIConfig struct {
 	// SearchDebounceMs is the delay between the last keystroke in the
 	// search input and firing a query against the index.
 	SearchDebounceMs int `mapstructure:"search_debounce_ms"`
+	// StatusTimeoutMs is how long status bar messages stay visible.
+	// Warnings and errors ignore it and stay until the next key press.
+	StatusTimeoutMs int `mapstructure:"status_timeout_ms"`
+	// RestoreSession reopens the TUI with the last-viewed feed and
+	// article selected.
+	RestoreSession bool `mapstructure:"restore_session"`
+	// StartView is the view the TUI opens in: "feeds" (default), "unread"
+	// for the unread inbox, or "last" for whichever of the two was open
+	// at the last quit.
+	StartView string `mapstructure:"start_view"`
+	// TimeFormat is a Go time layout (e.g. "2006-01-02 15:04") used for
+	// article and feed timestamps. Empty keeps each view's built-in format.
+	TimeFormat string `mapstructure:"time_format"`
+	// CompactList shows feeds and articles one line each, without the
+	// description line, fitting about twice as many on screen.
+	CompactList bool `mapstructure:"compact_list"`
+	// RenderCacheSize is how many rendered articles the reader keeps so
+	// reopening one skips glamour. The least recently opened is dropped
+	// first.
+	RenderCacheSize int `mapstructure:"render_cache_size"`
+	// MarkReadDelayMs is how long an article must stay open in the reader
+	// before it is marked read, so skimming past it does not count. 0
+	// marks it read as soon as it opens.
+	MarkReadDelayMs int `mapstructure:"mark_read_delay_ms"`
+	// Colors holds the [ui.colors] palette entries (name → "#RRGGBB").
+	// The TUI palette is currently built in; entries are accepted and
+	// checked so files based on config.example.toml load without noise.
+	Colors map[string]string `mapstructure:"colors"`
+}
+
+// TimeLayout returns the configured TimeFormat, or fallback when it is
+// unset or has no time fields (Warnings reports the latter).
+func (u UIConfig) TimeLayout(fallback string) string {
+	if u.TimeFormat == "" || !validTimeFormat(u.TimeFormat) {
+		return fallback
+	}
+	return u.TimeFormat
 }
 
 type ArticleConfig struct {
@@ -125,8 +268,16 @@ type ArticleConfig struct {
 	WordWrapMaxWidth     int `mapstructure:"word_wrap_max_width"`
 	WordWrapMinWidth     int `mapstructure:"word_wrap_min_width"`
 	// ListLimit caps how many articles are loaded into the article list
-	// per feed. Set <= 0 to fall back to DefaultArticleLimit.
+	// per feed and page. Set <= 0 to fall back to DefaultArticleListLimit.
 	ListLimit int `mapstructure:"list_limit"`
+	// MaxRenderWidth caps the reader's text width below what the window
+	// allows and centers the narrower column. 0 (the default) uses the
+	// window-based width, left-aligned.
+	MaxRenderWidth int `mapstructure:"max_render_width"`
+	// ImageMode decides what becomes of inline images, which the terminal
+	// cannot draw: "link" (the default) writes alt text and URL on a line,
+	// "placeholder" keeps only the alt text, "hide" drops them.
+	ImageMode string `mapstructure:"image_mode"`
 }
 
 type MediaConfig struct {
@@ -134,6 +285,17 @@ type MediaConfig struct {
 	Linux         MediaPlayers `mapstructure:"linux"`
 	Windows       MediaPlayers `mapstructure:"windows"`
 	DefaultOpener string       `mapstructure:"default_opener"`
+	// SchemeHandlers routes URLs by scheme (e.g. "magnet", "mailto") to
+	// a command, consulted before media-type detection. The command is
+	// split on whitespace and the URL appended as the final argument.
+	SchemeHandlers map[string]string `mapstructure:"scheme_handlers"`
+	// AllowedSchemes lists the URL schemes links may use to be opened.
+	// Schemes with a SchemeHandlers entry are allowed too. Empty means
+	// DefaultAllowedMediaSchemes.
+	AllowedSchemes []string `mapstructure:"allowed_schemes"`
+	// Proxy, when set, is passed to launched players as HTTP_PROXY and
+	// HTTPS_PROXY. Only players that read those variables use it.
+	Proxy string `mapstructure:"proxy"`
 }
 
 type MediaPlayers struct {
@@ -146,6 +308,10 @@ type MediaPlayers struct {
 type KeyConfig struct {
 	Modifier string      `mapstructure:"modifier"`
 	Bindings KeyBindings `mapstructure:"bindings"`
+	// QuitRequiresModifier makes the quit binding modifier+key, like the
+	// other commands, and stops Esc on the feed list from quitting, so a
+	// stray bare key never exits. ctrl+c always quits.
+	QuitRequiresModifier bool `mapstructure:"quit_requires_modifier"`
 }
 
 type KeyBindings struct {
@@ -160,36 +326,88 @@ type KeyBindings struct {
 	OpenMedia   string `mapstructure:"open_media"`
 	ThemeToggle string `mapstructure:"theme_toggle"`
 	Back        string `mapstructure:"back"`
+	// NextUnread and PrevUnread jump between unread articles in the
+	// article list. Like Back they are literal keys, not modifier+key.
+	NextUnread string `mapstructure:"next_unread"`
+	PrevUnread string `mapstructure:"prev_unread"`
+	// ToggleRaw switches the reader between rendered and raw content.
+	ToggleRaw string `mapstructure:"toggle_raw"`
+	// UnreadInbox opens the unread articles of every feed from the feed
+	// list.
+	UnreadInbox string `mapstructure:"unread_inbox"`
+	// OpenAllMedia opens every entry of the media list at once.
+	OpenAllMedia string `mapstructure:"open_all_media"`
+	// MoveFeedUp and MoveFeedDown shift the selected feed one place in
+	// the feed list and save the new manual order. Literal keys.
+	MoveFeedUp   string `mapstructure:"move_feed_up"`
+	MoveFeedDown string `mapstructure:"move_feed_down"`
+	// TogglePin pins the selected feed to the top of the feed list, or
+	// unpins it.
+	TogglePin string `mapstructure:"toggle_pin"`
+	// FilterTag picks a tag to narrow the article list to.
+	FilterTag string `mapstructure:"filter_tag"`
+	// ToggleOrder switches the open feed between newest-first and
+	// oldest-first and remembers the choice for that feed.
+	ToggleOrder string `mapstructure:"toggle_order"`
+	// SaveForLater sends the open article to the configured
+	// read-it-later service.
+	SaveForLater string `mapstructure:"save_for_later"`
 }
 
 func defaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
-	dbPath := filepath.Join(homeDir, ".fwrd", "fwrd.db")
-	searchIndexPath := filepath.Join(homeDir, ".fwrd", "index.bleve")
+	// The database and index follow XDG_DATA_HOME; TLS material and the
+	// audit log stay under ~/.fwrd with the other runtime files.
+	dataDir, err := validation.DataDir()
+	if err != nil {
+		dataDir = filepath.Join(homeDir, ".fwrd")
+	}
+	dbPath := filepath.Join(dataDir, "fwrd.db")
+	searchIndexPath := filepath.Join(dataDir, "index.bleve")
+	bodyCacheDir := filepath.Join(dataDir, "feed-cache")
 
 	return &Config{
+		Version: CurrentVersion,
 		Database: DatabaseConfig{
 			Path:        dbPath,
 			Timeout:     1 * time.Second,
 			SearchIndex: searchIndexPath,
 		},
+		Search: SearchConfig{
+			ScanLimit:   DefaultSearchScanLimit,
+			ResultLimit: DefaultSearchResultLimit,
+			HistorySize: DefaultSearchHistorySize,
+		},
 		Feed: FeedConfig{
 			HTTPTimeout:            30 * time.Second,
 			RefreshInterval:        5 * time.Minute,
 			DefaultRetryAfter:      15 * time.Minute,
 			UserAgent:              "fwrd/1.0 (https://github.com/pders01/fwrd)",
 			MaxConcurrentRefreshes: DefaultMaxConcurrentRefreshes,
+			FetchRetryBackoff:      time.Second,
+			MaxRedirects:           DefaultMaxRedirects,
+			BodyCacheDir:           bodyCacheDir,
+			BodyCacheMaxFiles:      DefaultBodyCacheMaxFiles,
+			UserAgents: map[string]string{
+				"browser": "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:128.0) Gecko/20100101 Firefox/128.0",
+				"bot":     "Mozilla/5.0 (compatible; fwrd/1.0; +https://github.com/pders01/fwrd)",
+			},
 		},
 		UI: UIConfig{
 			Article: ArticleConfig{
 				MaxDescriptionLength: 150,
 				WordWrapMaxWidth:     120,
 				WordWrapMinWidth:     40,
-				ListLimit:            50,
+				ListLimit:            DefaultArticleListLimit,
+				ImageMode:            "link",
 			},
 			Icons:            "nerd",
 			Theme:            "auto",
 			SearchDebounceMs: DefaultSearchDebounceMs,
+			StatusTimeoutMs:  DefaultStatusTimeoutMs,
+			RestoreSession:   true,
+			StartView:        "feeds",
+			RenderCacheSize:  DefaultRenderCacheSize,
 		},
 		Media: MediaConfig{
 			Darwin: MediaPlayers{
@@ -210,22 +428,34 @@ func defaultConfig() *Config {
 				Audio: []string{"mpv", "vlc"},
 				PDF:   []string{"start"},
 			},
-			DefaultOpener: getDefaultOpener(),
+			DefaultOpener:  getDefaultOpener(),
+			AllowedSchemes: slices.Clone(DefaultAllowedMediaSchemes),
 		},
 		Keys: KeyConfig{
 			Modifier: "ctrl",
 			Bindings: KeyBindings{
-				Quit:        "q",
-				Search:      "s",
-				NewFeed:     "n",
-				RenameFeed:  "e",
-				DeleteFeed:  "x",
-				Refresh:     "r",
-				ToggleRead:  "u",
-				ToggleStar:  "f",
-				OpenMedia:   "o",
-				ThemeToggle: "t",
-				Back:        "esc",
+				Quit:         "q",
+				Search:       "s",
+				NewFeed:      "n",
+				RenameFeed:   "e",
+				DeleteFeed:   "x",
+				Refresh:      "r",
+				ToggleRead:   "u",
+				ToggleStar:   "f",
+				OpenMedia:    "o",
+				ThemeToggle:  "t",
+				Back:         "esc",
+				NextUnread:   "n",
+				PrevUnread:   "p",
+				ToggleRaw:    "p",
+				UnreadInbox:  "a",
+				OpenAllMedia: "g",
+				MoveFeedUp:   "shift+up",
+				MoveFeedDown: "shift+down",
+				TogglePin:    "k",
+				FilterTag:    "l",
+				ToggleOrder:  "d",
+				SaveForLater: "l",
 			},
 		},
 		Web: WebConfig{
@@ -303,20 +533,43 @@ func getDefaultOpener() string {
 }
 
 func Load(configPath string) (*Config, error) {
+	config, _, err := load(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Expand paths after loading
+	expandPaths(config)
+
+	return config, nil
+}
+
+// loadInfo describes the file load read, if any.
+type loadInfo struct {
+	file    string
+	version int
+	// migrated is set when a migration step changed a key, so the
+	// file is worth rewriting with Migrate.
+	migrated bool
+}
+
+// load reads and migrates the config without expanding paths, so Migrate
+// can write values back the way the user spelled them.
+func load(configPath string) (*Config, loadInfo, error) {
+	var info loadInfo
 	v := viper.New()
 
 	cfg := defaultConfig()
 	defaultsMap := map[string]any{}
 	if err := mapstructure.Decode(cfg, &defaultsMap); err != nil {
-		return nil, fmt.Errorf("encoding defaults: %w", err)
+		return nil, info, fmt.Errorf("encoding defaults: %w", err)
 	}
 	seedDefaults(v, "", defaultsMap)
 
 	if configPath != "" {
 		v.SetConfigFile(configPath)
 	} else {
-		homeDir, _ := os.UserHomeDir()
-		configDir := filepath.Join(homeDir, ".config", "fwrd")
+		configDir, _ := validation.ConfigDir()
 
 		v.SetConfigName("config")
 		v.SetConfigType("toml")
@@ -329,12 +582,22 @@ func Load(configPath string) (*Config, error) {
 
 	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("reading config: %w", err)
+			return nil, info, fmt.Errorf("reading config: %w", err)
 		}
 	}
 
 	normalizeOverrides(v, "", defaultsMap)
 
+	var migrateWarning string
+	if info.file = v.ConfigFileUsed(); info.file != "" {
+		info.version = fileVersion(v)
+		migrateWarning, info.migrated = migrate(v, info.version)
+	}
+
+	// feed.refresh_concurrency is accepted as a shorter spelling of
+	// feed.max_concurrent_refreshes; the canonical key wins if both are set.
+	renameKey(v, "feed.refresh_concurrency", "feed.max_concurrent_refreshes")
+
 	var config Config
 	matchNameOpt := func(dc *mapstructure.DecoderConfig) {
 		dc.MatchName = func(mapKey, fieldName string) bool {
@@ -342,13 +605,16 @@ func Load(configPath string) (*Config, error) {
 		}
 	}
 	if err := v.Unmarshal(&config, matchNameOpt); err != nil {
-		return nil, fmt.Errorf("unmarshaling config: %w", err)
+		return nil, info, fmt.Errorf("unmarshaling config: %w", err)
 	}
+	config.Version = CurrentVersion
 
-	// Expand paths after loading
-	expandPaths(&config)
+	config.loadWarnings = unknownKeyWarnings(v)
+	if migrateWarning != "" {
+		config.loadWarnings = append(config.loadWarnings, migrateWarning)
+	}
 
-	return &config, nil
+	return &config, info, nil
 }
 
 // expandPath securely expands and validates a path
@@ -375,8 +641,11 @@ func expandPath(path string) string {
 func expandPaths(cfg *Config) {
 	cfg.Database.Path = expandPath(cfg.Database.Path)
 	cfg.Database.SearchIndex = expandPath(cfg.Database.SearchIndex)
+	cfg.Feed.BodyCacheDir = expandPath(cfg.Feed.BodyCacheDir)
 }
 
+// Save writes config to path in the current schema. The file is replaced
+// atomically and created with mode 0600.
 func Save(config *Config, path string) error {
 	v := viper.New()
 
@@ -388,25 +657,89 @@ func Save(config *Config, path string) error {
 	}
 
 	feedCfg := map[string]any{
-		"http_timeout":        config.Feed.HTTPTimeout.String(),
-		"refresh_interval":    config.Feed.RefreshInterval.String(),
-		"default_retry_after": config.Feed.DefaultRetryAfter.String(),
-		"user_agent":          config.Feed.UserAgent,
+		"http_timeout":             config.Feed.HTTPTimeout.String(),
+		"refresh_interval":         config.Feed.RefreshInterval.String(),
+		"refresh_jitter":           config.Feed.RefreshJitter.String(),
+		"refresh_on_start":         config.Feed.RefreshOnStart,
+		"default_retry_after":      config.Feed.DefaultRetryAfter.String(),
+		"user_agent":               config.Feed.UserAgent,
+		"max_concurrent_refreshes": config.Feed.MaxConcurrentRefreshes,
+		"max_articles_per_feed":    config.Feed.MaxArticlesPerFeed,
+		"fetch_retries":            config.Feed.FetchRetries,
+		"fetch_retry_backoff":      config.Feed.FetchRetryBackoff.String(),
+		"max_redirects":            config.Feed.MaxRedirects,
+		"cache_bodies":             config.Feed.CacheBodies,
+		"body_cache_dir":           config.Feed.BodyCacheDir,
+		"body_cache_max_files":     config.Feed.BodyCacheMaxFiles,
+		"user_agents":              config.Feed.UserAgents,
 	}
 
+	// Whatever schema the config was read from, it is written as the
+	// current one.
+	v.Set("version", CurrentVersion)
 	v.Set("database", dbCfg)
+	v.Set("search", config.Search)
 	v.Set("feed", feedCfg)
 	v.Set("ui", config.UI)
 	v.Set("media", config.Media)
 	v.Set("keys", config.Keys)
 	v.Set("web", config.Web)
+	v.Set("integrations", config.Integrations)
 
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("creating config directory: %w", err)
 	}
 
-	return v.WriteConfigAs(path)
+	configType := strings.TrimPrefix(filepath.Ext(path), ".")
+	if configType == "" {
+		configType = "toml"
+	}
+	v.SetConfigType(configType)
+
+	return writeFileAtomic(path, func(w io.Writer) error {
+		return marshalConfig(v, w)
+	})
+}
+
+// marshalConfig serializes v. It is a variable so tests can make
+// serialization fail partway through a write.
+var marshalConfig = func(v *viper.Viper, w io.Writer) error {
+	return v.WriteConfigTo(w)
+}
+
+// writeFileAtomic writes path through a temp file in the same directory
+// and renames it into place, so an interrupted or failed write leaves any
+// existing file untouched. The result is readable only by the owner since
+// the config can carry feed credentials.
+func writeFileAtomic(path string, write func(io.Writer) error) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp config: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = tmp.Chmod(0o600); err != nil {
+		return fmt.Errorf("setting config permissions: %w", err)
+	}
+	if err = write(tmp); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("syncing config: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("closing config: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing config: %w", err)
+	}
+	return nil
 }
 
 func GenerateDefaultConfig(path string) error {
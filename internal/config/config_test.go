@@ -1,11 +1,16 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
+
+	"github.com/spf13/viper"
 )
 
 func TestGetDefaultOpener(t *testing.T) {
@@ -99,6 +104,38 @@ func TestLoad_DefaultConfig(t *testing.T) {
 	}
 }
 
+func TestLoad_FollowsXDGDirectories(t *testing.T) {
+	home := t.TempDir()
+	xdgData := filepath.Join(t.TempDir(), "data")
+	xdgConfig := filepath.Join(t.TempDir(), "config")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", xdgData)
+	t.Setenv("XDG_CONFIG_HOME", xdgConfig)
+
+	configDir := filepath.Join(xdgConfig, "fwrd")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "[feed]\nuser_agent = \"xdg/1.0\"\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Feed.UserAgent != "xdg/1.0" {
+		t.Errorf("UserAgent = %q, want the value from $XDG_CONFIG_HOME/fwrd/config.toml", cfg.Feed.UserAgent)
+	}
+	if want := filepath.Join(xdgData, "fwrd", "fwrd.db"); cfg.Database.Path != want {
+		t.Errorf("Database.Path = %q, want %q", cfg.Database.Path, want)
+	}
+	if want := filepath.Join(xdgData, "fwrd", "index.bleve"); cfg.Database.SearchIndex != want {
+		t.Errorf("Database.SearchIndex = %q, want %q", cfg.Database.SearchIndex, want)
+	}
+}
+
 func TestLoad_FromFile(t *testing.T) {
 	// Create a temporary config file
 	tmpDir, err := os.MkdirTemp("", "config-test-*")
@@ -153,6 +190,49 @@ max_description_length = 200
 	}
 }
 
+func TestLoad_RefreshConcurrencyAlias(t *testing.T) {
+	tmpDir := t.TempDir()
+	cases := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"alias", "[feed]\nrefresh_concurrency = 12\n", 12},
+		{"canonical wins", "[feed]\nrefresh_concurrency = 12\nmax_concurrent_refreshes = 3\n", 3},
+		{"unset", "[feed]\nuser_agent = \"x\"\n", DefaultMaxConcurrentRefreshes},
+	}
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, fmt.Sprintf("c%d.toml", i))
+			if err := os.WriteFile(path, []byte(tc.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Feed.MaxConcurrentRefreshes != tc.want {
+				t.Errorf("MaxConcurrentRefreshes = %d, want %d", cfg.Feed.MaxConcurrentRefreshes, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoad_SchemeHandlers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[media.scheme_handlers]\nmagnet = \"transmission-remote -a\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.Media.SchemeHandlers["magnet"]; got != "transmission-remote -a" {
+		t.Errorf("SchemeHandlers[magnet] = %q, want 'transmission-remote -a'", got)
+	}
+}
+
 func TestSave(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "config-save-test-*")
 	if err != nil {
@@ -214,6 +294,55 @@ func TestSave(t *testing.T) {
 	}
 }
 
+func TestSave_FailedWriteKeepsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	original := "[feed]\nuser_agent = \"keep-me/1.0\"\n"
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := marshalConfig
+	marshalConfig = func(_ *viper.Viper, w io.Writer) error {
+		if _, err := io.WriteString(w, "[feed]\nuser_ag"); err != nil {
+			return err
+		}
+		return errors.New("serialization interrupted")
+	}
+	err := Save(defaultConfig(), path)
+	marshalConfig = orig
+	if err == nil {
+		t.Fatal("Save() should report the serialization failure")
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != original {
+		t.Errorf("existing config was clobbered:\n%s", got)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("temp file left behind: %v", entries)
+	}
+
+	// A successful save replaces the file and tightens its mode.
+	if err := Save(defaultConfig(), path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("config mode = %o, want 600", perm)
+	}
+}
+
 func TestGenerateDefaultConfig(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "config-gen-test-*")
 	if err != nil {
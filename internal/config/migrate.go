@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// CurrentVersion is the config schema version this build reads natively
+// and writes from Save. Bump it together with a new entry in migrations.
+const CurrentVersion = 1
+
+// migrations[i] upgrades a file from schema version i to i+1. Each step
+// edits the raw viper values before they are decoded into a Config, so a
+// step only needs to know about the keys it renames or reshapes. It
+// reports whether the file used any of them.
+var migrations = []func(v *viper.Viper) bool{
+	migrateV0ToV1,
+}
+
+// migrateV0ToV1 handles files written before the version key existed.
+// Version 1 is the layout those files already use, so there is nothing
+// to rename; the first step that changes a key will be V1ToV2.
+func migrateV0ToV1(*viper.Viper) bool {
+	return false
+}
+
+// renameKey copies a value set in the file under oldKey to newKey unless
+// the file already sets newKey, which then wins. It reports whether the
+// file set oldKey at all.
+func renameKey(v *viper.Viper, oldKey, newKey string) bool {
+	if !v.InConfig(oldKey) {
+		return false
+	}
+	if !v.InConfig(newKey) {
+		v.Set(newKey, v.Get(oldKey))
+	}
+	return true
+}
+
+// fileVersion reports the schema version declared by the file viper read.
+// Files without a version key predate versioning and count as 0.
+func fileVersion(v *viper.Viper) int {
+	if !v.InConfig("version") {
+		return 0
+	}
+	return v.GetInt("version")
+}
+
+// migrate runs every step between from and CurrentVersion. It reports
+// whether any step found something to change, and a warning for the
+// caller to surface, or "" when nothing needed doing. An older file that
+// uses none of the keys the steps handle reads as current: a missing or
+// old version number alone is not worth a warning on every launch.
+func migrate(v *viper.Viper, from int) (warning string, changed bool) {
+	switch {
+	case from > CurrentVersion:
+		return fmt.Sprintf("config version %d is newer than this build supports (%d); unknown settings are ignored", from, CurrentVersion), false
+	case from == CurrentVersion:
+		return "", false
+	}
+	for i := max(from, 0); i < CurrentVersion; i++ {
+		if migrations[i](v) {
+			changed = true
+		}
+	}
+	if !changed {
+		return "", false
+	}
+	return fmt.Sprintf("config version %d is outdated (current %d); run with --migrate-config to upgrade the file", from, CurrentVersion), true
+}
+
+// Migrate upgrades the config file at configPath (or the default location
+// when empty) to CurrentVersion and rewrites it in place, keeping the
+// original next to it with a .bak suffix. The rewrite goes through Save,
+// so comments in the file are not kept. It returns the file it rewrote
+// and the version it started from; a file that is already current, or
+// needs no changes beyond its version number, is left untouched and
+// reported with an empty path.
+func Migrate(configPath string) (string, int, error) {
+	cfg, info, err := load(configPath)
+	if err != nil {
+		return "", 0, err
+	}
+	if info.file == "" {
+		return "", 0, fmt.Errorf("no config file found to migrate")
+	}
+	if !info.migrated {
+		return "", info.version, nil
+	}
+
+	orig, err := os.ReadFile(info.file)
+	if err != nil {
+		return "", info.version, fmt.Errorf("reading config: %w", err)
+	}
+	if err := os.WriteFile(info.file+".bak", orig, 0o600); err != nil {
+		return "", info.version, fmt.Errorf("backing up config: %w", err)
+	}
+	if err := Save(cfg, info.file); err != nil {
+		return "", info.version, fmt.Errorf("writing migrated config: %w", err)
+	}
+	return info.file, info.version, nil
+}
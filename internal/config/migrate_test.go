@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// withRenameStep makes the V0→V1 step, which has nothing to do in the
+// real schema, rename feed.agent_string to feed.user_agent for the
+// duration of the test, so the migration machinery has a key to move.
+func withRenameStep(t *testing.T) {
+	t.Helper()
+	saved := migrations[0]
+	migrations[0] = func(v *viper.Viper) bool {
+		return renameKey(v, "feed.agent_string", "feed.user_agent")
+	}
+	t.Cleanup(func() { migrations[0] = saved })
+}
+
+func TestLoad_MigratesUnversionedFile(t *testing.T) {
+	withRenameStep(t)
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[feed]\nagent_string = \"custom/1.0\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, CurrentVersion)
+	}
+	if cfg.Feed.UserAgent != "custom/1.0" {
+		t.Errorf("UserAgent = %q, want custom/1.0", cfg.Feed.UserAgent)
+	}
+	if !containsWarning(Warnings(cfg), "--migrate-config") {
+		t.Errorf("expected a migration hint, got %v", Warnings(cfg))
+	}
+
+	// Loading leaves the file alone.
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != content {
+		t.Errorf("Load rewrote the file:\n%s", got)
+	}
+}
+
+func TestLoad_NewerVersionWarns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("version = 99\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !containsWarning(Warnings(cfg), "newer than this build") {
+		t.Errorf("expected a newer-version warning, got %v", Warnings(cfg))
+	}
+}
+
+func TestMigrate_RewritesFileAndKeepsBackup(t *testing.T) {
+	withRenameStep(t)
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[feed]\nmax_concurrent_refreshes = 9\nagent_string = \"custom/1.0\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, from, err := Migrate(path)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if file != path || from != 0 {
+		t.Errorf("Migrate() = (%q, %d), want (%q, 0)", file, from, path)
+	}
+
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil {
+		t.Fatalf("reading backup: %v", err)
+	}
+	if string(backup) != content {
+		t.Errorf("backup = %q, want original content", backup)
+	}
+
+	rewritten, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(rewritten), "agent_string") {
+		t.Errorf("rewritten file still uses the old key:\n%s", rewritten)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after migrate error = %v", err)
+	}
+	if w := Warnings(cfg); len(w) != 0 {
+		t.Errorf("migrated file should load cleanly, got %v", w)
+	}
+	if cfg.Feed.MaxConcurrentRefreshes != 9 || cfg.Feed.UserAgent != "custom/1.0" {
+		t.Errorf("values lost in migration: concurrency=%d user_agent=%q",
+			cfg.Feed.MaxConcurrentRefreshes, cfg.Feed.UserAgent)
+	}
+
+	// A second run finds nothing to do.
+	file, from, err = Migrate(path)
+	if err != nil {
+		t.Fatalf("second Migrate() error = %v", err)
+	}
+	if file != "" || from != CurrentVersion {
+		t.Errorf("second Migrate() = (%q, %d), want (\"\", %d)", file, from, CurrentVersion)
+	}
+}
+
+func TestMigrate_MissingFile(t *testing.T) {
+	if _, _, err := Migrate(filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Fatal("expected an error for a missing config file")
+	}
+}
+
+func containsWarning(warnings []string, substr string) bool {
+	for _, w := range warnings {
+		if strings.Contains(w, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestLoad_UnversionedFileWithNothingToMigrate covers every file written
+// before the version key: with none of the renamed keys in it, it loads
+// without a warning and --migrate-config leaves it, comments and all.
+func TestLoad_UnversionedFileWithNothingToMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "# my settings\n[feed]\nuser_agent = \"custom/1.0\" # keep\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if w := Warnings(cfg); len(w) != 0 {
+		t.Errorf("expected no warnings, got %v", w)
+	}
+
+	file, _, err := Migrate(path)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if file != "" {
+		t.Errorf("Migrate() rewrote %q, want nothing rewritten", file)
+	}
+	if got, _ := os.ReadFile(path); string(got) != content {
+		t.Errorf("file changed:\n%s", got)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("expected no backup, stat error = %v", err)
+	}
+}
@@ -8,11 +8,13 @@ func TestConfig() *Config {
 			Path:    ":memory:", // Use in-memory database for tests
 			Timeout: 1 * time.Second,
 		},
+		Search: defaultConfig().Search,
 		Feed: FeedConfig{
 			HTTPTimeout:       5 * time.Second,
 			RefreshInterval:   1 * time.Minute,
 			DefaultRetryAfter: 5 * time.Minute,
 			UserAgent:         "fwrd-test/1.0",
+			UserAgents:        defaultConfig().Feed.UserAgents,
 		},
 		UI:    defaultConfig().UI,
 		Media: defaultConfig().Media,
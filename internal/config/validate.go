@@ -2,8 +2,14 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/spf13/viper"
 )
 
 // reservedTerminalKeys maps a normalized "modifier+key" combination to a
@@ -18,27 +24,55 @@ var reservedTerminalKeys = map[string]string{
 	"ctrl+c": "ctrl+c always quits",
 }
 
+// literalBindings are used as typed rather than combined with the
+// modifier.
+var literalBindings = map[string]bool{
+	"back":           true,
+	"next_unread":    true,
+	"prev_unread":    true,
+	"move_feed_up":   true,
+	"move_feed_down": true,
+}
+
+// bindingViews names bindings that act in only one view. Two of them may
+// share a key when their views differ, as neither shadows the other.
+var bindingViews = map[string]string{
+	"filter_tag":     "lists",
+	"save_for_later": "reader",
+}
+
 // Warnings returns non-fatal issues with the loaded config. Callers
 // should print these to stderr at startup; nothing here blocks running.
 func Warnings(cfg *Config) []string {
 	if cfg == nil {
 		return nil
 	}
-	var out []string
+	out := append([]string(nil), cfg.loadWarnings...)
 
 	mod := strings.ToLower(strings.TrimSpace(cfg.Keys.Modifier))
 	bindings := map[string]string{
-		"quit":         cfg.Keys.Bindings.Quit,
-		"search":       cfg.Keys.Bindings.Search,
-		"new_feed":     cfg.Keys.Bindings.NewFeed,
-		"rename_feed":  cfg.Keys.Bindings.RenameFeed,
-		"delete_feed":  cfg.Keys.Bindings.DeleteFeed,
-		"refresh":      cfg.Keys.Bindings.Refresh,
-		"toggle_read":  cfg.Keys.Bindings.ToggleRead,
-		"toggle_star":  cfg.Keys.Bindings.ToggleStar,
-		"open_media":   cfg.Keys.Bindings.OpenMedia,
-		"theme_toggle": cfg.Keys.Bindings.ThemeToggle,
-		"back":         cfg.Keys.Bindings.Back,
+		"quit":           cfg.Keys.Bindings.Quit,
+		"search":         cfg.Keys.Bindings.Search,
+		"new_feed":       cfg.Keys.Bindings.NewFeed,
+		"rename_feed":    cfg.Keys.Bindings.RenameFeed,
+		"delete_feed":    cfg.Keys.Bindings.DeleteFeed,
+		"refresh":        cfg.Keys.Bindings.Refresh,
+		"toggle_read":    cfg.Keys.Bindings.ToggleRead,
+		"toggle_star":    cfg.Keys.Bindings.ToggleStar,
+		"open_media":     cfg.Keys.Bindings.OpenMedia,
+		"theme_toggle":   cfg.Keys.Bindings.ThemeToggle,
+		"back":           cfg.Keys.Bindings.Back,
+		"next_unread":    cfg.Keys.Bindings.NextUnread,
+		"prev_unread":    cfg.Keys.Bindings.PrevUnread,
+		"toggle_raw":     cfg.Keys.Bindings.ToggleRaw,
+		"unread_inbox":   cfg.Keys.Bindings.UnreadInbox,
+		"open_all_media": cfg.Keys.Bindings.OpenAllMedia,
+		"move_feed_up":   cfg.Keys.Bindings.MoveFeedUp,
+		"move_feed_down": cfg.Keys.Bindings.MoveFeedDown,
+		"toggle_pin":     cfg.Keys.Bindings.TogglePin,
+		"filter_tag":     cfg.Keys.Bindings.FilterTag,
+		"toggle_order":   cfg.Keys.Bindings.ToggleOrder,
+		"save_for_later": cfg.Keys.Bindings.SaveForLater,
 	}
 
 	// Stable iteration so warning order is deterministic.
@@ -54,20 +88,258 @@ func Warnings(cfg *Config) []string {
 		if val == "" {
 			continue
 		}
-		// "back" is bound to a literal key (e.g. "esc"), not modifier+key.
+		// Some bindings are literal keys (e.g. "esc"), not modifier+key.
 		combo := val
-		if name != "back" && mod != "" {
+		if !literalBindings[name] && mod != "" {
 			combo = mod + "+" + val
 		}
 		if reason, ok := reservedTerminalKeys[combo]; ok {
 			out = append(out, fmt.Sprintf("keys.bindings.%s = %q resolves to %s — %s; pick a different key", name, bindings[name], combo, reason))
 		}
-		if other, dup := seen[combo]; dup {
-			out = append(out, fmt.Sprintf("keys.bindings.%s and keys.bindings.%s both resolve to %s", other, name, combo))
-		} else {
+		other, dup := seen[combo]
+		switch {
+		case !dup:
 			seen[combo] = name
+		case bindingViews[other] != "" && bindingViews[name] != "" && bindingViews[other] != bindingViews[name]:
+			// Bound in different views; no conflict.
+		default:
+			out = append(out, fmt.Sprintf("keys.bindings.%s and keys.bindings.%s both resolve to %s", other, name, combo))
+		}
+	}
+
+	if cfg.Database.Timeout <= 0 {
+		out = append(out, fmt.Sprintf("database.timeout = %s must be greater than zero", cfg.Database.Timeout))
+	}
+	if cfg.Feed.HTTPTimeout <= 0 {
+		out = append(out, fmt.Sprintf("feed.http_timeout = %s must be greater than zero", cfg.Feed.HTTPTimeout))
+	}
+	if cfg.Feed.RefreshInterval < 0 {
+		out = append(out, fmt.Sprintf("feed.refresh_interval = %s must not be negative", cfg.Feed.RefreshInterval))
+	}
+	if cfg.Feed.RefreshJitter < 0 {
+		out = append(out, fmt.Sprintf("feed.refresh_jitter = %s must not be negative", cfg.Feed.RefreshJitter))
+	}
+	if cfg.Feed.DefaultRetryAfter < 0 {
+		out = append(out, fmt.Sprintf("feed.default_retry_after = %s must not be negative", cfg.Feed.DefaultRetryAfter))
+	}
+	if cfg.Feed.FetchRetries < 0 {
+		out = append(out, fmt.Sprintf("feed.fetch_retries = %d must not be negative", cfg.Feed.FetchRetries))
+	}
+	if cfg.Feed.FetchRetryBackoff < 0 {
+		out = append(out, fmt.Sprintf("feed.fetch_retry_backoff = %s must not be negative", cfg.Feed.FetchRetryBackoff))
+	}
+	if n := cfg.Feed.MaxRedirects; n < 0 {
+		out = append(out, fmt.Sprintf("feed.max_redirects = %d is negative; using the default of %d", n, DefaultMaxRedirects))
+	}
+	if n := cfg.Feed.BodyCacheMaxFiles; n < 0 {
+		out = append(out, fmt.Sprintf("feed.body_cache_max_files = %d is negative; using the default of %d", n, DefaultBodyCacheMaxFiles))
+	}
+
+	colorNames := make([]string, 0, len(cfg.UI.Colors))
+	for n := range cfg.UI.Colors {
+		colorNames = append(colorNames, n)
+	}
+	sort.Strings(colorNames)
+	for _, n := range colorNames {
+		if !hexColor.MatchString(cfg.UI.Colors[n]) {
+			out = append(out, fmt.Sprintf("ui.colors.%s = %q is not a hex color like #RRGGBB", n, cfg.UI.Colors[n]))
+		}
+	}
+
+	for _, s := range cfg.Media.AllowedSchemes {
+		if !urlScheme.MatchString(s) {
+			out = append(out, fmt.Sprintf("media.allowed_schemes entry %q is not a URL scheme like https", s))
+		}
+	}
+
+	if p := cfg.Media.Proxy; p != "" {
+		if u, err := url.Parse(p); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+			out = append(out, fmt.Sprintf("media.proxy = %q is not a proxy URL like http://host:port", p))
+		}
+	}
+
+	if wb := cfg.Integrations.Wallabag; wb.URL != "" {
+		if u, err := url.Parse(wb.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			out = append(out, fmt.Sprintf("integrations.wallabag.url = %q is not a URL like https://wallabag.example.com", wb.URL))
+		}
+		if wb.ClientID == "" || wb.ClientSecret == "" || wb.Username == "" || wb.Password == "" {
+			out = append(out, "integrations.wallabag needs client_id, client_secret, username and password; saving for later will fail")
+		}
+	}
+
+	if f := cfg.UI.TimeFormat; f != "" && !validTimeFormat(f) {
+		out = append(out, fmt.Sprintf("ui.time_format = %q has no Go time layout fields (like 2006-01-02 15:04); using the built-in formats", f))
+	}
+
+	switch v := cfg.UI.StartView; v {
+	case "", "feeds", "unread", "last":
+	default:
+		out = append(out, fmt.Sprintf("ui.start_view = %q is not one of feeds, unread, last; starting in feeds", v))
+	}
+
+	if n := cfg.UI.StatusTimeoutMs; n < 0 {
+		out = append(out, fmt.Sprintf("ui.status_timeout_ms = %d is negative; using the default of %d", n, DefaultStatusTimeoutMs))
+	}
+
+	if n := cfg.UI.RenderCacheSize; n < 0 {
+		out = append(out, fmt.Sprintf("ui.render_cache_size = %d is negative; using the default of %d", n, DefaultRenderCacheSize))
+	}
+
+	if n := cfg.UI.MarkReadDelayMs; n < 0 {
+		out = append(out, fmt.Sprintf("ui.mark_read_delay_ms = %d is negative; marking articles read as soon as they open", n))
+	}
+
+	presets := make([]string, 0, len(cfg.Feed.UserAgents))
+	for name := range cfg.Feed.UserAgents {
+		presets = append(presets, name)
+	}
+	sort.Strings(presets)
+	for _, name := range presets {
+		if strings.TrimSpace(cfg.Feed.UserAgents[name]) == "" {
+			out = append(out, fmt.Sprintf("feed.user_agents.%s is empty; feeds set to it send feed.user_agent", name))
 		}
 	}
 
+	if n := cfg.UI.Article.ListLimit; n < 0 {
+		out = append(out, fmt.Sprintf("ui.article.list_limit = %d is negative; using the default of %d", n, DefaultArticleListLimit))
+	}
+
+	if n := cfg.UI.Article.MaxRenderWidth; n < 0 {
+		out = append(out, fmt.Sprintf("ui.article.max_render_width = %d is negative; using the window width", n))
+	}
+
+	switch v := cfg.UI.Article.ImageMode; v {
+	case "", "hide", "link", "placeholder":
+	default:
+		out = append(out, fmt.Sprintf("ui.article.image_mode = %q is not one of hide, link, placeholder; showing images as links", v))
+	}
+
+	if n := cfg.Search.ScanLimit; n < 0 {
+		out = append(out, fmt.Sprintf("search.scan_limit = %d is negative; using the default of %d", n, DefaultSearchScanLimit))
+	}
+	if n := cfg.Search.ResultLimit; n < 0 {
+		out = append(out, fmt.Sprintf("search.result_limit = %d is negative; using the default of %d", n, DefaultSearchResultLimit))
+	}
+	if n := cfg.Search.HistorySize; n < 0 {
+		out = append(out, fmt.Sprintf("search.history_size = %d is negative; keeping no search history", n))
+	}
+
+	if n := cfg.Feed.MaxConcurrentRefreshes; n < 0 {
+		out = append(out, fmt.Sprintf("feed.max_concurrent_refreshes = %d is negative; using the default of %d", n, DefaultMaxConcurrentRefreshes))
+	}
+
+	if n := cfg.Feed.MaxArticlesPerFeed; n < 0 {
+		out = append(out, fmt.Sprintf("feed.max_articles_per_feed = %d is negative; keeping every article", n))
+	}
+
 	return out
 }
+
+// timeFormatProbe is formatted with a candidate layout to see whether it
+// references any time fields. None of its fields match the digits in the
+// reference layout, so a layout of plain text formats to itself.
+var timeFormatProbe = time.Date(1999, 11, 28, 21, 47, 38, 0, time.UTC)
+
+// validTimeFormat reports whether layout renders anything time-dependent.
+func validTimeFormat(layout string) bool {
+	out := timeFormatProbe.Format(layout)
+	return out != "" && out != layout
+}
+
+// hexColor matches #RGB and #RRGGBB.
+var hexColor = regexp.MustCompile(`^#(?:[0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// urlScheme matches a URL scheme name (RFC 3986), with or without its
+// trailing colon.
+var urlScheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:?$`)
+
+// keyAliases are accepted config keys that do not map to a struct field
+// directly; Load rewrites them onto their canonical key.
+var keyAliases = map[string]bool{
+	"feed.refresh_concurrency": true,
+}
+
+// unknownKeyWarnings reports keys set in the config file that match no
+// Config field. Matching mirrors Load's decoder: case-insensitive and
+// ignoring underscores, so "openmedia" and "OpenMedia" are both known.
+func unknownKeyWarnings(v *viper.Viper) []string {
+	var out []string
+	keys := v.AllKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !v.InConfig(key) || keyAliases[key] {
+			continue
+		}
+		if ok, segment, siblings := lookupKey(reflect.TypeOf(Config{}), strings.Split(key, ".")); !ok {
+			msg := fmt.Sprintf("unknown config key %q is ignored", key)
+			if s := closestKey(segment, siblings); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+// lookupKey walks path through t's mapstructure tags. Map-typed fields
+// accept any sub-key. On a miss it also returns the segment that failed
+// and the field names available at that level, for "did you mean" hints.
+func lookupKey(t reflect.Type, path []string) (ok bool, segment string, siblings []string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if len(path) == 0 || t.Kind() == reflect.Map {
+		return true, "", nil
+	}
+	if t.Kind() != reflect.Struct {
+		return false, path[0], nil
+	}
+	var names []string
+	for i := range t.NumField() {
+		name := t.Field(i).Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+		if normalizeKey(name) == normalizeKey(path[0]) {
+			return lookupKey(t.Field(i).Type, path[1:])
+		}
+		names = append(names, name)
+	}
+	return false, path[0], names
+}
+
+func normalizeKey(k string) string {
+	return strings.ToLower(strings.ReplaceAll(k, "_", ""))
+}
+
+// closestKey returns the candidate within edit distance 2 of key, if any.
+func closestKey(key string, candidates []string) string {
+	best, bestDist := "", 3
+	for _, c := range candidates {
+		if d := editDistance(normalizeKey(key), normalizeKey(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestWarnings_FlagsCtrlMCollision(t *testing.T) {
@@ -64,3 +67,254 @@ func TestWarnings_CleanConfigSilent(t *testing.T) {
 		t.Fatalf("default config should produce no warnings, got: %v", got)
 	}
 }
+
+func TestWarnings_FlagsNegativeRefreshConcurrency(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Feed.MaxConcurrentRefreshes = -2
+
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "max_concurrent_refreshes") {
+		t.Fatalf("expected a single max_concurrent_refreshes warning, got: %v", got)
+	}
+}
+
+func TestWarnings_FlagsNegativeArticleCap(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Feed.MaxArticlesPerFeed = -1
+
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "max_articles_per_feed") {
+		t.Fatalf("expected a single max_articles_per_feed warning, got: %v", got)
+	}
+}
+
+func TestWarnings_FlagsNegativeStatusTimeout(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.UI.StatusTimeoutMs = -5
+
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "ui.status_timeout_ms") {
+		t.Fatalf("expected a single ui.status_timeout_ms warning, got: %v", got)
+	}
+}
+
+func TestWarnings_FlagsNegativeRenderCacheSize(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.UI.RenderCacheSize = -1
+
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "ui.render_cache_size") {
+		t.Fatalf("expected a single ui.render_cache_size warning, got: %v", got)
+	}
+}
+
+func TestWarnings_FlagsNegativeMarkReadDelay(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.UI.MarkReadDelayMs = -1
+
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "ui.mark_read_delay_ms") {
+		t.Fatalf("expected a single ui.mark_read_delay_ms warning, got: %v", got)
+	}
+}
+
+func TestWarnings_FlagsNegativeMaxRedirects(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Feed.MaxRedirects = -1
+
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "feed.max_redirects") {
+		t.Fatalf("expected a single feed.max_redirects warning, got: %v", got)
+	}
+}
+
+func TestWarnings_FlagsBadAllowedScheme(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Media.AllowedSchemes = append(cfg.Media.AllowedSchemes, "file:", "http://")
+
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], `"http://"`) {
+		t.Fatalf("expected a single warning for \"http://\", got: %v", got)
+	}
+}
+
+func TestWarnings_FlagsBadMediaProxy(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Media.Proxy = "http://proxy.example:3128"
+	if got := Warnings(cfg); len(got) != 0 {
+		t.Fatalf("expected no warnings for a proxy URL, got: %v", got)
+	}
+
+	cfg.Media.Proxy = "proxy.example:3128"
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "media.proxy") {
+		t.Fatalf("expected a single media.proxy warning, got: %v", got)
+	}
+}
+
+func TestWarnings_FlagsNegativeSearchLimits(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Search.ScanLimit = -1
+	cfg.Search.ResultLimit = -1
+	cfg.Search.HistorySize = -1
+
+	got := Warnings(cfg)
+	if len(got) != 3 || !strings.Contains(got[0], "search.scan_limit") || !strings.Contains(got[1], "search.result_limit") || !strings.Contains(got[2], "search.history_size") {
+		t.Fatalf("expected search.scan_limit, search.result_limit and search.history_size warnings, got: %v", got)
+	}
+}
+
+func TestLoad_FlagsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `
+version = 1
+
+[feed]
+refresh_intervel = "1h"
+refresh_concurrency = 3
+
+[ui.article]
+max_description_length = 100
+
+[bogus]
+key = 1
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	got := Warnings(cfg)
+	want := []string{
+		`unknown config key "bogus.key" is ignored`,
+		`unknown config key "feed.refresh_intervel" is ignored (did you mean "refresh_interval"?)`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Warnings() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Warnings()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoad_ExampleAndSavedConfigsAreClean(t *testing.T) {
+	cfg, err := Load(filepath.Join("..", "..", "config.example.toml"))
+	if err != nil {
+		t.Fatalf("Load(example) error = %v", err)
+	}
+	if got := Warnings(cfg); len(got) != 0 {
+		t.Errorf("config.example.toml should load without warnings, got: %v", got)
+	}
+
+	// Save encodes some sections from the structs directly; the result
+	// must still be recognized on the way back in.
+	saved := filepath.Join(t.TempDir(), "saved.toml")
+	if err := GenerateDefaultConfig(saved); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load(saved)
+	if err != nil {
+		t.Fatalf("Load(saved) error = %v", err)
+	}
+	if got := Warnings(cfg); len(got) != 0 {
+		t.Errorf("generated config should load without warnings, got: %v", got)
+	}
+}
+
+func TestWarnings_FlagsBadRangesAndColors(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Feed.HTTPTimeout = 0
+	cfg.UI.Colors = map[string]string{"primary": "#FF6B6B", "accent": "teal", "muted": "#abc"}
+
+	got := Warnings(cfg)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 warnings, got: %v", got)
+	}
+	if !strings.Contains(got[0], "feed.http_timeout") {
+		t.Errorf("first warning should flag http_timeout: %q", got[0])
+	}
+	if !strings.Contains(got[1], "ui.colors.accent") {
+		t.Errorf("second warning should flag ui.colors.accent: %q", got[1])
+	}
+}
+
+func TestWarnings_FlagsTimeFormatWithoutFields(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.UI.TimeFormat = "yesterday"
+
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "ui.time_format") {
+		t.Fatalf("expected a single ui.time_format warning, got: %v", got)
+	}
+	if layout := cfg.UI.TimeLayout(time.RFC1123); layout != time.RFC1123 {
+		t.Errorf("TimeLayout() = %q, want the fallback for an invalid format", layout)
+	}
+
+	cfg.UI.TimeFormat = "2006-01-02 15:04"
+	if got := Warnings(cfg); len(got) != 0 {
+		t.Errorf("valid layout should not warn, got: %v", got)
+	}
+	if layout := cfg.UI.TimeLayout(time.RFC1123); layout != "2006-01-02 15:04" {
+		t.Errorf("TimeLayout() = %q, want the configured layout", layout)
+	}
+
+	cfg.UI.TimeFormat = ""
+	if layout := cfg.UI.TimeLayout("Jan 2"); layout != "Jan 2" {
+		t.Errorf("TimeLayout() = %q, want the fallback when unset", layout)
+	}
+}
+
+func TestWarnings_FlagsUnknownImageMode(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.UI.Article.ImageMode = "inline"
+
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "ui.article.image_mode") {
+		t.Fatalf("expected a single ui.article.image_mode warning, got: %v", got)
+	}
+
+	for _, mode := range []string{"", "hide", "link", "placeholder"} {
+		cfg.UI.Article.ImageMode = mode
+		if got := Warnings(cfg); len(got) != 0 {
+			t.Errorf("image_mode %q should not warn, got: %v", mode, got)
+		}
+	}
+}
+
+func TestWarnings_ViewScopedBindingsMayShareAKey(t *testing.T) {
+	cfg := defaultConfig()
+	if cfg.Keys.Bindings.SaveForLater != cfg.Keys.Bindings.FilterTag {
+		t.Fatalf("test assumes save_for_later and filter_tag share a default key")
+	}
+	if got := Warnings(cfg); len(got) != 0 {
+		t.Fatalf("defaults should not warn, got: %v", got)
+	}
+
+	cfg.Keys.Bindings.SaveForLater = cfg.Keys.Bindings.Refresh
+	got := Warnings(cfg)
+	if len(got) != 1 || !strings.Contains(got[0], "save_for_later") {
+		t.Fatalf("expected save_for_later to clash with refresh, got: %v", got)
+	}
+}
+
+func TestWarnings_FlagsIncompleteWallabag(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Integrations.Wallabag = WallabagConfig{URL: "wallabag.local", ClientID: "id"}
+
+	got := Warnings(cfg)
+	if len(got) != 2 || !strings.Contains(got[0], "integrations.wallabag.url") || !strings.Contains(got[1], "client_secret") {
+		t.Fatalf("expected url and credential warnings, got: %v", got)
+	}
+
+	cfg.Integrations.Wallabag = WallabagConfig{
+		URL: "https://wallabag.local", ClientID: "id", ClientSecret: "secret", Username: "me", Password: "pw",
+	}
+	if got := Warnings(cfg); len(got) != 0 {
+		t.Errorf("complete wallabag config should not warn, got: %v", got)
+	}
+}
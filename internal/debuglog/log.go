@@ -37,21 +37,32 @@ func (l LogLevel) String() string {
 	}
 }
 
-// ParseLogLevel parses a string into a LogLevel
+// ParseLogLevel parses a string into a LogLevel, falling back to INFO for
+// anything it does not recognise.
 func ParseLogLevel(s string) LogLevel {
+	if level, ok := LookupLogLevel(s); ok {
+		return level
+	}
+	return LevelInfo
+}
+
+// LookupLogLevel is ParseLogLevel for user input: ok is false when s is
+// not one of debug, info, warn (or warning), error or off, so the caller
+// can reject it instead of silently logging at INFO.
+func LookupLogLevel(s string) (level LogLevel, ok bool) {
 	switch strings.ToUpper(strings.TrimSpace(s)) {
 	case "DEBUG":
-		return LevelDebug
+		return LevelDebug, true
 	case "INFO":
-		return LevelInfo
+		return LevelInfo, true
 	case "WARN", "WARNING":
-		return LevelWarn
+		return LevelWarn, true
 	case "ERROR":
-		return LevelError
+		return LevelError, true
 	case "OFF":
-		return LevelOff
+		return LevelOff, true
 	default:
-		return LevelInfo // Default to INFO
+		return LevelInfo, false
 	}
 }
 
@@ -130,6 +141,12 @@ func GetLevel() LogLevel {
 	return currentLevel
 }
 
+// Enabled reports whether a message at level would be written, so callers
+// can skip building expensive log output.
+func Enabled(level LogLevel) bool {
+	return logger != nil && level >= currentLevel
+}
+
 // Close closes the log file if open
 func Close() error {
 	if logFile != nil {
@@ -53,6 +53,17 @@ func TestParseLogLevel(t *testing.T) {
 	}
 }
 
+func TestLookupLogLevel(t *testing.T) {
+	if level, ok := LookupLogLevel(" Warn "); !ok || level != LevelWarn {
+		t.Errorf("LookupLogLevel(\" Warn \") = %v, %v; want WARN, true", level, ok)
+	}
+	for _, input := range []string{"INVALID", "", "trace"} {
+		if _, ok := LookupLogLevel(input); ok {
+			t.Errorf("LookupLogLevel(%q) should not be ok", input)
+		}
+	}
+}
+
 func TestSetupWithLevel(t *testing.T) {
 	// Create temporary log file
 	tempDir, err := os.MkdirTemp("", "debuglog_test")
@@ -0,0 +1,123 @@
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/validation"
+)
+
+// ErrNoCachedBody is returned by ReparseFeed when no body is cached for
+// the feed, because feed.cache_bodies is off or it has not been fetched
+// since it was turned on.
+var ErrNoCachedBody = errors.New("no cached body for feed")
+
+// bodyCacheExt names the files the body cache owns; pruning leaves
+// anything else in the directory alone.
+const bodyCacheExt = ".body"
+
+// bodyCache keeps the last raw body fetched for each feed, one file per
+// feed ID, so it can be parsed again without a refetch.
+type bodyCache struct {
+	dir      string
+	maxFiles int
+}
+
+func newBodyCache(dir string, maxFiles int) *bodyCache {
+	if maxFiles <= 0 {
+		maxFiles = config.DefaultBodyCacheMaxFiles
+	}
+	return &bodyCache{dir: dir, maxFiles: maxFiles}
+}
+
+// path returns the validated file holding feedID's body.
+func (c *bodyCache) path(feedID string) (string, error) {
+	if feedID == "" || strings.ContainsAny(feedID, `/\`) || strings.HasPrefix(feedID, ".") {
+		return "", fmt.Errorf("invalid feed ID %q", feedID)
+	}
+	return validation.NewFilePathValidator().ValidateFile(filepath.Join(c.dir, feedID+bodyCacheExt))
+}
+
+// put replaces feedID's cached body and prunes the cache back to its
+// bound.
+func (c *bodyCache) put(feedID string, body []byte) error {
+	if _, err := validation.NewSecurePathHandler().EnsureSecureDirectory(c.dir); err != nil {
+		return fmt.Errorf("body cache directory: %w", err)
+	}
+	path, err := c.path(feedID)
+	if err != nil {
+		return err
+	}
+	// Write and rename so a concurrent reparse never reads half a body.
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+feedID+"-*")
+	if err != nil {
+		return fmt.Errorf("caching body: %w", err)
+	}
+	_, werr := tmp.Write(body)
+	if cerr := tmp.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr == nil {
+		werr = os.Rename(tmp.Name(), path)
+	}
+	if werr != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("caching body: %w", werr)
+	}
+	return c.prune()
+}
+
+// get returns feedID's cached body, or ErrNoCachedBody.
+func (c *bodyCache) get(feedID string) ([]byte, error) {
+	path, err := c.path(feedID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoCachedBody
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cached body: %w", err)
+	}
+	return body, nil
+}
+
+// prune removes the least recently written bodies past maxFiles.
+// Refresh workers prune concurrently, so a file already gone is fine.
+func (c *bodyCache) prune() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("pruning body cache: %w", err)
+	}
+	type cached struct {
+		name string
+		mod  int64
+	}
+	var files []cached
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), bodyCacheExt) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cached{e.Name(), info.ModTime().UnixNano()})
+	}
+	if len(files) <= c.maxFiles {
+		return nil
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].mod < files[j].mod })
+	for _, f := range files[:len(files)-c.maxFiles] {
+		if err := os.Remove(filepath.Join(c.dir, f.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("pruning body cache: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/pders01/fwrd/internal/storage"
+)
+
+// FeedCheck is the outcome of a dry-run fetch of one feed. Nothing about
+// it is written back to the store.
+type FeedCheck struct {
+	Feed *storage.Feed
+	// Status is the HTTP status code, or 0 when no response arrived.
+	Status int
+	// Duration is how long the server took to answer.
+	Duration time.Duration
+	// Format names the detected feed type, e.g. "rss 2.0" or "atom 1.0".
+	// It is empty unless a body was parsed.
+	Format string
+	// Articles counts the items in the response; New counts those not
+	// yet stored, i.e. what a refresh would add.
+	Articles int
+	New      int
+	// Err holds the fetch or parse failure, if any.
+	Err error
+}
+
+// CheckFeed performs the same conditional GET a refresh would and
+// reports what came back without saving anything. The refresh interval
+// is ignored so a check always hits the network. The returned error is
+// only for failing to look the feed up; fetch problems land in
+// FeedCheck.Err.
+func (m *Manager) CheckFeed(ctx context.Context, feedID string) (FeedCheck, error) {
+	feed, err := m.store.GetFeed(feedID)
+	if err != nil {
+		return FeedCheck{}, fmt.Errorf("getting feed: %w", err)
+	}
+	return m.checkFeed(ctx, feed), nil
+}
+
+// CheckAllFeeds runs CheckFeed over every stored feed in parallel,
+// bounded like RefreshAllFeeds. Results keep the store's feed order.
+func (m *Manager) CheckAllFeeds(ctx context.Context) ([]FeedCheck, error) {
+	feeds, err := m.store.GetAllFeeds()
+	if err != nil {
+		return nil, fmt.Errorf("getting feeds: %w", err)
+	}
+
+	checks := make([]FeedCheck, len(feeds))
+	sem := make(chan struct{}, m.maxConcurrentRefreshes())
+	var wg sync.WaitGroup
+	for i, f := range feeds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			checks[i] = m.checkFeed(ctx, f)
+		}()
+	}
+	wg.Wait()
+	return checks, nil
+}
+
+func (m *Manager) checkFeed(ctx context.Context, feed *storage.Feed) FeedCheck {
+	check := FeedCheck{Feed: feed}
+
+	start := time.Now()
+	resp, updated, err := m.fetcher.FetchContext(ctx, feed)
+	check.Duration = time.Since(start)
+	if err != nil {
+		var statusErr *HTTPStatusError
+		if errors.As(err, &statusErr) {
+			check.Status = statusErr.Code
+		}
+		check.Err = err
+		return check
+	}
+	if !updated || resp == nil {
+		check.Status = http.StatusNotModified
+		return check
+	}
+	defer resp.Body.Close()
+	check.Status = resp.StatusCode
+
+	parsed, articles, err := m.parser.parse(io.LimitReader(resp.Body, maxFeedBodySize), feed.ID, feed.URL, feed.ForceFormat)
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	check.Format = feedFormat(parsed)
+	check.Articles = len(articles)
+	for _, a := range m.newestArticles(articles) {
+		if _, err := m.store.GetArticle(a.ID); errors.Is(err, storage.ErrArticleNotFound) {
+			check.New++
+		}
+	}
+	return check
+}
+
+func feedFormat(f *gofeed.Feed) string {
+	return strings.TrimSpace(f.FeedType + " " + f.FeedVersion)
+}
@@ -0,0 +1,101 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/storage"
+)
+
+func TestCheckFeed_ReportsWithoutSaving(t *testing.T) {
+	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Check Feed</title>
+<item><title>Old</title><link>http://example.com/old</link><guid>old</guid></item>
+<item><title>New</title><link>http://example.com/new</link><guid>new</guid></item>
+</channel></rss>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, feedContent)
+	}))
+	defer server.Close()
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+	manager := NewManager(store, config.TestConfig())
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+
+	lastFetched := time.Now().Add(-time.Minute).Truncate(time.Second)
+	feed := &storage.Feed{ID: generateFeedID(server.URL), URL: server.URL, Title: "Check Feed", LastSuccess: lastFetched}
+	require.NoError(t, store.SaveFeed(feed))
+	require.NoError(t, store.SaveArticles([]*storage.Article{
+		{ID: generateID(feed.ID, "old"), FeedID: feed.ID, Title: "Old"},
+	}))
+
+	check, err := manager.CheckFeed(context.Background(), feed.ID)
+	require.NoError(t, err)
+	require.NoError(t, check.Err)
+	assert.Equal(t, http.StatusOK, check.Status)
+	assert.Equal(t, "rss 2.0", check.Format)
+	assert.Equal(t, 2, check.Articles)
+	assert.Equal(t, 1, check.New)
+
+	stored, err := store.GetFeed(feed.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.ETag, "check must not persist the ETag")
+	assert.True(t, stored.LastSuccess.Equal(lastFetched), "check must not touch LastSuccess")
+	articles, err := store.GetArticles(feed.ID, 0)
+	require.NoError(t, err)
+	assert.Len(t, articles, 1, "check must not save articles")
+
+	// A feed with a matching ETag gets a conditional 304.
+	feed.ETag = `"v1"`
+	require.NoError(t, store.SaveFeed(feed))
+	check, err = manager.CheckFeed(context.Background(), feed.ID)
+	require.NoError(t, err)
+	require.NoError(t, check.Err)
+	assert.Equal(t, http.StatusNotModified, check.Status)
+	assert.Empty(t, check.Format)
+}
+
+func TestCheckAllFeeds_RecordsFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+	manager := NewManager(store, config.TestConfig())
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+
+	feed := &storage.Feed{ID: generateFeedID(server.URL), URL: server.URL, Title: "Gone"}
+	require.NoError(t, store.SaveFeed(feed))
+
+	checks, err := manager.CheckAllFeeds(context.Background())
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	assert.Equal(t, http.StatusGone, checks[0].Status)
+	assert.Error(t, checks[0].Err)
+
+	stored, err := store.GetFeed(feed.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.LastError, "check must not record feed errors")
+
+	_, err = manager.CheckFeed(context.Background(), "missing")
+	assert.ErrorIs(t, err, storage.ErrFeedNotFound)
+}
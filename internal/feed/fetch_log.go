@@ -0,0 +1,71 @@
+package feed
+
+import (
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pders01/fwrd/internal/debuglog"
+)
+
+// sensitiveHeaders never reach the debug log with their values.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+}
+
+// formatHeaders renders h as "Name: value" pairs sorted by name, with
+// credentials replaced by [redacted].
+func formatHeaders(h http.Header) string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			value = "[redacted]"
+		}
+		parts = append(parts, name+": "+value)
+	}
+	return "{" + strings.Join(parts, "; ") + "}"
+}
+
+func logFetchRequest(req *http.Request) {
+	debuglog.Debugf("feed request: %s %s headers=%s", req.Method, req.URL.Redacted(), formatHeaders(req.Header))
+}
+
+// logFetchResponse records what came back for a feed request. bytes is
+// the body size actually read, or -1 when the body was discarded unread.
+func logFetchResponse(resp *http.Response, bytes int64) {
+	url := ""
+	if resp.Request != nil {
+		url = resp.Request.URL.Redacted()
+	}
+	debuglog.Debugf("feed response: %s %d content-type=%q etag=%q bytes=%d",
+		url, resp.StatusCode, resp.Header.Get("Content-Type"), resp.Header.Get("ETag"), bytes)
+}
+
+// loggedBody counts what is read from a response body and logs the
+// response once the body is closed, when its size is finally known.
+type loggedBody struct {
+	io.ReadCloser
+	resp *http.Response
+	n    int64
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	logFetchResponse(b.resp, b.n)
+	return b.ReadCloser.Close()
+}
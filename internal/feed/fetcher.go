@@ -1,13 +1,23 @@
 package feed
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/pders01/fwrd/internal/audit"
 	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/debuglog"
 	"github.com/pders01/fwrd/internal/storage"
+	"github.com/pders01/fwrd/internal/validation"
+	"golang.org/x/net/http/httpproxy"
 )
 
 type Fetcher struct {
@@ -15,17 +25,120 @@ type Fetcher struct {
 	config      *config.FeedConfig
 	userAgent   string
 	ignoreCache bool
+	// addrValidator, when set, vets every address the client connects
+	// to directly; see SetAddressValidator.
+	addrValidator *validation.FeedURLValidator
+	// proxyAddrs holds the host:port of every proxy the transport has
+	// picked. Dials to them skip addrValidator: a proxy is configured by
+	// the user and commonly runs on localhost or the LAN.
+	proxyAddrs sync.Map
 }
 
 func NewFetcher(cfg *config.Config) *Fetcher {
-	return &Fetcher{
-		client: &http.Client{
-			Timeout: cfg.Feed.HTTPTimeout,
-		},
+	f := &Fetcher{
 		config:      &cfg.Feed,
 		userAgent:   cfg.Feed.UserAgent,
 		ignoreCache: false,
 	}
+	checked := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   f.checkDialAddress,
+	}
+	direct := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	proxyFor := httpproxy.FromEnvironment().ProxyFunc()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		proxy, err := proxyFor(req.URL)
+		if proxy != nil {
+			f.proxyAddrs.Store(proxyAddr(proxy), true)
+		}
+		return proxy, err
+	}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if _, ok := f.proxyAddrs.Load(addr); ok {
+			return direct.DialContext(ctx, network, addr)
+		}
+		return checked.DialContext(ctx, network, addr)
+	}
+	f.client = &http.Client{
+		Timeout:       cfg.Feed.HTTPTimeout,
+		Transport:     transport,
+		CheckRedirect: f.checkRedirect,
+	}
+	return f
+}
+
+// proxyAddr is the host:port the transport dials for proxy, with the
+// scheme's default port filled in as net/http does.
+func proxyAddr(proxy *url.URL) string {
+	port := proxy.Port()
+	if port == "" {
+		switch proxy.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(proxy.Hostname(), port)
+}
+
+// SetAddressValidator makes the fetcher refuse connections to addresses
+// v rejects. The check runs on the resolved address right before each
+// direct connection, so a hostname that passed URL validation cannot be
+// re-pointed at a local or private address afterwards. Connections to
+// a proxy from HTTP(S)_PROXY are not checked; the feed host behind it
+// still is, by URL validation and on every redirect. A nil v connects
+// anywhere.
+func (f *Fetcher) SetAddressValidator(v *validation.FeedURLValidator) {
+	f.addrValidator = v
+}
+
+// checkRedirect is the client's CheckRedirect hook. It stops after
+// MaxRedirects and, when an address validator is set, refuses targets
+// whose host it rejects, so a public URL cannot bounce the fetch to a
+// local or private one.
+func (f *Fetcher) checkRedirect(req *http.Request, via []*http.Request) error {
+	limit := f.config.MaxRedirects
+	if limit <= 0 {
+		limit = config.DefaultMaxRedirects
+	}
+	// via holds every request so far, the original one included.
+	if len(via) > limit {
+		return fmt.Errorf("stopped after %d redirects", limit)
+	}
+	if f.addrValidator != nil {
+		if err := f.addrValidator.ValidateHost(req.URL.Host); err != nil {
+			return fmt.Errorf("redirect to %s refused: %w", req.URL.Redacted(), err)
+		}
+	}
+	return nil
+}
+
+// checkDialAddress is the dialer's Control hook. address is the resolved
+// IP and port about to be connected to.
+func (f *Fetcher) checkDialAddress(_, address string, _ syscall.RawConn) error {
+	if f.addrValidator == nil {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("dialing %s: not an IP address", address)
+	}
+	if err := f.addrValidator.ValidateIP(ip); err != nil {
+		return fmt.Errorf("refusing to connect to %s: %w", host, err)
+	}
+	return nil
 }
 
 // SetIgnoreCache sets whether to ignore ETag/Last-Modified headers
@@ -34,18 +147,67 @@ func (f *Fetcher) SetIgnoreCache(ignore bool) {
 }
 
 func (f *Fetcher) Fetch(feed *storage.Feed) (*http.Response, bool, error) {
-	req, err := http.NewRequest("GET", feed.URL, http.NoBody)
-	if err != nil {
-		return nil, false, fmt.Errorf("creating request: %w", err)
+	return f.FetchContext(context.Background(), feed)
+}
+
+// FetchContext is Fetch bound to ctx; cancelling ctx aborts the request
+// and any in-progress body read. Each call also gets its own deadline of
+// HTTPTimeout, covering the body read too, so one slow host cannot hold a
+// refresh worker past it. The deadline is released when the returned body
+// is closed.
+func (f *Fetcher) FetchContext(ctx context.Context, feed *storage.Feed) (*http.Response, bool, error) {
+	resp, updated, _, err := f.fetch(ctx, feed)
+	return resp, updated, err
+}
+
+// fetch is FetchContext that also reports where the feed has moved: the
+// final URL when every redirect on the way was permanent (301 or 308), or
+// "" when there were none or any was temporary. It is reported for 304s
+// and error statuses too, since a moved feed may well answer those.
+//
+// Timeouts and 5xx answers are retried per FetchRetries and
+// FetchRetryBackoff; each attempt gets its own HTTPTimeout.
+func (f *Fetcher) fetch(ctx context.Context, feed *storage.Feed) (resp *http.Response, updated bool, movedTo string, err error) {
+	err = retry(ctx, f.config.FetchRetries, f.config.FetchRetryBackoff, transientFetchError, func() error {
+		var attemptErr error
+		resp, updated, movedTo, attemptErr = f.fetchOnce(ctx, feed)
+		return attemptErr
+	})
+	return resp, updated, movedTo, err
+}
+
+// fetchOnce makes a single attempt at fetch.
+func (f *Fetcher) fetchOnce(ctx context.Context, feed *storage.Feed) (resp *http.Response, updated bool, movedTo string, err error) {
+	parent := ctx
+	cancel := context.CancelFunc(func() {})
+	if f.config.HTTPTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, f.config.HTTPTimeout)
 	}
+	defer func() {
+		if resp == nil {
+			cancel()
+		}
+	}()
+
 	// Tag the request so the audit RoundTripper (if installed) attributes it
 	// to feed fetching rather than a plugin call.
-	req = req.WithContext(audit.WithSource(req.Context(), "feed"))
+	req, err := http.NewRequestWithContext(audit.WithSource(ctx, "feed"), "GET", feed.URL, http.NoBody)
+	if err != nil {
+		return nil, false, "", fmt.Errorf("creating request: %w", err)
+	}
 
-	req.Header.Set("User-Agent", f.userAgent)
-	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml")
+	req.Header.Set("User-Agent", f.userAgentFor(feed))
+	// The body's format is sniffed rather than read off Content-Type, so
+	// anything is acceptable; the list only tells servers what we prefer.
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml, text/xml, application/json;q=0.9, */*;q=0.8")
+	// Accept-Encoding is deliberately left unset: the transport then asks
+	// for gzip itself and decompresses the body transparently. Setting the
+	// header here would hand us the raw compressed stream instead.
 
-	// Only set cache headers if not ignoring cache
+	// Only set cache headers if not ignoring cache. The validators go
+	// back exactly as the server sent them: a weak ETag keeps its W/
+	// prefix, since If-None-Match compares weakly and the bare tag would
+	// be a different, strong one that never matches.
 	if !f.ignoreCache {
 		if feed.ETag != "" {
 			req.Header.Set("If-None-Match", feed.ETag)
@@ -56,34 +218,110 @@ func (f *Fetcher) Fetch(feed *storage.Feed) (*http.Response, bool, error) {
 		}
 	}
 
-	resp, err := f.client.Do(req)
+	debugging := debuglog.Enabled(debuglog.LevelDebug)
+	if debugging {
+		logFetchRequest(req)
+	}
+
+	resp, err = f.client.Do(req)
 	if err != nil {
-		return nil, false, fmt.Errorf("fetching feed: %w", err)
+		if parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, false, "", fmt.Errorf("fetching feed: timed out after %s: %w", f.config.HTTPTimeout, err)
+		}
+		return nil, false, "", fmt.Errorf("fetching feed: %w", err)
+	}
+	movedTo = permanentRedirect(resp)
+
+	if debugging && (resp.StatusCode == http.StatusNotModified || resp.StatusCode >= 400) {
+		logFetchResponse(resp, -1)
 	}
 
 	if resp.StatusCode == http.StatusNotModified {
 		resp.Body.Close()
-		return nil, false, nil
+		// A 304 carries the validators a 200 would have; a server that
+		// rotated its ETag without changing the body sends the new one.
+		if !f.ignoreCache {
+			setValidators(feed, resp.Header, false)
+		}
+		return nil, false, movedTo, nil
 	}
 
 	if resp.StatusCode >= 400 {
 		resp.Body.Close()
-		return nil, false, fmt.Errorf("HTTP error: %d", resp.StatusCode)
+		return nil, false, movedTo, &HTTPStatusError{Code: resp.StatusCode}
+	}
+
+	if debugging {
+		resp.Body = &loggedBody{ReadCloser: resp.Body, resp: resp}
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, true, movedTo, nil
+}
+
+// permanentRedirect returns the URL resp was finally served from if the
+// client reached it only through permanent redirects, and "" otherwise.
+// The client links each redirected request to the response that caused
+// it, so the chain can be walked back from the final response.
+func permanentRedirect(resp *http.Response) string {
+	if resp.Request == nil || resp.Request.Response == nil {
+		return ""
 	}
+	for r := resp.Request.Response; r != nil; r = r.Request.Response {
+		if r.StatusCode != http.StatusMovedPermanently && r.StatusCode != http.StatusPermanentRedirect {
+			return ""
+		}
+	}
+	return resp.Request.URL.String()
+}
+
+// cancelOnClose releases a request's deadline once its body is done with.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// HTTPStatusError reports a feed request answered with a 4xx/5xx status.
+type HTTPStatusError struct {
+	Code int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("HTTP error: %d", e.Code)
+}
 
-	return resp, true, nil
+// userAgentFor picks the User-Agent sent for feed: its preset's, or the
+// fetcher's own when it has none or names one no longer configured.
+func (f *Fetcher) userAgentFor(feed *storage.Feed) string {
+	if ua := f.config.UserAgents[feed.UserAgentPreset]; feed.UserAgentPreset != "" && ua != "" {
+		return ua
+	}
+	return f.userAgent
 }
 
+// UpdateFeedMetadata records a full response's validators and marks the
+// fetch a success. Validators the response leaves out are cleared, so a
+// server that stops sending an ETag is not asked about the old one.
 func (f *Fetcher) UpdateFeedMetadata(feed *storage.Feed, resp *http.Response) {
-	if etag := resp.Header.Get("ETag"); etag != "" {
+	setValidators(feed, resp.Header, true)
+	feed.LastSuccess = time.Now()
+}
+
+// setValidators copies the ETag and Last-Modified in h to feed. A header
+// h lacks clears the feed's value when clearMissing is set and leaves it
+// otherwise.
+func setValidators(feed *storage.Feed, h http.Header, clearMissing bool) {
+	if etag := h.Get("ETag"); etag != "" || clearMissing {
 		feed.ETag = etag
 	}
-
-	if lastMod := resp.Header.Get("Last-Modified"); lastMod != "" {
+	if lastMod := h.Get("Last-Modified"); lastMod != "" || clearMissing {
 		feed.LastModified = lastMod
 	}
-
-	feed.LastFetched = time.Now()
 }
 
 func (f *Fetcher) GetRetryAfter(resp *http.Response) time.Duration {
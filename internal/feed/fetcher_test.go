@@ -1,13 +1,22 @@
 package feed
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/debuglog"
 	"github.com/pders01/fwrd/internal/storage"
+	"github.com/pders01/fwrd/internal/validation"
 )
 
 func TestFetcher_Fetch(t *testing.T) {
@@ -123,6 +132,412 @@ func TestFetcher_Fetch(t *testing.T) {
 	}
 }
 
+// conditionalServer answers 304 only when a request carries both
+// validators it handed out, and a full response otherwise.
+func conditionalServer(t *testing.T, etag, lastMod string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastMod)
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastMod {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte("<rss></rss>"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetcher_ConditionalGetSendsBothValidators(t *testing.T) {
+	const lastMod = "Wed, 01 Jan 2025 00:00:00 GMT"
+	for _, etag := range []string{`"v1"`, `W/"v1"`} {
+		t.Run(etag, func(t *testing.T) {
+			server := conditionalServer(t, etag, lastMod)
+			fetcher := NewFetcher(config.TestConfig())
+			feed := &storage.Feed{ID: "cond", URL: server.URL}
+
+			resp, updated, err := fetcher.Fetch(feed)
+			if err != nil || !updated {
+				t.Fatalf("first fetch: updated=%v err=%v", updated, err)
+			}
+			fetcher.UpdateFeedMetadata(feed, resp)
+			resp.Body.Close()
+			if feed.ETag != etag || feed.LastModified != lastMod {
+				t.Fatalf("stored validators %q, %q", feed.ETag, feed.LastModified)
+			}
+
+			resp, updated, err = fetcher.Fetch(feed)
+			if err != nil || updated || resp != nil {
+				t.Errorf("second fetch: updated=%v err=%v, want a 304", updated, err)
+			}
+
+			// With only one validator the server sends the body again.
+			resp, updated, err = fetcher.Fetch(&storage.Feed{ID: "cond", URL: server.URL, ETag: etag})
+			if err != nil || !updated {
+				t.Errorf("fetch with ETag alone: updated=%v err=%v, want a full response", updated, err)
+			}
+			if resp != nil {
+				resp.Body.Close()
+			}
+		})
+	}
+}
+
+func TestFetcher_NotModifiedUpdatesRotatedETag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `W/"v2"`)
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(config.TestConfig())
+	feed := &storage.Feed{ID: "rot", URL: server.URL, ETag: `"v1"`, LastModified: "Wed, 01 Jan 2025 00:00:00 GMT"}
+	if _, updated, err := fetcher.Fetch(feed); err != nil || updated {
+		t.Fatalf("updated=%v err=%v, want a 304", updated, err)
+	}
+	if feed.ETag != `W/"v2"` {
+		t.Errorf("ETag = %q, want the one the 304 sent", feed.ETag)
+	}
+	if feed.LastModified != "Wed, 01 Jan 2025 00:00:00 GMT" {
+		t.Errorf("LastModified = %q, a 304 without one must keep it", feed.LastModified)
+	}
+}
+
+func TestFetcher_FetchTimesOutSlowHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "no response headers",
+			handler: func(_ http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+			},
+		},
+		{
+			name: "stalled body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("<rss>"))
+				w.(http.Flusher).Flush()
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			cfg := config.TestConfig()
+			cfg.Feed.HTTPTimeout = 100 * time.Millisecond
+			fetcher := NewFetcher(cfg)
+
+			start := time.Now()
+			resp, _, err := fetcher.Fetch(&storage.Feed{ID: "slow", URL: server.URL})
+			if err == nil {
+				_, err = io.ReadAll(resp.Body)
+				resp.Body.Close()
+			}
+			elapsed := time.Since(start)
+
+			var netErr net.Error
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				t.Fatalf("expected a timeout error, got %v", err)
+			}
+			if elapsed > 2*time.Second {
+				t.Errorf("fetch took %v, want it cut off near the 100ms timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestFetcher_FetchRetriesTransientFailures(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		expectHits  int
+		expectError bool
+	}{
+		{name: "503 then 200 succeeds after one retry", status: http.StatusServiceUnavailable, expectHits: 2},
+		{name: "404 is not retried", status: http.StatusNotFound, expectHits: 1, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				hits++
+				if hits == 1 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Write([]byte("<rss></rss>"))
+			}))
+			defer server.Close()
+
+			cfg := config.TestConfig()
+			cfg.Feed.FetchRetries = 2
+			cfg.Feed.FetchRetryBackoff = time.Millisecond
+			fetcher := NewFetcher(cfg)
+
+			resp, updated, err := fetcher.Fetch(&storage.Feed{ID: "retry", URL: server.URL})
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Fetch() error = %v, expectError %v", err, tt.expectError)
+			}
+			if !tt.expectError && !updated {
+				t.Error("expected the retried fetch to return content")
+			}
+			if hits != tt.expectHits {
+				t.Errorf("server hit %d times, want %d", hits, tt.expectHits)
+			}
+		})
+	}
+}
+
+func TestFetcher_FetchSendsFeedUserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.UserAgent()
+		w.Write([]byte("<rss></rss>"))
+	}))
+	defer server.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.UserAgents = map[string]string{"browser": "Mozilla/5.0 test-browser"}
+	fetcher := NewFetcher(cfg)
+
+	tests := []struct {
+		preset string
+		want   string
+	}{
+		{preset: "", want: cfg.Feed.UserAgent},
+		{preset: "browser", want: "Mozilla/5.0 test-browser"},
+		{preset: "removed", want: cfg.Feed.UserAgent},
+	}
+	for _, tt := range tests {
+		resp, _, err := fetcher.Fetch(&storage.Feed{ID: "ua", URL: server.URL, UserAgentPreset: tt.preset})
+		if err != nil {
+			t.Fatalf("Fetch(preset %q) error = %v", tt.preset, err)
+		}
+		resp.Body.Close()
+		if got != tt.want {
+			t.Errorf("preset %q sent User-Agent %q, want %q", tt.preset, got, tt.want)
+		}
+	}
+}
+
+func TestFetcher_RefusesHostnameResolvingToLoopback(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte("<rss></rss>"))
+	}))
+	defer server.Close()
+
+	// A hostname rather than an IP literal, so only the dial-time check
+	// can see it lands on 127.0.0.1.
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	feed := &storage.Feed{ID: "rebind", URL: "http://localhost:" + port + "/feed"}
+
+	cfg := config.TestConfig()
+	cfg.Feed.FetchRetries = 0
+	fetcher := NewFetcher(cfg)
+	fetcher.SetAddressValidator(validation.NewFeedURLValidator())
+	_, _, err := fetcher.Fetch(feed)
+	if err == nil || !strings.Contains(err.Error(), "refusing to connect") {
+		t.Fatalf("Fetch() error = %v, want the dial refused", err)
+	}
+	if hits != 0 {
+		t.Fatalf("server hit %d times, want 0", hits)
+	}
+
+	fetcher.SetAddressValidator(validation.NewPermissiveFeedURLValidator())
+	resp, _, err := fetcher.Fetch(feed)
+	if err != nil {
+		t.Fatalf("permissive Fetch() error = %v", err)
+	}
+	resp.Body.Close()
+	if hits != 1 {
+		t.Fatalf("server hit %d times, want 1", hits)
+	}
+}
+
+func TestFetcher_FetchesThroughLocalProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy sees the absolute URL of the feed.
+		proxied = append(proxied, r.URL.String())
+		w.Write([]byte("<rss></rss>"))
+	}))
+	defer proxy.Close()
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("http_proxy", "")
+	t.Setenv("NO_PROXY", "")
+	t.Setenv("no_proxy", "")
+
+	cfg := config.TestConfig()
+	cfg.Feed.FetchRetries = 0
+	fetcher := NewFetcher(cfg)
+	fetcher.SetAddressValidator(validation.NewFeedURLValidator())
+	resp, _, err := fetcher.Fetch(&storage.Feed{ID: "via-proxy", URL: "http://feeds.example.org/feed"})
+	if err != nil {
+		t.Fatalf("Fetch() through a proxy on 127.0.0.1 error = %v", err)
+	}
+	resp.Body.Close()
+	if len(proxied) != 1 || proxied[0] != "http://feeds.example.org/feed" {
+		t.Fatalf("proxy saw %v, want the feed URL once", proxied)
+	}
+}
+
+// roundTripFunc serves requests in-process, for hosts no test server
+// can listen on.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return fn(r) }
+
+func TestFetcher_RefusesRedirectToPrivateAddress(t *testing.T) {
+	var metadataHits int
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		resp := &http.Response{StatusCode: http.StatusFound, Header: http.Header{}, Body: http.NoBody, Request: r}
+		switch {
+		case r.URL.Host == "169.254.169.254":
+			metadataHits++
+			resp.StatusCode = http.StatusOK
+			resp.Body = io.NopCloser(strings.NewReader("<rss></rss>"))
+		case r.URL.Path == "/feed":
+			resp.Header.Set("Location", "/moved")
+		default:
+			resp.Header.Set("Location", "http://169.254.169.254/latest/meta-data/")
+		}
+		return resp, nil
+	})
+
+	cfg := config.TestConfig()
+	cfg.Feed.FetchRetries = 0
+	fetcher := NewFetcher(cfg)
+	fetcher.client.Transport = transport
+	feed := &storage.Feed{ID: "bounce", URL: "https://feeds.news.org/feed"}
+
+	fetcher.SetAddressValidator(validation.NewFeedURLValidator())
+	_, _, err := fetcher.Fetch(feed)
+	if err == nil || !strings.Contains(err.Error(), "redirect to http://169.254.169.254/latest/meta-data/ refused") {
+		t.Fatalf("Fetch() error = %v, want the redirect refused", err)
+	}
+	if metadataHits != 0 {
+		t.Fatalf("private address hit %d times, want 0", metadataHits)
+	}
+
+	fetcher.SetAddressValidator(validation.NewPermissiveFeedURLValidator())
+	resp, _, err := fetcher.Fetch(feed)
+	if err != nil {
+		t.Fatalf("permissive Fetch() error = %v", err)
+	}
+	resp.Body.Close()
+	if metadataHits != 1 {
+		t.Fatalf("private address hit %d times, want 1", metadataHits)
+	}
+}
+
+func TestFetcher_StopsAfterMaxRedirects(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		http.Redirect(w, r, fmt.Sprintf("/hop%d", hits), http.StatusFound)
+	}))
+	defer server.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.FetchRetries = 0
+	cfg.Feed.MaxRedirects = 3
+	fetcher := NewFetcher(cfg)
+	fetcher.SetAddressValidator(validation.NewPermissiveFeedURLValidator())
+
+	_, _, err := fetcher.Fetch(&storage.Feed{ID: "loop", URL: server.URL})
+	if err == nil || !strings.Contains(err.Error(), "stopped after 3 redirects") {
+		t.Fatalf("Fetch() error = %v, want the redirect cap", err)
+	}
+	if hits != 4 {
+		t.Fatalf("server hit %d times, want 4", hits)
+	}
+}
+
+func TestFetcher_FetchLogsHTTPMetadataWhenDebugging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v2"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Header().Set("ETag", `"v2"`)
+		w.Write([]byte("<rss></rss>"))
+	}))
+	defer server.Close()
+
+	logPath := filepath.Join(t.TempDir(), "fwrd.log")
+	if err := debuglog.Setup(debuglog.LevelDebug, logPath); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = debuglog.Setup(debuglog.LevelOff) })
+
+	fetcher := NewFetcher(config.TestConfig())
+	feed := &storage.Feed{ID: "logged", URL: server.URL, ETag: `"v1"`}
+	resp, _, err := fetcher.Fetch(feed)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	feed.ETag = `"v2"`
+	if _, _, err := fetcher.Fetch(feed); err != nil {
+		t.Fatalf("conditional Fetch() error = %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+	for _, want := range []string{
+		"feed request: GET " + server.URL,
+		`If-None-Match: "v1"`,
+		`200 content-type="application/rss+xml" etag="\"v2\"" bytes=11`,
+		"304",
+	} {
+		if !strings.Contains(log, want) {
+			t.Errorf("debug log missing %q:\n%s", want, log)
+		}
+	}
+}
+
+func TestFormatHeaders_RedactsCredentials(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer secret")
+	h.Set("Cookie", "session=secret")
+	h.Set("If-None-Match", `"v1"`)
+
+	got := formatHeaders(h)
+	if strings.Contains(got, "secret") {
+		t.Errorf("formatHeaders leaked a credential: %s", got)
+	}
+	want := `{Authorization: [redacted]; Cookie: [redacted]; If-None-Match: "v1"}`
+	if got != want {
+		t.Errorf("formatHeaders() = %s, want %s", got, want)
+	}
+}
+
 func TestFetcher_UpdateFeedMetadata(t *testing.T) {
 	cfg := config.TestConfig()
 	fetcher := NewFetcher(cfg)
@@ -152,8 +567,22 @@ func TestFetcher_UpdateFeedMetadata(t *testing.T) {
 	if feed.LastModified != "Thu, 02 Jan 2025 00:00:00 GMT" {
 		t.Errorf("expected LastModified Thu, 02 Jan 2025 00:00:00 GMT, got %s", feed.LastModified)
 	}
-	if time.Since(feed.LastFetched) > time.Second {
-		t.Error("LastFetched not updated")
+	if time.Since(feed.LastSuccess) > time.Second {
+		t.Error("LastSuccess not updated")
+	}
+}
+
+func TestFetcher_UpdateFeedMetadataClearsDroppedValidators(t *testing.T) {
+	fetcher := NewFetcher(config.TestConfig())
+	feed := &storage.Feed{ID: "test", ETag: `"old"`, LastModified: "Wed, 01 Jan 2025 00:00:00 GMT"}
+
+	fetcher.UpdateFeedMetadata(feed, &http.Response{Header: http.Header{"Etag": {`W/"new"`}}})
+
+	if feed.ETag != `W/"new"` {
+		t.Errorf("ETag = %q, want W/\"new\"", feed.ETag)
+	}
+	if feed.LastModified != "" {
+		t.Errorf("LastModified = %q, want it cleared when the response has none", feed.LastModified)
 	}
 }
 
@@ -0,0 +1,52 @@
+package feed
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+// charRef matches a numeric character reference such as &#1; or &#x1b;.
+var charRef = regexp.MustCompile(`&#([xX][0-9a-fA-F]+|[0-9]+);`)
+
+// sanitizeFeedXML repairs what the XML decoder rejects even in gofeed's
+// non-strict mode: a byte order mark, invalid UTF-8, and control
+// characters, raw or written as character references. It is only tried
+// after a normal parse has failed, since it rewrites the body.
+func sanitizeFeedXML(data []byte) []byte {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.ToValidUTF8(data, []byte(string(utf8.RuneError)))
+	data = bytes.Map(func(r rune) rune {
+		if !xmlChar(r) {
+			return -1
+		}
+		return r
+	}, data)
+	return charRef.ReplaceAllFunc(data, func(ref []byte) []byte {
+		digits := string(ref[2 : len(ref)-1])
+		base := 10
+		if digits[0] == 'x' || digits[0] == 'X' {
+			digits, base = digits[1:], 16
+		}
+		code, err := strconv.ParseUint(digits, base, 32)
+		if err != nil || !xmlChar(rune(code)) {
+			return nil
+		}
+		return ref
+	})
+}
+
+// xmlChar reports whether r may appear in an XML 1.0 document.
+func xmlChar(r rune) bool {
+	switch {
+	case r == '\t' || r == '\n' || r == '\r':
+		return true
+	case r < 0x20:
+		return false
+	case r >= 0xD800 && r <= 0xDFFF, r == 0xFFFE || r == 0xFFFF:
+		return false
+	default:
+		return r <= utf8.MaxRune
+	}
+}
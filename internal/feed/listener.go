@@ -12,6 +12,13 @@ type DataListener interface {
 	OnDataUpdated(feed *storage.Feed, articles []*storage.Article)
 }
 
+// DeleteListener is optionally implemented by a DataListener that keeps
+// data per feed ID. It is told when a permanent redirect re-keys a feed,
+// just before the feed's articles are delivered again under the new ID.
+type DeleteListener interface {
+	OnFeedDeleted(feedID string)
+}
+
 // BatchScope brackets a multi-feed operation so listeners that batch work
 // (e.g. a search index using grouped writes) can amortise overhead across
 // many feeds. RefreshAllFeeds calls BeginBatch before any notifications
@@ -1,18 +1,24 @@
 package feed
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/mmcdole/gofeed"
 	"github.com/pders01/fwrd/internal/audit"
 	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/debuglog"
 	"github.com/pders01/fwrd/internal/plugins"
 	"github.com/pders01/fwrd/internal/storage"
 	"github.com/pders01/fwrd/internal/validation"
@@ -35,6 +41,11 @@ type Manager struct {
 	config         *config.Config
 	urlValidator   *validation.FeedURLValidator
 	pluginRegistry *plugins.Registry
+	// resubscribe lets AddFeed re-add a feed that is already stored; see
+	// SetAllowResubscribe.
+	resubscribe bool
+	// bodies is the raw body cache, nil unless feed.cache_bodies is on.
+	bodies *bodyCache
 
 	dataListeners []DataListener
 	batchScopes   []BatchScope
@@ -47,14 +58,21 @@ func NewManager(store *storage.Store, cfg *config.Config) *Manager {
 	// Initialize plugin registry with HTTP timeout from config
 	pluginRegistry := plugins.NewRegistry(cfg.Feed.HTTPTimeout)
 
-	return &Manager{
+	fetcher := NewFetcher(cfg)
+	fetcher.SetAddressValidator(urlValidator)
+
+	m := &Manager{
 		store:          store,
-		fetcher:        NewFetcher(cfg),
+		fetcher:        fetcher,
 		parser:         NewParser(),
 		config:         cfg,
 		urlValidator:   urlValidator,
 		pluginRegistry: pluginRegistry,
 	}
+	if cfg.Feed.CacheBodies {
+		m.bodies = newBodyCache(cfg.Feed.BodyCacheDir, cfg.Feed.BodyCacheMaxFiles)
+	}
+	return m
 }
 
 // SetForceRefresh configures the manager to ignore ETag/Last-Modified headers
@@ -64,6 +82,12 @@ func (m *Manager) SetForceRefresh(force bool) {
 	}
 }
 
+// SetAllowResubscribe lets AddFeed fetch and save again a feed that is
+// already subscribed instead of returning an AlreadySubscribedError.
+func (m *Manager) SetAllowResubscribe(allow bool) {
+	m.resubscribe = allow
+}
+
 // PluginRegistry returns the registry plugins are registered against.
 // Callers wire scriptable plugin loaders against this registry at
 // startup. The returned pointer is the manager's own registry; mutating
@@ -98,13 +122,17 @@ func (m *Manager) PluginHTTPClient() *http.Client {
 	return m.fetcher.client
 }
 
-// SetPermissiveValidation enables permissive URL validation for development/testing
+// SetPermissiveValidation enables permissive URL validation for development/testing.
+// It applies to the addresses fetches connect to as well as to feed URLs.
 func (m *Manager) SetPermissiveValidation(permissive bool) {
 	if permissive {
 		m.urlValidator = validation.NewPermissiveFeedURLValidator()
 	} else {
 		m.urlValidator = validation.NewFeedURLValidator()
 	}
+	if m.fetcher != nil {
+		m.fetcher.SetAddressValidator(m.urlValidator)
+	}
 }
 
 // RegisterDataListener subscribes l to post-write notifications. Listeners
@@ -129,6 +157,23 @@ func (m *Manager) notifyDataUpdated(feed *storage.Feed, articles []*storage.Arti
 	}
 }
 
+// notifyFeedMoved tells listeners that feed's data, formerly stored under
+// oldID, now lives under feed.ID: listeners that handle deletes drop the
+// old ID, and every listener is handed the feed's articles again.
+func (m *Manager) notifyFeedMoved(oldID string, feed *storage.Feed) {
+	for _, l := range m.dataListeners {
+		if dl, ok := l.(DeleteListener); ok {
+			dl.OnFeedDeleted(oldID)
+		}
+	}
+	articles, err := m.store.GetArticles(feed.ID, 0)
+	if err != nil {
+		debuglog.Warnf("loading moved feed %s: %v", feed.ID, err)
+		return
+	}
+	m.notifyDataUpdated(feed, articles)
+}
+
 func (m *Manager) beginBatchScopes() {
 	for _, s := range m.batchScopes {
 		s.BeginBatch()
@@ -146,15 +191,25 @@ func (m *Manager) commitBatchScopes() {
 // DataListeners. The returned feed and saved articles are also handed to
 // listeners.
 func (m *Manager) AddFeed(url string) (*storage.Feed, error) {
+	return m.AddFeedContext(context.Background(), url)
+}
+
+// AddFeedContext is AddFeed bound to ctx. Plugin enhancement is further
+// capped at cfg.Feed.HTTPTimeout; cancelling ctx aborts enhancement and
+// the fetch, and nothing is persisted.
+func (m *Manager) AddFeedContext(ctx context.Context, url string) (*storage.Feed, error) {
 	normalizedURL, err := m.urlValidator.ValidateAndNormalize(url)
 	if err != nil {
 		return nil, fmt.Errorf("invalid feed URL: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), m.config.Feed.HTTPTimeout)
+	enhanceCtx, cancel := context.WithTimeout(ctx, m.config.Feed.HTTPTimeout)
 	defer cancel()
 
-	feedInfo, err := m.pluginRegistry.EnhanceFeed(ctx, normalizedURL)
+	feedInfo, err := m.pluginRegistry.EnhanceFeed(enhanceCtx, normalizedURL)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
 	if err != nil {
 		feedInfo = &plugins.FeedInfo{
 			OriginalURL: normalizedURL,
@@ -174,8 +229,12 @@ func (m *Manager) AddFeed(url string) (*storage.Feed, error) {
 		Title:     feedInfo.Title,
 		UpdatedAt: time.Now(),
 	}
+	if err := m.checkNotSubscribed(feed.ID); err != nil {
+		return nil, err
+	}
 
-	resp, updated, err := m.fetcher.Fetch(feed)
+	feed.LastAttempt = time.Now()
+	resp, updated, movedTo, err := m.fetcher.fetch(ctx, feed)
 	if err != nil {
 		return nil, fmt.Errorf("fetching feed: %w", err)
 	}
@@ -183,11 +242,27 @@ func (m *Manager) AddFeed(url string) (*storage.Feed, error) {
 		return nil, fmt.Errorf("feed not modified")
 	}
 	defer resp.Body.Close()
+	if movedTo != "" {
+		// Subscribe to where the feed lives now rather than a URL that
+		// will redirect on every refresh.
+		feed.URL = movedTo
+		feed.ID = generateFeedID(movedTo)
+		if err := m.checkNotSubscribed(feed.ID); err != nil {
+			return nil, err
+		}
+	}
 
-	articles, err := m.parser.Parse(io.LimitReader(resp.Body, maxFeedBodySize), feed.ID)
+	body, err := m.cacheBody(feed.ID, io.LimitReader(resp.Body, maxFeedBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("parsing feed: %w", err)
+	}
+	parsed, articles, err := m.parser.parse(body, feed.ID, feed.URL, "")
 	if err != nil {
 		return nil, fmt.Errorf("parsing feed: %w", err)
 	}
+	articles = m.newestArticles(articles)
+	feed.SiteURL = siteURL(parsed, feed.URL)
+	feed.HubURLs, feed.SelfURL = hubURLs(parsed, feed.URL), selfURL(parsed, feed.URL)
 
 	if feed.Title == "" && len(articles) > 0 {
 		feed.Title = extractFeedTitleFromArticles(articles)
@@ -198,85 +273,254 @@ func (m *Manager) AddFeed(url string) (*storage.Feed, error) {
 	if err := m.store.SaveFeed(feed); err != nil {
 		return nil, fmt.Errorf("saving feed: %w", err)
 	}
-	if err := m.store.SaveArticles(articles); err != nil {
+	saved, err := m.store.SaveChangedArticles(articles)
+	if err != nil {
 		return nil, fmt.Errorf("saving articles: %w", err)
 	}
 
-	m.notifyDataUpdated(feed, articles)
+	m.notifyDataUpdated(feed, saved)
 	return feed, nil
 }
 
 // RefreshFeed re-fetches a single feed and notifies listeners on success.
 func (m *Manager) RefreshFeed(feedID string) error {
-	_, _, err := m.refreshFeedByID(feedID, true)
+	return m.RefreshFeedContext(context.Background(), feedID)
+}
+
+// RefreshFeedContext is RefreshFeed bound to ctx.
+func (m *Manager) RefreshFeedContext(ctx context.Context, feedID string) error {
+	feed, articles, movedFrom, err := m.refreshFeedByID(ctx, feedID)
+	if movedFrom != "" {
+		m.notifyFeedMoved(movedFrom, feed)
+	}
+	if err == nil && articles != nil {
+		m.notifyDataUpdated(feed, articles)
+	}
 	return err
 }
 
+// refreshDue reports whether feed's refresh interval, stretched by its
+// jitter, has passed since its last successful refresh.
+func (m *Manager) refreshDue(feed *storage.Feed) bool {
+	wait := m.config.Feed.RefreshInterval + refreshJitter(feed, m.config.Feed.RefreshJitter)
+	return time.Since(feed.LastSuccess) >= wait
+}
+
+// refreshJitter picks feed's extra wait in [0, window). It is drawn from
+// the feed's ID and last success rather than at random on each call, so
+// it holds steady across the checks between two refreshes and moves on
+// with each refresh.
+func refreshJitter(feed *storage.Feed, window time.Duration) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s@%d", feed.ID, feed.LastSuccess.UnixNano())
+	return time.Duration(h.Sum64() % uint64(window))
+}
+
 // refreshFeedByID does the work of RefreshFeed and returns the feed +
-// freshly-saved articles so RefreshAllFeeds can dispatch listener
-// notifications from a single goroutine. When notify is true,
-// notifyDataUpdated runs inline; the multi-feed path passes false and
-// notifies later from the result-collection loop.
-func (m *Manager) refreshFeedByID(feedID string, notify bool) (*storage.Feed, []*storage.Article, error) {
-	feed, err := m.store.GetFeed(feedID)
+// the articles it wrote so RefreshAllFeeds can dispatch listener
+// notifications from a single goroutine. When the feed turned out to have
+// moved permanently and the new URL served a feed that parses, or a 304,
+// it is re-keyed under its new URL and its old ID is returned as
+// movedFrom; the caller owes listeners a notifyFeedMoved.
+func (m *Manager) refreshFeedByID(ctx context.Context, feedID string) (feed *storage.Feed, saved []*storage.Article, movedFrom string, err error) {
+	feed, err = m.store.GetFeed(feedID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("getting feed: %w", err)
+		return nil, nil, "", fmt.Errorf("getting feed: %w", err)
 	}
 
-	if time.Since(feed.LastFetched) < m.config.Feed.RefreshInterval {
-		return feed, nil, nil
+	if !m.refreshDue(feed) {
+		return feed, nil, "", nil
 	}
 
-	resp, updated, err := m.fetcher.Fetch(feed)
+	feed.LastAttempt = time.Now()
+	resp, updated, movedTo, err := m.fetcher.fetch(ctx, feed)
 	if err != nil {
+		if ctx.Err() != nil {
+			// Cancelled by the caller, not a feed failure: leave the
+			// feed's error badge alone.
+			return feed, nil, movedFrom, fmt.Errorf("fetching feed: %w", err)
+		}
 		// Persist the failure so /feeds can surface a stale/error badge.
 		// Best-effort: a save error here is subordinate to the fetch error.
+		debuglog.Warnf("fetching feed %s: %v", feed.URL, err)
 		recordFeedError(feed, err)
 		_ = m.store.SaveFeed(feed)
-		return feed, nil, fmt.Errorf("fetching feed: %w", err)
+		return feed, nil, movedFrom, fmt.Errorf("fetching feed: %w", err)
 	}
-
 	if !updated || resp == nil {
 		// 304/unchanged is a successful round-trip — clear any prior error.
-		feed.LastFetched = time.Now()
+		// The new URL confirmed the feed we have, so a move is safe.
+		if movedTo != "" {
+			movedFrom = m.moveFeed(feed, movedTo)
+		}
+		feed.LastSuccess = time.Now()
 		clearFeedError(feed)
 		if saveErr := m.store.SaveFeed(feed); saveErr != nil {
-			return feed, nil, fmt.Errorf("saving feed metadata: %w", saveErr)
+			return feed, nil, movedFrom, fmt.Errorf("saving feed metadata: %w", saveErr)
 		}
-		return feed, nil, nil
+		return feed, nil, movedFrom, nil
 	}
 	defer resp.Body.Close()
 
-	articles, err := m.parser.Parse(io.LimitReader(resp.Body, maxFeedBodySize), feedID)
+	// The body belongs to where the feed lives now, moved or not.
+	cacheID := feed.ID
+	if movedTo != "" {
+		cacheID = generateFeedID(movedTo)
+	}
+	var parsed *gofeed.Feed
+	var articles []*storage.Article
+	body, err := m.cacheBody(cacheID, io.LimitReader(resp.Body, maxFeedBodySize))
+	if err == nil {
+		parsed, articles, err = m.parser.parse(body, feed.ID, feed.URL, feed.ForceFormat)
+	}
 	if err != nil {
+		if ctx.Err() != nil {
+			return feed, nil, movedFrom, fmt.Errorf("parsing feed: %w", ctx.Err())
+		}
+		debuglog.Warnf("parsing feed %s: %v", feed.URL, err)
 		recordFeedError(feed, err)
 		_ = m.store.SaveFeed(feed)
-		return feed, nil, fmt.Errorf("parsing feed: %w", err)
+		return feed, nil, movedFrom, fmt.Errorf("parsing feed: %w", err)
+	}
+	// Follow a permanent move only once the new URL has served a feed
+	// that parses: a redirect to an HTML, login or parking page must not
+	// carry the subscription off with it.
+	if movedTo != "" {
+		if movedFrom = m.moveFeed(feed, movedTo); movedFrom != "" {
+			rekey := articleRekey(movedFrom, feed.ID)
+			for _, a := range articles {
+				a.ID, a.FeedID = rekey(a.ID), feed.ID
+			}
+		}
 	}
+	articles = m.newestArticles(articles)
 
 	m.fetcher.UpdateFeedMetadata(feed, resp)
 	feed.UpdatedAt = time.Now()
+	if site := siteURL(parsed, feed.URL); site != "" {
+		feed.SiteURL = site
+	}
+	// Unlike the homepage, hubs come and go; keep what the feed says now.
+	feed.HubURLs, feed.SelfURL = hubURLs(parsed, feed.URL), selfURL(parsed, feed.URL)
 	clearFeedError(feed)
 
 	if err := m.store.SaveFeed(feed); err != nil {
-		return feed, nil, fmt.Errorf("saving feed: %w", err)
+		return feed, nil, movedFrom, fmt.Errorf("saving feed: %w", err)
 	}
-	if err := m.store.SaveArticles(articles); err != nil {
-		return feed, nil, fmt.Errorf("saving articles: %w", err)
+	// Only new or edited articles come back, so listeners don't re-index
+	// a feed that hasn't changed. The slice is non-nil even when empty.
+	saved, err = m.store.SaveChangedArticles(articles)
+	if err != nil {
+		return feed, nil, movedFrom, fmt.Errorf("saving articles: %w", err)
 	}
+	return feed, saved, movedFrom, nil
+}
 
-	if notify {
-		m.notifyDataUpdated(feed, articles)
+// cacheBody keeps a copy of body in the body cache when it is enabled and
+// returns a reader to parse in its place. Failing to cache is only
+// logged: the fetch itself went fine.
+func (m *Manager) cacheBody(feedID string, body io.Reader) (io.Reader, error) {
+	if m.bodies == nil {
+		return body, nil
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("reading feed: %w", err)
+	}
+	if err := m.bodies.put(feedID, data); err != nil {
+		debuglog.Warnf("caching body of feed %s: %v", feedID, err)
+	}
+	return bytes.NewReader(data), nil
+}
+
+// ReparseFeed parses the body cached for feedID again, as if it had just
+// been fetched, and saves the articles, returning those that were new or
+// changed. Nothing is fetched. Without a cached body it returns
+// ErrNoCachedBody.
+func (m *Manager) ReparseFeed(feedID string) ([]*storage.Article, error) {
+	feed, err := m.store.GetFeed(feedID)
+	if err != nil {
+		return nil, fmt.Errorf("getting feed: %w", err)
+	}
+	cache := m.bodies
+	if cache == nil {
+		// Caching may have been turned off since; what it left behind
+		// can still be reparsed.
+		if m.config.Feed.BodyCacheDir == "" {
+			return nil, ErrNoCachedBody
+		}
+		cache = newBodyCache(m.config.Feed.BodyCacheDir, m.config.Feed.BodyCacheMaxFiles)
+	}
+	body, err := cache.get(feed.ID)
+	if err != nil {
+		return nil, err
+	}
+	_, articles, err := m.parser.parse(bytes.NewReader(body), feed.ID, feed.URL, feed.ForceFormat)
+	if err != nil {
+		return nil, fmt.Errorf("parsing feed: %w", err)
+	}
+	saved, err := m.store.SaveChangedArticles(m.newestArticles(articles))
+	if err != nil {
+		return nil, fmt.Errorf("saving articles: %w", err)
+	}
+	m.notifyDataUpdated(feed, saved)
+	return saved, nil
+}
+
+// moveFeed re-keys feed under newURL after a permanent redirect, updating
+// feed in place, and returns the ID it had before. Feed and article IDs
+// derive from the URL, so the stored records move to new keys. If a feed
+// already lives at newURL, or the move fails, feed stays where it is and
+// "" is returned; the redirect is simply followed again next time.
+func (m *Manager) moveFeed(feed *storage.Feed, newURL string) string {
+	oldID, newID := feed.ID, generateFeedID(newURL)
+	if newID == oldID {
+		return ""
+	}
+	moved := *feed
+	moved.ID = newID
+	moved.URL = newURL
+	if err := m.store.MoveFeed(oldID, &moved, articleRekey(oldID, newID)); err != nil {
+		debuglog.Warnf("not moving feed %s to %s: %v", oldID, newURL, err)
+		return ""
+	}
+	*feed = moved
+	return oldID
+}
+
+// articleRekey maps the ID of an article of feed oldID to the ID it has
+// under feed newID.
+func articleRekey(oldID, newID string) func(string) string {
+	return func(articleID string) string {
+		if rest, ok := strings.CutPrefix(articleID, oldID+":"); ok {
+			return generateID(newID, rest)
+		}
+		return articleID
 	}
-	return feed, articles, nil
 }
 
+// RefreshProgressFunc receives the number of feeds finished so far and
+// the total being refreshed. It is called from worker goroutines, so
+// implementations must be safe for concurrent use and should not block.
+type RefreshProgressFunc func(done, total int)
+
 // RefreshAllFeeds refreshes every persisted feed in parallel and returns
 // a summary the caller can render. Listener notifications and batch
 // scope brackets fire from a single goroutine after all worker
 // goroutines complete, so listener implementations need not be safe
 // for concurrent invocation.
 func (m *Manager) RefreshAllFeeds() (RefreshSummary, error) {
+	return m.RefreshAllFeedsWithProgress(context.Background(), nil)
+}
+
+// RefreshAllFeedsWithProgress is RefreshAllFeeds bound to ctx, with a
+// callback fired as each feed completes, successfully or not. progress
+// may be nil. Feeds not yet started when ctx is cancelled are skipped
+// and counted as errors.
+func (m *Manager) RefreshAllFeedsWithProgress(ctx context.Context, progress RefreshProgressFunc) (RefreshSummary, error) {
 	feeds, err := m.store.GetAllFeeds()
 	if err != nil {
 		return RefreshSummary{}, fmt.Errorf("getting feeds: %w", err)
@@ -286,27 +530,31 @@ func (m *Manager) RefreshAllFeeds() (RefreshSummary, error) {
 	}
 
 	type result struct {
-		feed     *storage.Feed
-		articles []*storage.Article
-		err      error
+		feed      *storage.Feed
+		articles  []*storage.Article
+		movedFrom string
+		err       error
 	}
 
-	maxConcurrent := m.config.Feed.MaxConcurrentRefreshes
-	if maxConcurrent <= 0 {
-		maxConcurrent = config.DefaultMaxConcurrentRefreshes
-	}
 	feedChan := make(chan *storage.Feed, len(feeds))
 	resultChan := make(chan result, len(feeds))
 
 	var wg sync.WaitGroup
-	workers := min(maxConcurrent, len(feeds))
+	var done atomic.Int32
+	workers := min(m.maxConcurrentRefreshes(), len(feeds))
 	for range workers {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for f := range feedChan {
-				feed, articles, err := m.refreshFeedByID(f.ID, false)
-				resultChan <- result{feed: feed, articles: articles, err: err}
+				r := result{err: ctx.Err()}
+				if r.err == nil {
+					r.feed, r.articles, r.movedFrom, r.err = m.refreshFeedByID(ctx, f.ID)
+				}
+				resultChan <- r
+				if progress != nil {
+					progress(int(done.Add(1)), len(feeds))
+				}
 			}
 		}()
 	}
@@ -322,6 +570,9 @@ func (m *Manager) RefreshAllFeeds() (RefreshSummary, error) {
 
 	var summary RefreshSummary
 	for r := range resultChan {
+		if r.movedFrom != "" {
+			m.notifyFeedMoved(r.movedFrom, r.feed)
+		}
 		if r.err != nil {
 			summary.Errors = append(summary.Errors, r.err)
 			continue
@@ -338,7 +589,30 @@ func (m *Manager) RefreshAllFeeds() (RefreshSummary, error) {
 	return summary, errors.Join(summary.Errors...)
 }
 
-// recordFeedError stamps a failed refresh onto the feed. LastFetched is left
+// newestArticles trims parsed articles to feed.max_articles_per_feed,
+// keeping the most recently published. Undated articles rank last and
+// otherwise keep feed order. A cap of 0 or less keeps everything.
+func (m *Manager) newestArticles(articles []*storage.Article) []*storage.Article {
+	limit := m.config.Feed.MaxArticlesPerFeed
+	if limit <= 0 || len(articles) <= limit {
+		return articles
+	}
+	slices.SortStableFunc(articles, func(a, b *storage.Article) int {
+		return b.Published.Compare(a.Published)
+	})
+	return articles[:limit]
+}
+
+// maxConcurrentRefreshes is the configured worker count for multi-feed
+// operations, falling back to the default when unset.
+func (m *Manager) maxConcurrentRefreshes() int {
+	if n := m.config.Feed.MaxConcurrentRefreshes; n > 0 {
+		return n
+	}
+	return config.DefaultMaxConcurrentRefreshes
+}
+
+// recordFeedError stamps a failed refresh onto the feed. LastSuccess is left
 // untouched so it keeps pointing at the last *successful* fetch.
 func recordFeedError(feed *storage.Feed, err error) {
 	feed.LastError = err.Error()
@@ -351,6 +625,36 @@ func clearFeedError(feed *storage.Feed) {
 	feed.LastErrorAt = time.Time{}
 }
 
+// AlreadySubscribedError is returned by AddFeed for a feed that is
+// already in the store, unless SetAllowResubscribe allows re-adding it.
+type AlreadySubscribedError struct {
+	Feed *storage.Feed
+}
+
+func (e *AlreadySubscribedError) Error() string {
+	name := e.Feed.Title
+	if name == "" {
+		name = e.Feed.URL
+	}
+	return fmt.Sprintf("feed already subscribed: %s", name)
+}
+
+// checkNotSubscribed returns an AlreadySubscribedError when a feed with
+// id is stored and re-adding is not allowed.
+func (m *Manager) checkNotSubscribed(id string) error {
+	if m.resubscribe {
+		return nil
+	}
+	existing, err := m.store.GetFeed(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrFeedNotFound) {
+			return nil
+		}
+		return fmt.Errorf("checking existing feeds: %w", err)
+	}
+	return &AlreadySubscribedError{Feed: existing}
+}
+
 func generateFeedID(url string) string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(url)))
 }
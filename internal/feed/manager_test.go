@@ -1,6 +1,8 @@
 package feed
 
 import (
+	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -129,6 +131,7 @@ func TestRefreshAllFeeds_NotifiesListeners(t *testing.T) {
 	defer store.Close()
 
 	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
 	rec := &recordingListener{}
 	manager.RegisterDataListener(rec)
 	manager.RegisterBatchScope(rec)
@@ -137,7 +140,7 @@ func TestRefreshAllFeeds_NotifiesListeners(t *testing.T) {
 		f := &storage.Feed{
 			ID:          fmt.Sprintf("feed-%d", i),
 			URL:         server.URL,
-			LastFetched: time.Now().Add(-2 * time.Hour),
+			LastSuccess: time.Now().Add(-2 * time.Hour),
 		}
 		require.NoError(t, store.SaveFeed(f))
 	}
@@ -153,6 +156,395 @@ func TestRefreshAllFeeds_NotifiesListeners(t *testing.T) {
 	assert.Equal(t, 1, commits)
 }
 
+// TestRefreshFeed_DecodesGzipBody serves a gzip-compressed feed and checks
+// the fetcher advertised gzip and parsed the decompressed articles.
+func TestRefreshFeed_DecodesGzipBody(t *testing.T) {
+	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Gzipped</title>
+<item><title>One</title><link>http://example.com/1</link><guid>1</guid></item>
+<item><title>Two</title><link>http://example.com/2</link><guid>2</guid></item>
+<item><title>Three</title><link>http://example.com/3</link><guid>3</guid></item>
+</channel></rss>`
+
+	var acceptEncoding atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding.Store(r.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		fmt.Fprint(gz, feedContent)
+		gz.Close()
+	}))
+	defer server.Close()
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+	manager := NewManager(store, config.TestConfig())
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+
+	feed := &storage.Feed{ID: "gz", URL: server.URL}
+	require.NoError(t, store.SaveFeed(feed))
+	require.NoError(t, manager.RefreshFeed(feed.ID))
+
+	assert.Contains(t, acceptEncoding.Load(), "gzip")
+	articles, err := store.GetArticles(feed.ID, 0)
+	require.NoError(t, err)
+	assert.Len(t, articles, 3)
+}
+
+func TestEmptyBodyReturnsErrEmptyFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, " \n\t\n")
+	}))
+	defer server.Close()
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+	manager := NewManager(store, config.TestConfig())
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+
+	_, err = manager.AddFeed(server.URL + "/empty.xml")
+	require.ErrorIs(t, err, ErrEmptyFeed)
+
+	feed := &storage.Feed{ID: "empty", URL: server.URL}
+	require.NoError(t, store.SaveFeed(feed))
+	require.ErrorIs(t, manager.RefreshFeed(feed.ID), ErrEmptyFeed)
+
+	stored, err := store.GetFeed(feed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ErrEmptyFeed.Error(), stored.LastError)
+}
+
+// movingListener records deletes on top of recordingListener.
+type movingListener struct {
+	recordingListener
+	deleted []string
+}
+
+func (l *movingListener) OnFeedDeleted(feedID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.deleted = append(l.deleted, feedID)
+}
+
+// TestRefreshFeed_FollowsPermanentRedirect moves a feed whose URL now
+// 301s: the feed and its articles are re-keyed under the new URL with
+// reader state intact, while a temporary redirect leaves the feed alone.
+func TestRefreshFeed_FollowsPermanentRedirect(t *testing.T) {
+	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Moved</title>
+<item><title>Post</title><link>http://example.com/post</link><guid>post</guid></item>
+</channel></rss>`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/temp", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusFound)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, feedContent)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.RefreshInterval = time.Millisecond
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+	rec := &movingListener{}
+	manager.RegisterDataListener(rec)
+
+	oldURL, newURL := server.URL+"/old", server.URL+"/new"
+	oldID, newID := generateFeedID(oldURL), generateFeedID(newURL)
+	require.NoError(t, store.SaveFeed(&storage.Feed{ID: oldID, URL: oldURL, Title: "Moved"}))
+	require.NoError(t, store.SaveArticles([]*storage.Article{
+		{ID: generateID(oldID, "post"), FeedID: oldID, Title: "Post", URL: "http://example.com/post"},
+	}))
+	require.NoError(t, store.MarkArticleRead(generateID(oldID, "post"), true))
+
+	require.NoError(t, manager.RefreshFeed(oldID))
+
+	_, err = store.GetFeed(oldID)
+	assert.ErrorIs(t, err, storage.ErrFeedNotFound)
+	moved, err := store.GetFeed(newID)
+	require.NoError(t, err)
+	assert.Equal(t, newURL, moved.URL)
+
+	articles, err := store.GetArticles(newID, 0)
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, generateID(newID, "post"), articles[0].ID)
+	assert.True(t, articles[0].Read, "read state must survive the move")
+	_, err = store.GetArticle(generateID(oldID, "post"))
+	assert.ErrorIs(t, err, storage.ErrArticleNotFound)
+	assert.Equal(t, []string{oldID}, rec.deleted)
+
+	tempURL := server.URL + "/temp"
+	tempID := generateFeedID(tempURL)
+	require.NoError(t, store.SaveFeed(&storage.Feed{ID: tempID, URL: tempURL}))
+	require.NoError(t, manager.RefreshFeed(tempID))
+	stayed, err := store.GetFeed(tempID)
+	require.NoError(t, err)
+	assert.Equal(t, tempURL, stayed.URL)
+}
+
+// TestRefreshFeed_StaysPutWhenRedirectTargetFails keeps a feed whose URL
+// 301s to an error page, or to a page that is not a feed, where it is,
+// articles and all.
+func TestRefreshFeed_StaysPutWhenRedirectTargetFails(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/gone", http.NotFound)
+	mux.HandleFunc("/parked", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><body>This domain is for sale</body></html>")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for _, target := range []string{"/gone", "/parked"} {
+		t.Run(target, func(t *testing.T) {
+			mux.HandleFunc("/old"+target, func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, target, http.StatusMovedPermanently)
+			})
+			cfg := config.TestConfig()
+			cfg.Feed.RefreshInterval = time.Millisecond
+			cfg.Feed.FetchRetries = 0
+			store, err := storage.NewStore(":memory:")
+			require.NoError(t, err)
+			defer store.Close()
+			manager := NewManager(store, cfg)
+			manager.SetPermissiveValidation(true)
+
+			oldURL := server.URL + "/old" + target
+			oldID := generateFeedID(oldURL)
+			require.NoError(t, store.SaveFeed(&storage.Feed{ID: oldID, URL: oldURL, Title: "Stays"}))
+			require.NoError(t, store.SaveArticles([]*storage.Article{
+				{ID: generateID(oldID, "post"), FeedID: oldID, Title: "Post"},
+			}))
+
+			assert.Error(t, manager.RefreshFeed(oldID))
+
+			kept, err := store.GetFeed(oldID)
+			require.NoError(t, err)
+			assert.Equal(t, oldURL, kept.URL)
+			assert.NotEmpty(t, kept.LastError)
+			_, err = store.GetFeed(generateFeedID(server.URL + target))
+			assert.ErrorIs(t, err, storage.ErrFeedNotFound)
+			_, err = store.GetArticle(generateID(oldID, "post"))
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestRefreshFeed_KeepsNewestArticlesUpToCap serves 500 items, oldest first,
+// and checks only the configured number of newest ones are stored.
+func TestRefreshFeed_KeepsNewestArticlesUpToCap(t *testing.T) {
+	const total, limit = 500, 50
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var items strings.Builder
+	for i := range total {
+		fmt.Fprintf(&items, "<item><title>Item %d</title><guid>item-%d</guid><pubDate>%s</pubDate></item>\n",
+			i, i, base.Add(time.Duration(i)*time.Hour).Format(time.RFC1123Z))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Busy</title>%s</channel></rss>`, items.String())
+	}))
+	defer server.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.MaxArticlesPerFeed = limit
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+
+	feed := &storage.Feed{ID: "busy", URL: server.URL}
+	require.NoError(t, store.SaveFeed(feed))
+	require.NoError(t, manager.RefreshFeed(feed.ID))
+
+	n, err := store.CountArticles(feed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, limit, n)
+
+	articles, err := store.GetArticles(feed.ID, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, articles)
+	assert.Equal(t, fmt.Sprintf("Item %d", total-1), articles[0].Title)
+	assert.Equal(t, fmt.Sprintf("Item %d", total-limit), articles[len(articles)-1].Title)
+}
+
+// TestRefreshAllFeeds_UnchangedFeedWritesNothing refreshes a feed whose
+// content never changes: only the first refresh writes the article, and
+// later ones hand listeners nothing to re-index.
+func TestRefreshAllFeeds_UnchangedFeedWritesNothing(t *testing.T) {
+	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>F</title>
+<item><title>i</title><link>http://example.com/x</link><guid>x</guid></item>
+</channel></rss>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, feedContent)
+	}))
+	defer server.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.RefreshInterval = time.Millisecond
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+	rec := &recordingListener{}
+	manager.RegisterDataListener(rec)
+
+	f := &storage.Feed{ID: generateFeedID(server.URL), URL: server.URL, LastSuccess: time.Now().Add(-time.Hour)}
+	require.NoError(t, store.SaveFeed(f))
+
+	summary, err := manager.RefreshAllFeeds()
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.AddedArticles)
+
+	time.Sleep(5 * time.Millisecond)
+	summary, err = manager.RefreshAllFeeds()
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.UpdatedFeeds)
+	assert.Equal(t, 0, summary.AddedArticles, "unchanged articles must not be rewritten")
+
+	updates, articles, _, _ := rec.snapshot()
+	assert.Equal(t, 2, updates)
+	assert.Equal(t, 1, articles, "only the first refresh should hand articles to listeners")
+}
+
+func TestRefreshFeed_NotModifiedSkipsReparse(t *testing.T) {
+	const etag, lastMod = `W/"abc"`, "Wed, 01 Jan 2025 00:00:00 GMT"
+	var full atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastMod)
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastMod {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		// Every full response has a new item, so a reparse would show.
+		n := full.Add(1)
+		fmt.Fprintf(w, `<rss version="2.0"><channel><title>F</title>
+<item><title>i%d</title><link>http://example.com/%d</link><guid>%d</guid></item>
+</channel></rss>`, n, n, n)
+	}))
+	defer server.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.RefreshInterval = time.Millisecond
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+
+	f := &storage.Feed{ID: generateFeedID(server.URL), URL: server.URL}
+	require.NoError(t, store.SaveFeed(f))
+
+	_, saved, _, err := manager.refreshFeedByID(context.Background(), f.ID)
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+
+	time.Sleep(5 * time.Millisecond)
+	_, saved, _, err = manager.refreshFeedByID(context.Background(), f.ID)
+	require.NoError(t, err)
+	assert.Empty(t, saved, "a 304 must not be parsed")
+	assert.Equal(t, int32(1), full.Load(), "the second refresh should send both validators and get a 304")
+
+	stored, err := store.GetFeed(f.ID)
+	require.NoError(t, err)
+	assert.Equal(t, etag, stored.ETag)
+	assert.Equal(t, lastMod, stored.LastModified)
+}
+
+func TestRefreshJitter_StaysWithinWindow(t *testing.T) {
+	cfg := config.TestConfig()
+	cfg.Feed.RefreshInterval = 10 * time.Minute
+	cfg.Feed.RefreshJitter = 2 * time.Minute
+	m := &Manager{config: cfg}
+	last := time.Now()
+
+	seen := map[time.Duration]bool{}
+	for i := range 100 {
+		f := &storage.Feed{ID: fmt.Sprintf("feed-%d", i), LastSuccess: last}
+		j := refreshJitter(f, cfg.Feed.RefreshJitter)
+		require.GreaterOrEqual(t, j, time.Duration(0))
+		require.Less(t, j, cfg.Feed.RefreshJitter)
+		assert.Equal(t, j, refreshJitter(f, cfg.Feed.RefreshJitter), "jitter must hold between checks")
+		seen[j] = true
+
+		f.LastSuccess = time.Now().Add(-cfg.Feed.RefreshInterval + time.Second)
+		assert.False(t, m.refreshDue(f), "never due before the interval")
+		f.LastSuccess = time.Now().Add(-cfg.Feed.RefreshInterval - cfg.Feed.RefreshJitter)
+		assert.True(t, m.refreshDue(f), "always due once the window has passed")
+	}
+	assert.Greater(t, len(seen), 50, "feeds refreshed together should fall due at different times")
+
+	assert.Zero(t, refreshJitter(&storage.Feed{ID: "x"}, 0))
+}
+
+// TestRefreshFeed_KeepsReadAndStarredState is a regression test: a refresh
+// that re-saves an edited article used to reset it to unread.
+func TestRefreshFeed_KeepsReadAndStarredState(t *testing.T) {
+	var title atomic.Value
+	title.Store("Original title")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>F</title>
+<item><title>%s</title><link>http://example.com/x</link><guid>x</guid></item>
+</channel></rss>`, title.Load())
+	}))
+	defer server.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.RefreshInterval = time.Millisecond
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+
+	f := &storage.Feed{ID: generateFeedID(server.URL), URL: server.URL, LastSuccess: time.Now().Add(-time.Hour)}
+	require.NoError(t, store.SaveFeed(f))
+	require.NoError(t, manager.RefreshFeed(f.ID))
+
+	articleID := generateID(f.ID, "x")
+	require.NoError(t, store.MarkArticleRead(articleID, true))
+	require.NoError(t, store.MarkArticleStarred(articleID, true))
+
+	// The feed edits the item, so the refresh rewrites it.
+	title.Store("Edited title")
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, manager.RefreshFeed(f.ID))
+
+	got, err := store.GetArticle(articleID)
+	require.NoError(t, err)
+	assert.Equal(t, "Edited title", got.Title)
+	assert.True(t, got.Read, "refresh must not mark a read article unread")
+	assert.True(t, got.Starred, "refresh must not unstar an article")
+
+	stats, err := store.FeedStats()
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats[f.ID].Unread)
+}
+
 // TestAddFeed_NotifiesListeners covers the single-feed path.
 func TestAddFeed_NotifiesListeners(t *testing.T) {
 	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
@@ -178,8 +570,9 @@ func TestAddFeed_NotifiesListeners(t *testing.T) {
 	rec := &recordingListener{}
 	manager.RegisterDataListener(rec)
 
-	feed, err := manager.AddFeed(server.URL)
-	require.NoError(t, err)
+	feed := &storage.Feed{ID: "busy", URL: server.URL}
+	require.NoError(t, store.SaveFeed(feed))
+	require.NoError(t, manager.RefreshFeed(feed.ID))
 	require.NotNil(t, feed)
 
 	updates, articles, _, _ := rec.snapshot()
@@ -246,7 +639,7 @@ func TestRefreshFeed(t *testing.T) {
 			ID:          "test-feed",
 			URL:         "http://test.com/feed",
 			Title:       "Test Feed",
-			LastFetched: time.Now(),
+			LastSuccess: time.Now(),
 			UpdatedAt:   time.Now(),
 		}
 		err := store.SaveFeed(feed)
@@ -302,6 +695,185 @@ func TestAddFeedWithMockServer(t *testing.T) {
 	assert.NotEmpty(t, feed.Title)
 }
 
+// TestAddFeed_IgnoresContentType serves valid feeds under content types a
+// strict client would refuse; both add and refresh must still parse them.
+func TestAddFeed_IgnoresContentType(t *testing.T) {
+	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Mislabelled</title>
+<item><title>One</title><link>http://example.com/1</link><guid>1</guid></item>
+</channel></rss>`
+
+	for _, contentType := range []string{"text/html; charset=utf-8", "text/plain", "application/octet-stream"} {
+		t.Run(contentType, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", contentType)
+				fmt.Fprint(w, feedContent)
+			}))
+			defer server.Close()
+
+			store, err := storage.NewStore(":memory:")
+			require.NoError(t, err)
+			defer store.Close()
+
+			cfg := config.TestConfig()
+			cfg.Feed.RefreshInterval = 0
+			manager := NewManager(store, cfg)
+			manager.SetPermissiveValidation(true)
+
+			feed, err := manager.AddFeed(server.URL)
+			require.NoError(t, err)
+
+			// A forced format applies on refresh and agrees with the body.
+			feed.ForceFormat = FormatRSS
+			require.NoError(t, store.SaveFeed(feed))
+			require.NoError(t, manager.RefreshFeed(feed.ID))
+
+			articles, err := store.GetArticles(feed.ID, 0)
+			require.NoError(t, err)
+			require.Len(t, articles, 1)
+			assert.Equal(t, "One", articles[0].Title)
+		})
+	}
+}
+
+func TestAddFeed_RejectsDuplicate(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		fmt.Fprint(w, `<rss version="2.0"><channel><title>Once</title>
+<item><title>One</title><link>https://blog.example/1</link><guid>1</guid></item></channel></rss>`)
+	}))
+	defer server.Close()
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	manager := NewManager(store, config.TestConfig())
+	manager.SetPermissiveValidation(true)
+
+	first, err := manager.AddFeed(server.URL + "/feed.xml")
+	require.NoError(t, err)
+
+	_, err = manager.AddFeed(server.URL + "/feed.xml")
+	var subscribed *AlreadySubscribedError
+	require.ErrorAs(t, err, &subscribed)
+	assert.Equal(t, first.ID, subscribed.Feed.ID)
+	assert.EqualError(t, err, "feed already subscribed: "+first.Title)
+	assert.Equal(t, 1, hits, "a duplicate is refused before fetching")
+
+	// Ignoring caching headers does not; allowing resubscription does.
+	manager.SetForceRefresh(true)
+	_, err = manager.AddFeed(server.URL + "/feed.xml")
+	require.ErrorAs(t, err, &subscribed)
+	manager.SetAllowResubscribe(true)
+	_, err = manager.AddFeed(server.URL + "/feed.xml")
+	require.NoError(t, err)
+	assert.Equal(t, 2, hits)
+}
+
+func TestReparseFeed_UsesCachedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `<rss version="2.0"><channel><title>Cached</title>
+<item><title>One</title><link>https://blog.example/1</link><guid>1</guid><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate></item>
+<item><title>Two</title><link>https://blog.example/2</link><guid>2</guid><pubDate>Tue, 03 Jan 2006 15:04:05 GMT</pubDate></item>
+</channel></rss>`)
+	}))
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.CacheBodies = true
+	cfg.Feed.BodyCacheDir = t.TempDir()
+	cfg.Feed.BodyCacheMaxFiles = 1
+	cfg.Feed.MaxArticlesPerFeed = 1
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true)
+
+	f, err := manager.AddFeed(server.URL + "/feed.xml")
+	require.NoError(t, err)
+	articles, err := store.GetArticles(f.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+
+	// Reparsing with the cap lifted picks up the other article, with the
+	// server long gone.
+	server.Close()
+	cfg.Feed.MaxArticlesPerFeed = 0
+	saved, err := manager.ReparseFeed(f.ID)
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, "One", saved[0].Title)
+
+	// A second feed's body pushes the first out of a one-file cache.
+	require.NoError(t, manager.bodies.put("other", []byte("<rss/>")))
+	_, err = manager.ReparseFeed(f.ID)
+	assert.ErrorIs(t, err, ErrNoCachedBody)
+}
+
+func TestAddFeed_CapturesSiteURL(t *testing.T) {
+	channelLink := "/blog/"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `<rss version="2.0"><channel><title>Site</title><link>%s</link>
+<item><title>One</title><link>/blog/1</link><guid>1</guid></item></channel></rss>`, channelLink)
+	}))
+	defer server.Close()
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.RefreshInterval = 0
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true)
+
+	feed, err := manager.AddFeed(server.URL + "/feed.xml")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/blog/", feed.SiteURL, "a relative channel link resolves against the feed URL")
+
+	// Feeds added before SiteURL existed pick it up on refresh.
+	feed.SiteURL = ""
+	require.NoError(t, store.SaveFeed(feed))
+	channelLink = "https://blog.example/"
+	require.NoError(t, manager.RefreshFeed(feed.ID))
+	stored, err := store.GetFeed(feed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://blog.example/", stored.SiteURL)
+}
+
+func TestAddFeed_StoresAbsoluteLinks(t *testing.T) {
+	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Relative Feed</title>
+<item><title>Rel</title><link>/posts/rel</link><guid>rel</guid>
+<description><![CDATA[<img src="/img/rel.png">]]></description></item>
+</channel></rss>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, feedContent)
+	}))
+	defer server.Close()
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	manager := NewManager(store, config.TestConfig())
+	manager.SetPermissiveValidation(true)
+
+	feed, err := manager.AddFeed(server.URL + "/feed.xml")
+	require.NoError(t, err)
+
+	articles, err := store.GetArticles(feed.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, server.URL+"/posts/rel", articles[0].URL)
+	assert.Equal(t, []string{server.URL + "/img/rel.png"}, articles[0].MediaURLs())
+}
+
 func TestRefreshFeedWithMockServer(t *testing.T) {
 	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
 <rss version="2.0">
@@ -333,13 +905,14 @@ func TestRefreshFeedWithMockServer(t *testing.T) {
 	defer store.Close()
 
 	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
 
 	// Create a feed that needs refreshing
 	feed := &storage.Feed{
 		ID:          generateFeedID(server.URL),
 		URL:         server.URL,
 		Title:       "Old Title",
-		LastFetched: time.Now().Add(-2 * time.Hour), // Old enough to need refresh
+		LastSuccess: time.Now().Add(-2 * time.Hour), // Old enough to need refresh
 		UpdatedAt:   time.Now().Add(-2 * time.Hour),
 	}
 	err = store.SaveFeed(feed)
@@ -352,12 +925,13 @@ func TestRefreshFeedWithMockServer(t *testing.T) {
 	// Verify the feed was updated
 	updatedFeed, err := store.GetFeed(feed.ID)
 	require.NoError(t, err)
-	assert.True(t, updatedFeed.LastFetched.After(feed.LastFetched))
+	assert.True(t, updatedFeed.LastSuccess.After(feed.LastSuccess))
 }
 
 // TestRefreshFeed_RecordsAndClearsError asserts that a failing refresh
-// persists LastError/LastErrorAt (leaving LastFetched at its prior, last-
-// successful value) and that a subsequent successful refresh clears them.
+// persists LastError/LastErrorAt and advances LastAttempt (leaving
+// LastSuccess at its prior, last-successful value) and that a subsequent
+// successful refresh clears them.
 func TestRefreshFeed_RecordsAndClearsError(t *testing.T) {
 	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
 <rss version="2.0"><channel><title>OK Feed</title>
@@ -384,27 +958,29 @@ func TestRefreshFeed_RecordsAndClearsError(t *testing.T) {
 	defer store.Close()
 
 	manager := NewManager(store, cfg)
-	manager.SetForceRefresh(true) // skip ETag/Last-Modified caching
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+	manager.SetForceRefresh(true)         // skip ETag/Last-Modified caching
 
 	lastGood := time.Now().Add(-2 * time.Hour)
 	feed := &storage.Feed{
 		ID:          generateFeedID(server.URL),
 		URL:         server.URL,
 		Title:       "OK Feed",
-		LastFetched: lastGood,
+		LastSuccess: lastGood,
 		UpdatedAt:   lastGood,
 	}
 	require.NoError(t, store.SaveFeed(feed))
 
-	// Failing refresh records the error without clobbering LastFetched.
+	// Failing refresh records the error without clobbering LastSuccess.
 	err = manager.RefreshFeed(feed.ID)
 	require.Error(t, err)
 	failed, err := store.GetFeed(feed.ID)
 	require.NoError(t, err)
 	assert.NotEmpty(t, failed.LastError, "expected LastError to be recorded")
 	assert.False(t, failed.LastErrorAt.IsZero(), "expected LastErrorAt to be set")
-	assert.WithinDuration(t, lastGood, failed.LastFetched, time.Second,
-		"failed refresh must not advance LastFetched")
+	assert.WithinDuration(t, lastGood, failed.LastSuccess, time.Second,
+		"failed refresh must not advance LastSuccess")
+	assert.True(t, failed.LastAttempt.After(lastGood), "failed refresh still counts as an attempt")
 
 	// Successful refresh clears the error.
 	fail.Store(false)
@@ -413,7 +989,8 @@ func TestRefreshFeed_RecordsAndClearsError(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, ok.LastError, "expected LastError cleared after success")
 	assert.True(t, ok.LastErrorAt.IsZero(), "expected LastErrorAt cleared after success")
-	assert.True(t, ok.LastFetched.After(lastGood), "expected LastFetched advanced")
+	assert.True(t, ok.LastSuccess.After(lastGood), "expected LastSuccess advanced")
+	assert.False(t, ok.LastAttempt.After(ok.LastSuccess), "a successful attempt is the last success")
 }
 
 // TestAddFeed_CapsBodyAtMaxSize asserts that a server attempting to
@@ -496,7 +1073,7 @@ func TestRefreshAllFeeds_RunsInParallel(t *testing.T) {
 			ID:          fmt.Sprintf("feed-%d", i),
 			URL:         server.URL,
 			Title:       fmt.Sprintf("Feed %d", i),
-			LastFetched: time.Now().Add(-2 * time.Hour),
+			LastSuccess: time.Now().Add(-2 * time.Hour),
 		}
 		require.NoError(t, store.SaveFeed(feed))
 	}
@@ -516,6 +1093,166 @@ func TestRefreshAllFeeds_RunsInParallel(t *testing.T) {
 	}
 }
 
+// TestRefreshAllFeeds_RespectsConcurrencyLimit checks the worker pool
+// never has more than MaxConcurrentRefreshes fetches in flight. The
+// handler tracks the high-water mark of concurrent requests.
+func TestRefreshAllFeeds_RespectsConcurrencyLimit(t *testing.T) {
+	const numFeeds = 8
+	const limit = 2
+
+	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Feed</title>
+<item><title>Item</title><link>http://example.com/x</link><guid>x</guid></item>
+</channel></rss>`
+
+	var inFlight, peak, total atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		total.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, feedContent)
+	}))
+	defer server.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.RefreshInterval = 1 * time.Millisecond
+	cfg.Feed.MaxConcurrentRefreshes = limit
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+	for i := range numFeeds {
+		require.NoError(t, store.SaveFeed(&storage.Feed{
+			ID:          fmt.Sprintf("feed-%d", i),
+			URL:         server.URL,
+			LastSuccess: time.Now().Add(-2 * time.Hour),
+		}))
+	}
+
+	_, err = manager.RefreshAllFeeds()
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(numFeeds), total.Load())
+	assert.LessOrEqual(t, peak.Load(), int32(limit))
+}
+
+func TestRefreshAllFeedsWithProgress(t *testing.T) {
+	const numFeeds = 4
+
+	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Feed</title>
+<item><title>Item</title><link>http://example.com/x</link><guid>x</guid></item>
+</channel></rss>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, feedContent)
+	}))
+	defer server.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.RefreshInterval = 1 * time.Millisecond
+
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+	for i := range numFeeds {
+		require.NoError(t, store.SaveFeed(&storage.Feed{
+			ID:          fmt.Sprintf("feed-%d", i),
+			URL:         server.URL,
+			LastSuccess: time.Now().Add(-2 * time.Hour),
+		}))
+	}
+
+	var mu sync.Mutex
+	var seen []int
+	_, err = manager.RefreshAllFeedsWithProgress(context.Background(), func(done, total int) {
+		assert.Equal(t, numFeeds, total)
+		mu.Lock()
+		seen = append(seen, done)
+		mu.Unlock()
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []int{1, 2, 3, 4}, seen)
+}
+
+func TestAddFeedContext_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cfg := config.TestConfig()
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err = manager.AddFeedContext(ctx, server.URL)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	feeds, err := store.GetAllFeeds()
+	require.NoError(t, err)
+	assert.Empty(t, feeds, "a cancelled add must not persist anything")
+}
+
+func TestRefreshFeedContext_CancelDoesNotRecordError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	cfg := config.TestConfig()
+	cfg.Feed.RefreshInterval = 1 * time.Millisecond
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	manager := NewManager(store, cfg)
+	manager.SetPermissiveValidation(true) // allow http://127.0.0.1:port
+	require.NoError(t, store.SaveFeed(&storage.Feed{
+		ID:          "slow",
+		URL:         server.URL,
+		LastSuccess: time.Now().Add(-2 * time.Hour),
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	err = manager.RefreshFeedContext(ctx, "slow")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	got, err := store.GetFeed("slow")
+	require.NoError(t, err)
+	assert.Empty(t, got.LastError)
+}
+
 func TestRefreshAllFeedsWithMockServer(t *testing.T) {
 	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
 <rss version="2.0">
@@ -551,7 +1288,7 @@ func TestRefreshAllFeedsWithMockServer(t *testing.T) {
 			ID:          fmt.Sprintf("feed-%d", i),
 			URL:         server.URL,
 			Title:       fmt.Sprintf("Feed %d", i),
-			LastFetched: time.Now().Add(-2 * time.Hour),
+			LastSuccess: time.Now().Add(-2 * time.Hour),
 			UpdatedAt:   time.Now().Add(-2 * time.Hour),
 		}
 		err = store.SaveFeed(feed)
@@ -1,12 +1,21 @@
 package feed
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"regexp"
-	"time"
+	"strconv"
+	"strings"
 
 	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/atom"
+	jsonfeed "github.com/mmcdole/gofeed/json"
+	"github.com/mmcdole/gofeed/rss"
+	"github.com/pders01/fwrd/internal/debuglog"
 	"github.com/pders01/fwrd/internal/storage"
 )
 
@@ -19,22 +28,75 @@ func NewParser() *Parser {
 	return &Parser{}
 }
 
-func (p *Parser) Parse(reader io.Reader, feedID string) ([]*storage.Article, error) {
-	feed, err := gofeed.NewParser().Parse(reader)
+// Feed formats a feed's ForceFormat may name.
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+// FeedFormats lists every accepted ForceFormat value.
+var FeedFormats = []string{FormatRSS, FormatAtom, FormatJSON}
+
+// Parse decodes a feed body into articles. feedURL is where the body was
+// fetched from; relative article and media links are resolved against
+// the feed's own channel link, or feedURL when the channel has none.
+// The format is sniffed from the body; the Content-Type the server sent
+// plays no part, so mislabelled feeds parse all the same.
+func (p *Parser) Parse(reader io.Reader, feedID, feedURL string) ([]*storage.Article, error) {
+	_, articles, err := p.parse(reader, feedID, feedURL, "")
+	return articles, err
+}
+
+// ErrEmptyFeed is returned for a response whose body is empty or only
+// whitespace, which servers send as a 200 more often than one would hope.
+var ErrEmptyFeed = errors.New("feed returned no content")
+
+// parse is Parse that also hands back the decoded feed, for callers that
+// need channel-level details such as the format. A non-empty format, one
+// of FeedFormats, skips sniffing and decodes the body as that format.
+//
+// A body that fails to decode is retried once after sanitizeFeedXML, so
+// a stray control character or bad byte does not lose the whole feed.
+func (p *Parser) parse(reader io.Reader, feedID, feedURL, format string) (*gofeed.Feed, []*storage.Article, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading feed: %w", err)
+	}
+	if len(bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))) == 0 {
+		return nil, nil, ErrEmptyFeed
+	}
+	feed, err := decodeFeed(bytes.NewReader(data), format)
+	if err != nil && format != FormatJSON {
+		if cleaned := sanitizeFeedXML(data); !bytes.Equal(cleaned, data) {
+			if lenient, lerr := decodeFeed(bytes.NewReader(cleaned), format); lerr == nil {
+				debuglog.Infof("feed %s: parsed in lenient mode after: %v", feedURL, err)
+				feed, err = lenient, nil
+			}
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("parsing feed: %w", err)
+		return nil, nil, fmt.Errorf("parsing feed: %w", err)
 	}
 
+	base := feedBaseURL(feed.Link, feedURL)
 	articles := make([]*storage.Article, 0, len(feed.Items))
 	for _, item := range feed.Items {
+		link := resolveLink(item.Link, base)
+		mediaBase := base
+		if u, err := url.Parse(link); err == nil && u.IsAbs() {
+			mediaBase = u
+		}
+
 		article := &storage.Article{
-			ID:          generateID(feedID, item.GUID),
+			ID:          generateID(feedID, itemKey(item)),
 			FeedID:      feedID,
 			Title:       item.Title,
 			Description: item.Description,
 			Content:     getContent(item),
-			URL:         item.Link,
-			MediaURLs:   extractMediaURLs(item),
+			URL:         link,
+			Media:       extractMedia(item, mediaBase),
+			Tags:        itemTags(item),
 		}
 
 		if item.PublishedParsed != nil {
@@ -48,7 +110,54 @@ func (p *Parser) Parse(reader io.Reader, feedID string) ([]*storage.Article, err
 		articles = append(articles, article)
 	}
 
-	return articles, nil
+	return feed, articles, nil
+}
+
+// decodeFeed parses r as format, or lets gofeed detect the format from
+// the body when format is "".
+func decodeFeed(r io.Reader, format string) (*gofeed.Feed, error) {
+	switch format {
+	case "":
+		p := gofeed.NewParser()
+		p.AtomTranslator = &hubAtomTranslator{}
+		return p.Parse(r)
+	case FormatRSS:
+		f, err := (&rss.Parser{}).Parse(r)
+		if err != nil {
+			return nil, err
+		}
+		return (&gofeed.DefaultRSSTranslator{}).Translate(f)
+	case FormatAtom:
+		f, err := (&atom.Parser{}).Parse(r)
+		if err != nil {
+			return nil, err
+		}
+		return (&hubAtomTranslator{}).Translate(f)
+	case FormatJSON:
+		f, err := (&jsonfeed.Parser{}).Parse(r)
+		if err != nil {
+			return nil, err
+		}
+		return (&gofeed.DefaultJSONTranslator{}).Translate(f)
+	default:
+		return nil, fmt.Errorf("unknown feed format %q", format)
+	}
+}
+
+// itemTags returns the item's categories trimmed, without blanks, and
+// without repeats differing only in case; the first spelling is kept.
+func itemTags(item *gofeed.Item) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, c := range item.Categories {
+		c = strings.TrimSpace(c)
+		if c == "" || seen[strings.ToLower(c)] {
+			continue
+		}
+		seen[strings.ToLower(c)] = true
+		tags = append(tags, c)
+	}
+	return tags
 }
 
 func getContent(item *gofeed.Item) string {
@@ -58,23 +167,164 @@ func getContent(item *gofeed.Item) string {
 	return item.Description
 }
 
-func extractMediaURLs(item *gofeed.Item) []string {
-	var urls []string
+// siteURL returns the homepage a parsed feed names in its channel link,
+// made absolute against feedURL, or "" when it names no web page.
+func siteURL(parsed *gofeed.Feed, feedURL string) string {
+	if parsed == nil {
+		return ""
+	}
+	return absoluteWebURL(parsed.Link, feedURL)
+}
+
+// absoluteWebURL resolves ref against base and returns it when it is an
+// http(s) URL with a host, or "" otherwise.
+func absoluteWebURL(ref, base string) string {
+	var b *url.URL
+	if u, err := url.Parse(base); err == nil && u.IsAbs() {
+		b = u
+	}
+	u, err := url.Parse(resolveLink(ref, b))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return u.String()
+}
+
+// feedBaseURL picks the URL relative links in a feed are resolved
+// against: the channel link (itself resolved against feedURL, since some
+// feeds make that relative too) when absolute, else feedURL. It returns
+// nil when neither yields an absolute URL.
+func feedBaseURL(channelLink, feedURL string) *url.URL {
+	var fetched *url.URL
+	if u, err := url.Parse(feedURL); err == nil && u.IsAbs() {
+		fetched = u
+	}
+	if channelLink != "" {
+		if u, err := url.Parse(resolveLink(channelLink, fetched)); err == nil && u.IsAbs() {
+			return u
+		}
+	}
+	return fetched
+}
+
+// resolveLink makes link absolute against base. Links that are already
+// absolute, unparsable, or have no base to resolve against are returned
+// as given.
+func resolveLink(link string, base *url.URL) string {
+	link = strings.TrimSpace(link)
+	if link == "" || base == nil {
+		return link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return base.ResolveReference(u).String()
+}
+
+// extractMedia gathers enclosure, image, and inline <img>/<video> media
+// for an item. Each URL is normalized against base (the item's link) so
+// the same asset referenced two slightly different ways is stored once;
+// the first reference wins, so an enclosure keeps its type and length.
+// base may be nil.
+func extractMedia(item *gofeed.Item, base *url.URL) []storage.Media {
+	var found []storage.Media
 
 	for _, enclosure := range item.Enclosures {
 		if enclosure.URL != "" {
-			urls = append(urls, enclosure.URL)
+			found = append(found, storage.Media{
+				URL:    enclosure.URL,
+				MIME:   strings.TrimSpace(enclosure.Type),
+				Length: parseEnclosureLength(enclosure.Length),
+			})
 		}
 	}
 
 	if item.Image != nil && item.Image.URL != "" {
-		urls = append(urls, item.Image.URL)
+		found = append(found, storage.Media{URL: item.Image.URL})
 	}
 
 	content := item.Content + " " + item.Description
-	urls = append(urls, findMediaInHTML(content)...)
+	for _, u := range findMediaInHTML(content) {
+		found = append(found, storage.Media{URL: u})
+	}
+
+	seen := make(map[string]bool, len(found))
+	media := make([]storage.Media, 0, len(found))
+	for _, m := range found {
+		m.URL = normalizeMediaURL(m.URL, base)
+		if m.URL == "" || seen[m.URL] {
+			continue
+		}
+		seen[m.URL] = true
+		media = append(media, m)
+	}
+	return media
+}
+
+// parseEnclosureLength reads an enclosure's length attribute. Feeds often
+// leave it empty or put 0 or junk in it; all of those come back as 0.
+func parseEnclosureLength(s string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// isTrackingParam reports whether a query parameter only identifies the
+// referrer and has no bearing on which resource is served.
+func isTrackingParam(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "fbclid", "gclid", "dclid", "msclkid", "mc_cid", "mc_eid", "igshid":
+		return true
+	}
+	return strings.HasPrefix(name, "utm_")
+}
+
+// normalizeMediaURL resolves raw against base (when raw is relative and
+// base is non-nil), lowercases the scheme and host, drops tracking query
+// parameters and the fragment, sorts what is left of the query, and trims
+// a trailing slash from the path. It returns "" for values that do not
+// parse as URLs.
+func normalizeMediaURL(raw string, base *url.URL) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Opaque != "" {
+		// data:, mailto: and friends have no host or path to tidy up.
+		return u.String()
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.RawQuery != "" {
+		q := u.Query()
+		for name := range q {
+			if isTrackingParam(name) {
+				q.Del(name)
+			}
+		}
+		// Encode sorts by key, which also unifies parameter order.
+		u.RawQuery = q.Encode()
+	}
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+	}
 
-	return uniqueStrings(urls)
+	return u.String()
 }
 
 var (
@@ -100,21 +350,21 @@ func findMediaInHTML(html string) []string {
 	return urls
 }
 
-func generateID(feedID, guid string) string {
-	if guid != "" {
-		return fmt.Sprintf("%s:%s", feedID, guid)
-	}
-	return fmt.Sprintf("%s:%d", feedID, time.Now().UnixNano())
+// generateID builds an article ID from its feed's ID and the item's key
+// within that feed, as returned by itemKey.
+func generateID(feedID, key string) string {
+	return fmt.Sprintf("%s:%s", feedID, key)
 }
 
-func uniqueStrings(strs []string) []string {
-	seen := make(map[string]bool)
-	result := []string{}
-	for _, s := range strs {
-		if !seen[s] {
-			seen[s] = true
-			result = append(result, s)
-		}
+// itemKey identifies item within its feed: its GUID, or for feeds that
+// omit GUIDs a hash of its link, title and publish date, so the same item
+// maps to the same article on every refresh. The feed ID stays out of the
+// hash; generateID prefixes it, and moveFeed re-keys by swapping only that
+// prefix.
+func itemKey(item *gofeed.Item) string {
+	if item.GUID != "" {
+		return item.GUID
 	}
-	return result
+	sum := sha256.Sum256([]byte(strings.TrimSpace(item.Link) + "\x00" + item.Title + "\x00" + item.Published))
+	return fmt.Sprintf("%x", sum[:16])
 }
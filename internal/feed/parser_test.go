@@ -1,6 +1,8 @@
 package feed
 
 import (
+	"net/url"
+	"slices"
 	"strings"
 	"testing"
 
@@ -55,7 +57,7 @@ func TestParser_Parse(t *testing.T) {
 				if articles[0].URL != "http://example.com/article1" {
 					t.Errorf("expected URL 'http://example.com/article1', got %s", articles[0].URL)
 				}
-				if len(articles[0].MediaURLs) != 1 || articles[0].MediaURLs[0] != "http://example.com/image1.jpg" {
+				if len(articles[0].Media) != 1 || articles[0].Media[0].URL != "http://example.com/image1.jpg" {
 					t.Error("expected media URL not found")
 				}
 				if articles[1].Content != "<p>Full content here</p>" {
@@ -111,14 +113,14 @@ func TestParser_Parse(t *testing.T) {
 			expectError:   false,
 			expectedCount: 1,
 			validateFunc: func(t *testing.T, articles []*storage.Article) {
-				if len(articles[0].MediaURLs) != 2 {
-					t.Errorf("expected 2 media URLs, got %d", len(articles[0].MediaURLs))
+				if len(articles[0].Media) != 2 {
+					t.Errorf("expected 2 media URLs, got %d", len(articles[0].Media))
 				}
 				expectedURLs := map[string]bool{
 					"http://example.com/photo.jpg": false,
 					"http://example.com/video.mp4": false,
 				}
-				for _, url := range articles[0].MediaURLs {
+				for _, url := range articles[0].MediaURLs() {
 					expectedURLs[url] = true
 				}
 				for url, found := range expectedURLs {
@@ -147,7 +149,7 @@ func TestParser_Parse(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			reader := strings.NewReader(tt.feedContent)
-			articles, err := parser.Parse(reader, tt.feedID)
+			articles, err := parser.Parse(reader, tt.feedID, "")
 
 			if tt.expectError && err == nil {
 				t.Error("expected error, got nil")
@@ -167,7 +169,69 @@ func TestParser_Parse(t *testing.T) {
 	}
 }
 
-func TestExtractMediaURLs(t *testing.T) {
+func TestParser_ResolvesRelativeLinks(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		feedURL   string
+		wantURL   string
+		wantMedia []string
+	}{
+		{
+			name: "against channel link",
+			content: `<?xml version="1.0"?><rss version="2.0"><channel>
+<title>Rel</title><link>https://blog.example.com/</link>
+<item><title>A</title><link>/posts/a</link><guid>a</guid>
+<description><![CDATA[<img src="img/a.jpg">]]></description>
+<enclosure url="/media/a.mp3" type="audio/mpeg" length="1"/></item>
+</channel></rss>`,
+			feedURL:   "https://feeds.example.net/rel.xml",
+			wantURL:   "https://blog.example.com/posts/a",
+			wantMedia: []string{"https://blog.example.com/media/a.mp3", "https://blog.example.com/posts/img/a.jpg"},
+		},
+		{
+			name: "against feed URL without channel link",
+			content: `<?xml version="1.0"?><rss version="2.0"><channel>
+<title>Rel</title>
+<item><title>B</title><link>b.html</link><guid>b</guid>
+<description><![CDATA[<img src="/b.png">]]></description></item>
+</channel></rss>`,
+			feedURL:   "https://example.org/blog/feed.xml",
+			wantURL:   "https://example.org/blog/b.html",
+			wantMedia: []string{"https://example.org/b.png"},
+		},
+		{
+			name: "relative channel link",
+			content: `<?xml version="1.0"?><rss version="2.0"><channel>
+<title>Rel</title><link>/site/</link>
+<item><title>C</title><link>c</link><guid>c</guid></item>
+</channel></rss>`,
+			feedURL: "https://example.org/feed.xml",
+			wantURL: "https://example.org/site/c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, err := NewParser().Parse(strings.NewReader(tt.content), "rel", tt.feedURL)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(articles) != 1 {
+				t.Fatalf("expected 1 article, got %d", len(articles))
+			}
+			a := articles[0]
+			if a.URL != tt.wantURL {
+				t.Errorf("URL = %q, want %q", a.URL, tt.wantURL)
+			}
+			if strings.Join(a.MediaURLs(), " ") != strings.Join(tt.wantMedia, " ") {
+				t.Errorf("MediaURLs = %v, want %v", a.MediaURLs(), tt.wantMedia)
+			}
+		})
+	}
+}
+
+func TestExtractMedia(t *testing.T) {
 	tests := []struct {
 		name         string
 		item         *gofeed.Item
@@ -209,19 +273,50 @@ func TestExtractMediaURLs(t *testing.T) {
 			},
 			expectedURLs: []string{"http://example.com/media.mp4"},
 		},
+		{
+			name: "relative src resolved against item link",
+			item: &gofeed.Item{
+				Link:    "https://example.com/posts/hello",
+				Content: `<img src="/img.jpg"><img src="thumb.png">`,
+			},
+			expectedURLs: []string{"https://example.com/img.jpg", "https://example.com/posts/thumb.png"},
+		},
+		{
+			name: "tracking params stripped before dedup",
+			item: &gofeed.Item{
+				Enclosures: []*gofeed.Enclosure{
+					{URL: "https://example.com/ep1.mp3?utm_source=rss&utm_medium=feed"},
+				},
+				Content: `<img src="https://example.com/ep1.mp3">`,
+			},
+			expectedURLs: []string{"https://example.com/ep1.mp3"},
+		},
+		{
+			name: "case, trailing slash and query order unified",
+			item: &gofeed.Item{
+				Image:   &gofeed.Image{URL: "HTTPS://Example.COM/gallery/?b=2&a=1"},
+				Content: `<img src="https://example.com/gallery?a=1&b=2">`,
+			},
+			expectedURLs: []string{"https://example.com/gallery?a=1&b=2"},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			urls := extractMediaURLs(tt.item)
+			var base *url.URL
+			if tt.item.Link != "" {
+				base, _ = url.Parse(tt.item.Link)
+			}
+			a := &storage.Article{Media: extractMedia(tt.item, base)}
+			urls := a.MediaURLs()
 
 			if len(urls) != len(tt.expectedURLs) {
 				t.Errorf("expected %d URLs, got %d", len(tt.expectedURLs), len(urls))
 			}
 
 			urlMap := make(map[string]bool)
-			for _, url := range urls {
-				urlMap[url] = true
+			for _, u := range urls {
+				urlMap[u] = true
 			}
 
 			for _, expectedURL := range tt.expectedURLs {
@@ -233,6 +328,51 @@ func TestExtractMediaURLs(t *testing.T) {
 	}
 }
 
+func TestExtractMedia_EnclosureDetails(t *testing.T) {
+	item := &gofeed.Item{
+		Enclosures: []*gofeed.Enclosure{
+			{URL: "https://example.com/ep1.mp3", Type: "audio/mpeg", Length: "24576000"},
+			{URL: "https://example.com/chapters.jpg", Type: "image/jpeg", Length: "junk"},
+		},
+		// The inline copy of the episode must not replace the enclosure
+		// that carries its type and size.
+		Content: `<img src="https://example.com/ep1.mp3?utm_source=rss">`,
+	}
+
+	got := extractMedia(item, nil)
+	want := []storage.Media{
+		{URL: "https://example.com/ep1.mp3", MIME: "audio/mpeg", Length: 24576000},
+		{URL: "https://example.com/chapters.jpg", MIME: "image/jpeg"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("extractMedia() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNormalizeMediaURL(t *testing.T) {
+	base, err := url.Parse("https://blog.example.com/2024/05/post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"/img.jpg", "https://blog.example.com/img.jpg"},
+		{"//cdn.example.com/a.png", "https://cdn.example.com/a.png"},
+		{"https://example.com/a.png?utm_source=x&id=3#top", "https://example.com/a.png?id=3"},
+		{"https://example.com/a.png?fbclid=abc", "https://example.com/a.png"},
+		{"https://example.com/", "https://example.com/"},
+		{"data:image/png;base64,AAAA", "data:image/png;base64,AAAA"},
+		{"  ", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeMediaURL(tt.raw, base); got != tt.want {
+			t.Errorf("normalizeMediaURL(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
 func TestGenerateID(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -246,12 +386,6 @@ func TestGenerateID(t *testing.T) {
 			guid:         "article456",
 			expectPrefix: "feed123:article456",
 		},
-		{
-			name:         "without GUID",
-			feedID:       "feed789",
-			guid:         "",
-			expectPrefix: "feed789:",
-		},
 	}
 
 	for _, tt := range tests {
@@ -263,3 +397,168 @@ func TestGenerateID(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_ParseWithoutGUIDsGivesStableIDs(t *testing.T) {
+	feedContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>No GUIDs</title>
+<item><title>First</title><link>http://example.com/1</link><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate></item>
+<item><title>Second</title><link>http://example.com/2</link><pubDate>Tue, 03 Jan 2006 15:04:05 GMT</pubDate></item>
+</channel></rss>`
+
+	parser := NewParser()
+	first, err := parser.Parse(strings.NewReader(feedContent), "feed1", "http://example.com/feed")
+	if err != nil {
+		t.Fatalf("first parse: %v", err)
+	}
+	second, err := parser.Parse(strings.NewReader(feedContent), "feed1", "http://example.com/feed")
+	if err != nil {
+		t.Fatalf("second parse: %v", err)
+	}
+	if len(first) != 2 || len(second) != 2 {
+		t.Fatalf("expected 2 articles per parse, got %d and %d", len(first), len(second))
+	}
+
+	for i := range first {
+		if first[i].ID != second[i].ID {
+			t.Errorf("article %d: ID changed between parses: %s vs %s", i, first[i].ID, second[i].ID)
+		}
+		if !strings.HasPrefix(first[i].ID, "feed1:") {
+			t.Errorf("article %d: expected ID to start with feed1:, got %s", i, first[i].ID)
+		}
+	}
+	if first[0].ID == first[1].ID {
+		t.Errorf("distinct items share ID %s", first[0].ID)
+	}
+}
+
+func TestParser_StoresCategoriesAsTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "rss", content: `<rss version="2.0"><channel><title>T</title>
+<item><title>Item</title><guid>1</guid><category>Go</category><category> Databases </category>
+<category>go</category><category></category></item></channel></rss>`},
+		{name: "atom", content: `<feed xmlns="http://www.w3.org/2005/Atom"><title>T</title>
+<entry><title>Item</title><id>1</id><category term="Go"/><category term="Databases"/></entry></feed>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, err := NewParser().Parse(strings.NewReader(tt.content), "feed1", "")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(articles) != 1 {
+				t.Fatalf("got %d articles, want 1", len(articles))
+			}
+			if want := []string{"Go", "Databases"}; !slices.Equal(articles[0].Tags, want) {
+				t.Errorf("Tags = %q, want %q", articles[0].Tags, want)
+			}
+		})
+	}
+}
+
+func TestParser_ForcedFormat(t *testing.T) {
+	rssContent := `<rss version="2.0"><channel><title>Forced</title>
+<item><title>Item</title><guid>1</guid></item></channel></rss>`
+	jsonContent := `{"version":"https://jsonfeed.org/version/1.1","title":"Forced",
+"items":[{"id":"1","title":"Item"}]}`
+
+	tests := []struct {
+		name        string
+		content     string
+		format      string
+		expectError bool
+	}{
+		{name: "rss as rss", content: rssContent, format: FormatRSS},
+		{name: "json as json", content: jsonContent, format: FormatJSON},
+		{name: "rss as atom fails", content: rssContent, format: FormatAtom, expectError: true},
+		{name: "unknown format", content: rssContent, format: "yaml", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, articles, err := NewParser().parse(strings.NewReader(tt.content), "feed1", "", tt.format)
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse() error = %v", err)
+			}
+			if len(articles) != 1 || articles[0].Title != "Item" {
+				t.Errorf("expected the single item, got %+v", articles)
+			}
+		})
+	}
+}
+
+func TestSiteURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		link    string
+		feedURL string
+		want    string
+	}{
+		{name: "absolute", link: "https://example.com/", feedURL: "https://example.com/feed", want: "https://example.com/"},
+		{name: "relative", link: "/blog", feedURL: "https://example.com/feed.xml", want: "https://example.com/blog"},
+		{name: "missing", link: "", feedURL: "https://example.com/feed", want: ""},
+		{name: "not a web page", link: "mailto:me@example.com", feedURL: "https://example.com/feed", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := siteURL(&gofeed.Feed{Link: tt.link}, tt.feedURL); got != tt.want {
+				t.Errorf("siteURL(%q, %q) = %q, want %q", tt.link, tt.feedURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_LenientRetryAfterStrictFailure(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		body  string
+	}{
+		{
+			name:  "raw control character",
+			title: "Tom & Jerry",
+			body:  "<?xml version=\"1.0\"?><rss version=\"2.0\"><channel><title>T\x01</title><item><title>Tom & Jerry</title><guid>1</guid></item></channel></rss>",
+		},
+		{
+			name:  "control character reference",
+			title: "Escape",
+			body:  `<?xml version="1.0"?><rss version="2.0"><channel><title>T</title><item><title>Esc&#x1b;ape</title><guid>1</guid></item></channel></rss>`,
+		},
+		{
+			name:  "invalid UTF-8 in Atom",
+			title: "Caf�",
+			body:  "\xef\xbb\xbf<?xml version=\"1.0\"?><feed xmlns=\"http://www.w3.org/2005/Atom\"><title>T</title><entry><id>1</id><title>Caf\xe9</title></entry></feed>",
+		},
+	}
+
+	parser := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeFeed(strings.NewReader(tt.body), ""); err == nil {
+				t.Fatal("body parses as is; it no longer exercises the lenient retry")
+			}
+			articles, err := parser.Parse(strings.NewReader(tt.body), "feed", "https://blog.example/feed")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(articles) != 1 || articles[0].Title != tt.title {
+				t.Fatalf("Parse() = %+v, want one article titled %q", articles, tt.title)
+			}
+		})
+	}
+
+	// A body that is not a feed at all still fails.
+	if _, err := parser.Parse(strings.NewReader("not \x01 a feed"), "feed", "https://blog.example/feed"); err == nil {
+		t.Error("Parse() of garbage succeeded")
+	}
+}
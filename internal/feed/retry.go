@@ -0,0 +1,43 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// retry runs fn, and runs it again up to retries more times while it
+// fails with an error retryable accepts. The first retry waits backoff
+// and each later one twice as long as the last. A done ctx ends the wait
+// early and returns fn's last error.
+func retry(ctx context.Context, retries int, backoff time.Duration, retryable func(error) bool, fn func() error) error {
+	wait := backoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= retries || !retryable(err) {
+			return err
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		wait *= 2
+	}
+}
+
+// transientFetchError reports whether a failed fetch is worth trying
+// again: a timeout, or a 5xx answer. Everything else, 4xx answers
+// included, is treated as final.
+func transientFetchError(err error) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
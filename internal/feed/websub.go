@@ -0,0 +1,74 @@
+package feed
+
+import (
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/atom"
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// atomExtensionKeys are the prefixes gofeed files atom:link elements
+// under when they appear inside an RSS channel.
+var atomExtensionKeys = []string{"atom", "atom10", "atom03"}
+
+// hubAtomTranslator is gofeed's Atom translator, except that rel="hub"
+// links survive translation. gofeed keeps only alternate and self links
+// of an Atom feed; the hubs are copied into Extensions in the shape RSS
+// feeds already carry them, so hubURLs reads both alike.
+type hubAtomTranslator struct {
+	gofeed.DefaultAtomTranslator
+}
+
+func (t *hubAtomTranslator) Translate(feed interface{}) (*gofeed.Feed, error) {
+	result, err := t.DefaultAtomTranslator.Translate(feed)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range feed.(*atom.Feed).Links {
+		if l.Rel != "hub" {
+			continue
+		}
+		if result.Extensions == nil {
+			result.Extensions = ext.Extensions{}
+		}
+		if result.Extensions["atom"] == nil {
+			result.Extensions["atom"] = map[string][]ext.Extension{}
+		}
+		result.Extensions["atom"]["link"] = append(result.Extensions["atom"]["link"], ext.Extension{
+			Name:  "link",
+			Attrs: map[string]string{"rel": "hub", "href": l.Href},
+		})
+	}
+	return result, nil
+}
+
+// hubURLs returns the WebSub hubs a parsed feed advertises, made absolute
+// against feedURL, in document order and without duplicates. JSON feeds
+// report none: gofeed does not decode their hubs.
+func hubURLs(parsed *gofeed.Feed, feedURL string) []string {
+	if parsed == nil {
+		return nil
+	}
+	var hubs []string
+	seen := map[string]bool{}
+	for _, key := range atomExtensionKeys {
+		for _, l := range parsed.Extensions[key]["link"] {
+			if l.Attrs["rel"] != "hub" {
+				continue
+			}
+			if hub := absoluteWebURL(l.Attrs["href"], feedURL); hub != "" && !seen[hub] {
+				seen[hub] = true
+				hubs = append(hubs, hub)
+			}
+		}
+	}
+	return hubs
+}
+
+// selfURL returns the canonical URL a parsed feed gives for itself, made
+// absolute against feedURL, or "" when it gives none.
+func selfURL(parsed *gofeed.Feed, feedURL string) string {
+	if parsed == nil {
+		return ""
+	}
+	return absoluteWebURL(parsed.FeedLink, feedURL)
+}
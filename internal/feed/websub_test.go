@@ -0,0 +1,66 @@
+package feed
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHubAndSelfURLs(t *testing.T) {
+	const feedURL = "https://example.com/feed.xml"
+	tests := []struct {
+		name     string
+		format   string
+		body     string
+		wantHubs []string
+		wantSelf string
+	}{
+		{
+			name: "rss with atom links",
+			body: `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel><title>T</title>
+<atom:link rel="hub" href="https://pubsubhubbub.appspot.com/"/>
+<atom:link rel="hub" href="/hub"/>
+<atom:link rel="hub" href="https://pubsubhubbub.appspot.com/"/>
+<atom:link rel="self" href="https://example.com/moved.xml"/>
+</channel></rss>`,
+			wantHubs: []string{"https://pubsubhubbub.appspot.com/", "https://example.com/hub"},
+			wantSelf: "https://example.com/moved.xml",
+		},
+		{
+			name: "atom",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom"><title>T</title>
+<link rel="hub" href="https://hub.example/"/>
+<link rel="self" href="/feed.xml"/>
+<link href="https://example.com/"/>
+</feed>`,
+			wantHubs: []string{"https://hub.example/"},
+			wantSelf: "https://example.com/feed.xml",
+		},
+		{
+			name:   "forced atom",
+			format: FormatAtom,
+			body: `<feed xmlns="http://www.w3.org/2005/Atom"><title>T</title>
+<link rel="hub" href="https://hub.example/"/></feed>`,
+			wantHubs: []string{"https://hub.example/"},
+		},
+		{
+			name: "none advertised",
+			body: `<rss version="2.0"><channel><title>T</title><link>https://example.com/</link></channel></rss>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, _, err := NewParser().parse(strings.NewReader(tt.body), "f", feedURL, tt.format)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if got := hubURLs(parsed, feedURL); !slices.Equal(got, tt.wantHubs) {
+				t.Errorf("hubURLs = %q, want %q", got, tt.wantHubs)
+			}
+			if got := selfURL(parsed, feedURL); got != tt.wantSelf {
+				t.Errorf("selfURL = %q, want %q", got, tt.wantSelf)
+			}
+		})
+	}
+}
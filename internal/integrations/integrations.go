@@ -0,0 +1,38 @@
+// Package integrations sends articles to services outside fwrd. For now
+// that is read-it-later services: the reader hands an article URL to
+// whichever one is configured under [integrations] in the config file.
+package integrations
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pders01/fwrd/internal/config"
+)
+
+// ReadLater saves a URL to a read-it-later service.
+type ReadLater interface {
+	SaveForLater(url string) error
+}
+
+// defaultTimeout bounds one save when the feed HTTP timeout is unset.
+const defaultTimeout = 30 * time.Second
+
+// NewReadLater returns the read-it-later service configured in cfg, or
+// nil when none is. Requests use cfg.Feed.HTTPTimeout but none of the
+// feed fetcher's host checks: a self-hosted service commonly lives on
+// the local network.
+func NewReadLater(cfg *config.Config) ReadLater {
+	if cfg == nil {
+		return nil
+	}
+	timeout := cfg.Feed.HTTPTimeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+	if wb := cfg.Integrations.Wallabag; wb.URL != "" {
+		return NewWallabag(wb, client)
+	}
+	return nil
+}
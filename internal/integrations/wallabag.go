@@ -0,0 +1,119 @@
+package integrations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pders01/fwrd/internal/config"
+)
+
+// Wallabag saves URLs to a Wallabag instance through its API. It signs
+// in with the OAuth password grant using the API client credentials and
+// the account's username and password, and keeps the access token until
+// it expires or the server rejects it.
+type Wallabag struct {
+	cfg    config.WallabagConfig
+	client *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewWallabag returns a client for the instance described by cfg.
+func NewWallabag(cfg config.WallabagConfig, client *http.Client) *Wallabag {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+	return &Wallabag{cfg: cfg, client: client}
+}
+
+// SaveForLater adds link as a new Wallabag entry. A token the server no
+// longer accepts is dropped and the save tried once more with a new one.
+func (w *Wallabag) SaveForLater(link string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		token, err := w.accessToken()
+		if err != nil {
+			return err
+		}
+		status, err := w.postEntry(token, link)
+		if err != nil {
+			return err
+		}
+		switch {
+		case status == http.StatusUnauthorized && attempt == 0:
+			w.token = ""
+			continue
+		case status < 200 || status > 299:
+			return fmt.Errorf("wallabag: saving entry: %s", http.StatusText(status))
+		}
+		return nil
+	}
+}
+
+func (w *Wallabag) postEntry(token, link string) (int, error) {
+	form := url.Values{"url": {link}}
+	req, err := http.NewRequest(http.MethodPost, w.cfg.URL+"/api/entries.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, fmt.Errorf("wallabag: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("wallabag: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// accessToken returns the cached token, signing in again once it is
+// missing or about to expire. Callers hold w.mu.
+func (w *Wallabag) accessToken() (string, error) {
+	if w.token != "" && time.Now().Before(w.expires) {
+		return w.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"password"},
+		"client_id":     {w.cfg.ClientID},
+		"client_secret": {w.cfg.ClientSecret},
+		"username":      {w.cfg.Username},
+		"password":      {w.cfg.Password},
+	}
+	resp, err := w.client.PostForm(w.cfg.URL+"/oauth/v2/token", form)
+	if err != nil {
+		return "", fmt.Errorf("wallabag: signing in: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("wallabag: signing in: %s", http.StatusText(resp.StatusCode))
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("wallabag: reading token: %w", err)
+	}
+	if body.AccessToken == "" {
+		return "", errors.New("wallabag: signing in: no access token in response")
+	}
+	w.token = body.AccessToken
+	// Renew a little early so a token never expires mid-request.
+	w.expires = time.Now().Add(time.Duration(body.ExpiresIn)*time.Second - time.Minute)
+	return w.token, nil
+}
@@ -0,0 +1,117 @@
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pders01/fwrd/internal/config"
+)
+
+// fakeWallabag serves the two endpoints the client uses. It hands out
+// token-1, token-2, … and accepts only the newest one.
+type fakeWallabag struct {
+	signIns int
+	saved   []string
+	revoke  bool
+}
+
+func (f *fakeWallabag) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch r.URL.Path {
+	case "/oauth/v2/token":
+		if r.Form.Get("grant_type") != "password" || r.Form.Get("client_id") != "id" ||
+			r.Form.Get("client_secret") != "secret" || r.Form.Get("username") != "me" || r.Form.Get("password") != "pw" {
+			http.Error(w, "bad credentials", http.StatusBadRequest)
+			return
+		}
+		f.signIns++
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600,"token_type":"bearer"}`, f.signIns)
+	case "/api/entries.json":
+		if f.revoke || r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", f.signIns) {
+			f.revoke = false
+			http.Error(w, "expired", http.StatusUnauthorized)
+			return
+		}
+		if r.Form.Get("url") == "" {
+			http.Error(w, "no url", http.StatusBadRequest)
+			return
+		}
+		f.saved = append(f.saved, r.Form.Get("url"))
+		fmt.Fprint(w, `{"id":1}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestWallabag(t *testing.T, f *fakeWallabag) *Wallabag {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return NewWallabag(config.WallabagConfig{
+		URL: srv.URL + "/", ClientID: "id", ClientSecret: "secret", Username: "me", Password: "pw",
+	}, srv.Client())
+}
+
+func TestWallabagSaveForLater(t *testing.T) {
+	f := &fakeWallabag{}
+	wb := newTestWallabag(t, f)
+
+	for _, link := range []string{"https://example.com/a", "https://example.com/b"} {
+		if err := wb.SaveForLater(link); err != nil {
+			t.Fatalf("SaveForLater(%q): %v", link, err)
+		}
+	}
+	if f.signIns != 1 {
+		t.Errorf("expected the token to be reused, signed in %d times", f.signIns)
+	}
+	if got := strings.Join(f.saved, " "); got != "https://example.com/a https://example.com/b" {
+		t.Errorf("saved = %q", got)
+	}
+}
+
+func TestWallabagSaveForLater_SignsInAgainWhenTokenIsRejected(t *testing.T) {
+	f := &fakeWallabag{}
+	wb := newTestWallabag(t, f)
+	if err := wb.SaveForLater("https://example.com/a"); err != nil {
+		t.Fatal(err)
+	}
+
+	f.revoke = true
+	if err := wb.SaveForLater("https://example.com/b"); err != nil {
+		t.Fatalf("expected a retry with a new token, got: %v", err)
+	}
+	if f.signIns != 2 || len(f.saved) != 2 {
+		t.Errorf("signIns = %d, saved = %v", f.signIns, f.saved)
+	}
+}
+
+func TestWallabagSaveForLater_ReportsFailures(t *testing.T) {
+	f := &fakeWallabag{}
+	wb := newTestWallabag(t, f)
+	wb.cfg.Password = "wrong"
+	if err := wb.SaveForLater("https://example.com/a"); err == nil || !strings.Contains(err.Error(), "signing in") {
+		t.Fatalf("expected a sign-in error, got: %v", err)
+	}
+
+	wb.cfg.Password = "pw"
+	if err := wb.SaveForLater(""); err == nil || !strings.Contains(err.Error(), "Bad Request") {
+		t.Fatalf("expected the server's refusal, got: %v", err)
+	}
+}
+
+func TestNewReadLater(t *testing.T) {
+	cfg := config.TestConfig()
+	if rl := NewReadLater(cfg); rl != nil {
+		t.Fatalf("expected no service without config, got %T", rl)
+	}
+	cfg.Integrations.Wallabag.URL = "https://wallabag.local"
+	if _, ok := NewReadLater(cfg).(*Wallabag); !ok {
+		t.Fatalf("expected a Wallabag client")
+	}
+}
@@ -2,10 +2,16 @@ package media
 
 import (
 	"fmt"
+	"net/url"
+	"os"
 	"os/exec"
 	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/validation"
 )
 
 type Type int
@@ -24,9 +30,15 @@ type Launcher struct {
 	audioPlayer   string
 	pdfViewer     string
 	defaultOpener string
+	schemes       map[string][]string
 	config        *config.MediaConfig
 	registry      *PlayerRegistry
 	detector      *TypeDetector
+	urls          *validation.MediaURLValidator
+
+	// DryRun makes Open print the resolved command line to stderr
+	// instead of executing it. Useful for diagnosing player arguments.
+	DryRun bool
 }
 
 func NewLauncher(cfg *config.Config) *Launcher {
@@ -51,10 +63,22 @@ func NewLauncher(cfg *config.Config) *Launcher {
 	l := &Launcher{
 		config:        &cfg.Media,
 		defaultOpener: defaultOpener,
+		schemes:       parseSchemeHandlers(cfg.Media.SchemeHandlers),
 		registry:      registry,
 		detector:      detector,
 	}
 
+	// A scheme with a handler configured for it is allowed by that alone.
+	allowed := cfg.Media.AllowedSchemes
+	if len(allowed) == 0 {
+		allowed = config.DefaultAllowedMediaSchemes
+	}
+	allowed = slices.Clone(allowed)
+	for scheme := range l.schemes {
+		allowed = append(allowed, scheme)
+	}
+	l.urls = validation.NewMediaURLValidator(allowed)
+
 	var players config.MediaPlayers
 	switch runtime.GOOS {
 	case "darwin":
@@ -96,29 +120,82 @@ func NewLauncher(cfg *config.Config) *Launcher {
 	return l
 }
 
+// Open launches the application resolved by Command for url, detached.
+// With DryRun set it only prints the command line.
 func (l *Launcher) Open(url string) error {
+	cmd, err := l.Command(url)
+	if err != nil {
+		return err
+	}
+	if l.DryRun {
+		fmt.Fprintf(os.Stderr, "fwrd: would run: %s\n", FormatArgv(cmd.Args))
+		return nil
+	}
+	return startDetached(cmd, cmd.Args[0])
+}
+
+// Command resolves the command Open would run for url without starting
+// it: a media.scheme_handlers entry first, then the player for the
+// detected media type, then the default opener. A url whose scheme is
+// not allowed is refused. With media.proxy set, the command's environment
+// points the proxy variables at it.
+func (l *Launcher) Command(url string) (*exec.Cmd, error) {
+	url, err := l.urls.ValidateAndNormalize(url)
+	if err != nil {
+		return nil, fmt.Errorf("refusing to open link: %w", err)
+	}
+	cmd, err := l.command(url)
+	if err != nil {
+		return nil, err
+	}
+	l.useProxy(cmd)
+	return cmd, nil
+}
+
+// proxyEnvVars are the variables proxy-aware players read. Both cases are
+// set since programs disagree on which they honor.
+var proxyEnvVars = []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"}
+
+// useProxy sets cmd's proxy variables to media.proxy on top of fwrd's own
+// environment; exec keeps the last of duplicate keys.
+func (l *Launcher) useProxy(cmd *exec.Cmd) {
+	if l.config == nil || l.config.Proxy == "" {
+		return
+	}
+	env := os.Environ()
+	for _, k := range proxyEnvVars {
+		env = append(env, k+"="+l.config.Proxy)
+	}
+	cmd.Env = env
+}
+
+func (l *Launcher) command(url string) (*exec.Cmd, error) {
+	if argv := l.schemeCommand(url); argv != nil {
+		return exec.Command(argv[0], append(argv[1:], url)...), nil
+	}
+
 	mediaType := l.detector.DetectType(url)
 
 	var playerName string
 	switch mediaType {
 	case TypeVideo:
 		if l.videoPlayer == "" {
-			return fmt.Errorf("no video player found")
+			return nil, fmt.Errorf("no video player found")
 		}
 		playerName = l.videoPlayer
 	case TypeImage:
 		if l.imageViewer == "" {
-			return fmt.Errorf("no image viewer found")
+			return nil, fmt.Errorf("no image viewer found")
 		}
 		playerName = l.imageViewer
 	case TypeAudio:
 		if l.audioPlayer == "" {
-			return fmt.Errorf("no audio player found")
+			return nil, fmt.Errorf("no audio player found")
 		}
 		playerName = l.audioPlayer
 	case TypePDF:
 		if l.pdfViewer == "" {
-			return fmt.Errorf("no PDF viewer found")
+			return nil, fmt.Errorf("no PDF viewer found")
 		}
 		playerName = l.pdfViewer
 	default:
@@ -131,17 +208,33 @@ func (l *Launcher) Open(url string) error {
 
 	// Ensure we have a valid command
 	if playerName == "" {
-		return fmt.Errorf("no application found to open URL")
+		return nil, fmt.Errorf("no application found to open URL")
 	}
 
 	cmd, err := l.registry.GetCommand(playerName, mediaType, url)
 	if err != nil {
 		cmd = exec.Command(playerName, url)
 	}
+	return cmd, nil
+}
+
+// FormatArgv renders argv as a shell-like command line, quoting any
+// argument that is empty or contains whitespace or quotes.
+func FormatArgv(argv []string) string {
+	parts := make([]string, len(argv))
+	for i, a := range argv {
+		if a == "" || strings.ContainsAny(a, " \t\n'\"") {
+			a = strconv.Quote(a)
+		}
+		parts[i] = a
+	}
+	return strings.Join(parts, " ")
+}
 
-	// Start GUI applications detached
+// startDetached starts a GUI application without waiting for it to exit.
+func startDetached(cmd *exec.Cmd, name string) error {
 	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start %s: %w", playerName, err)
+		return fmt.Errorf("failed to start %s: %w", name, err)
 	}
 
 	go func() {
@@ -151,6 +244,33 @@ func (l *Launcher) Open(url string) error {
 	return nil
 }
 
+// parseSchemeHandlers lower-cases scheme keys and splits each command
+// into argv, dropping entries with an empty command.
+func parseSchemeHandlers(handlers map[string]string) map[string][]string {
+	out := make(map[string][]string, len(handlers))
+	for scheme, command := range handlers {
+		argv := strings.Fields(command)
+		if len(argv) == 0 {
+			continue
+		}
+		out[strings.ToLower(strings.TrimSuffix(scheme, ":"))] = argv
+	}
+	return out
+}
+
+// schemeCommand returns the configured argv for rawURL's scheme, or nil
+// when no media.scheme_handlers entry applies.
+func (l *Launcher) schemeCommand(rawURL string) []string {
+	if len(l.schemes) == 0 {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" {
+		return nil
+	}
+	return l.schemes[strings.ToLower(u.Scheme)]
+}
+
 func findCommand(commands ...string) string {
 	for _, cmd := range commands {
 		if _, err := exec.LookPath(cmd); err == nil {
@@ -1,7 +1,10 @@
 package media
 
 import (
+	"os"
 	"runtime"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/pders01/fwrd/internal/config"
@@ -169,3 +172,125 @@ func TestGetDefaultOpener(t *testing.T) {
 		}
 	}
 }
+
+func TestLauncherSchemeHandlers(t *testing.T) {
+	cfg := &config.Config{
+		Media: config.MediaConfig{
+			DefaultOpener: "open",
+			SchemeHandlers: map[string]string{
+				"magnet":  "transmission-remote -a",
+				"MAILTO:": "thunderbird",
+				"gemini":  "   ",
+			},
+		},
+	}
+	launcher := NewLauncher(cfg)
+
+	tests := []struct {
+		url  string
+		want []string
+	}{
+		{url: "magnet:?xt=urn:btih:abc", want: []string{"transmission-remote", "-a"}},
+		{url: "mailto:someone@example.com", want: []string{"thunderbird"}},
+		{url: "MAGNET:?xt=urn:btih:abc", want: []string{"transmission-remote", "-a"}},
+		{url: "gemini://example.com/", want: nil},
+		{url: "https://example.com/video.mp4", want: nil},
+		{url: "no-scheme", want: nil},
+	}
+	for _, tt := range tests {
+		got := launcher.schemeCommand(tt.url)
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") || (got == nil) != (tt.want == nil) {
+			t.Errorf("schemeCommand(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestLauncherCommandAndDryRun(t *testing.T) {
+	cfg := &config.Config{
+		Media: config.MediaConfig{
+			DefaultOpener:  "fwrd-test-opener",
+			SchemeHandlers: map[string]string{"magnet": "torrent-client --add"},
+		},
+	}
+	launcher := NewLauncher(cfg)
+
+	cmd, err := launcher.Command("magnet:?xt=urn:btih:abc")
+	if err != nil {
+		t.Fatalf("Command() error = %v", err)
+	}
+	want := []string{"torrent-client", "--add", "magnet:?xt=urn:btih:abc"}
+	if strings.Join(cmd.Args, "|") != strings.Join(want, "|") {
+		t.Errorf("Command().Args = %v, want %v", cmd.Args, want)
+	}
+
+	// The opener doesn't exist, so a real launch would fail; DryRun must
+	// not try to start it.
+	launcher.DryRun = true
+	if err := launcher.Open("http://example.com/page.html"); err != nil {
+		t.Errorf("Open() with DryRun = %v, want nil", err)
+	}
+}
+
+func TestLauncherCommandRejectsDisallowedSchemes(t *testing.T) {
+	cfg := &config.Config{
+		Media: config.MediaConfig{
+			DefaultOpener:  "fwrd-test-opener",
+			SchemeHandlers: map[string]string{"gemini": "lagrange"},
+		},
+	}
+	launcher := NewLauncher(cfg)
+	for _, u := range []string{"file:///etc/passwd", "javascript:alert(1)", "smb://host/share", "-flag"} {
+		if cmd, err := launcher.Command(u); err == nil {
+			t.Errorf("Command(%q) = %v, want it refused", u, cmd.Args)
+		}
+	}
+	if _, err := launcher.Command("gemini://example.org/"); err != nil {
+		t.Errorf("a scheme with a handler should be allowed: %v", err)
+	}
+
+	cfg.Media.AllowedSchemes = []string{"https", "file"}
+	launcher = NewLauncher(cfg)
+	if _, err := launcher.Command("file:///tmp/paper.pdf"); err != nil {
+		t.Errorf("file: should open once listed in allowed_schemes: %v", err)
+	}
+	if _, err := launcher.Command("http://example.org/"); err == nil {
+		t.Error("http: should be refused once allowed_schemes leaves it out")
+	}
+}
+
+func TestLauncherCommandSetsProxyEnv(t *testing.T) {
+	cfg := &config.Config{Media: config.MediaConfig{DefaultOpener: "fwrd-test-opener"}}
+	cmd, err := NewLauncher(cfg).Command("https://example.com/talk.mp4")
+	if err != nil {
+		t.Fatalf("Command() error = %v", err)
+	}
+	if cmd.Env != nil {
+		t.Errorf("without media.proxy the player should inherit the environment, got Env %v", cmd.Env)
+	}
+
+	cfg.Media.Proxy = "http://proxy.example:3128"
+	cfg.Media.SchemeHandlers = map[string]string{"magnet": "torrent-client"}
+	launcher := NewLauncher(cfg)
+	for _, u := range []string{"https://example.com/talk.mp4", "magnet:?xt=urn:btih:abc"} {
+		cmd, err := launcher.Command(u)
+		if err != nil {
+			t.Fatalf("Command(%q) error = %v", u, err)
+		}
+		for _, k := range proxyEnvVars {
+			if !slices.Contains(cmd.Env, k+"=http://proxy.example:3128") {
+				t.Errorf("Command(%q).Env lacks %s", u, k)
+			}
+		}
+		if len(cmd.Env) <= len(proxyEnvVars) && len(os.Environ()) > 0 {
+			t.Errorf("Command(%q).Env dropped the rest of the environment", u)
+		}
+	}
+}
+
+func TestFormatArgv(t *testing.T) {
+	got := FormatArgv([]string{"mpv", "--title=My Video", "", "http://x/y"})
+	want := `mpv "--title=My Video" "" http://x/y`
+	if got != want {
+		t.Errorf("FormatArgv() = %s, want %s", got, want)
+	}
+}
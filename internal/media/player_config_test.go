@@ -4,6 +4,9 @@ import (
 	"os/exec"
 	"runtime"
 	"testing"
+
+	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/validation"
 )
 
 func TestPlayerRegistry_GetCommand(t *testing.T) {
@@ -279,6 +282,7 @@ func TestLauncher_Open(t *testing.T) {
 		defaultOpener: "echo",
 		registry:      &PlayerRegistry{players: make(map[string]PlayerDefinition)},
 		detector:      &TypeDetector{config: &TypesConfig{}},
+		urls:          validation.NewMediaURLValidator(config.DefaultAllowedMediaSchemes),
 	}
 
 	// Initialize detector properly
@@ -337,6 +341,7 @@ func TestLauncher_OpenWithMissingPlayers(t *testing.T) {
 		defaultOpener: "",
 		registry:      &PlayerRegistry{players: make(map[string]PlayerDefinition)},
 		detector:      &TypeDetector{config: &TypesConfig{}},
+		urls:          validation.NewMediaURLValidator(config.DefaultAllowedMediaSchemes),
 	}
 
 	detector, _ := NewTypeDetector()
@@ -8,6 +8,8 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -51,17 +53,23 @@ type outline struct {
 }
 
 // Feed is a single subscription recovered from an OPML document: the feed
-// URL and a human-readable title. It is intentionally smaller than
-// storage.Feed — import only knows the URL and title; the rest is filled
-// in when the feed is actually fetched.
+// URL, a human-readable title, and the categories (folders) it was filed
+// under. It is intentionally smaller than storage.Feed — import only knows
+// these; the rest is filled in when the feed is actually fetched.
 type Feed struct {
-	URL   string
-	Title string
+	URL        string
+	Title      string
+	Categories []string
 }
 
 // Export renders feeds as an OPML 2.0 document. created stamps the head's
 // dateCreated (RFC 1123); pass the zero time to omit it. Feeds without a
 // URL are skipped — an outline with no xmlUrl is not a subscription.
+//
+// A feed's tags become categories: the feed is listed under a container
+// outline for each of its tags, the way other readers export folders, so
+// a tagged feed appears once per tag. Containers are sorted by name and
+// come first; untagged feeds follow at the top level.
 func Export(feeds []*storage.Feed, created time.Time) ([]byte, error) {
 	doc := document{
 		Version: "2.0",
@@ -70,21 +78,32 @@ func Export(feeds []*storage.Feed, created time.Time) ([]byte, error) {
 	if !created.IsZero() {
 		doc.Head.DateCreated = created.Format(time.RFC1123Z)
 	}
+	// Tags match case-insensitively, so group under the first spelling.
+	groups := make(map[string]*outline)
+	var flat []outline
 	for _, f := range feeds {
 		if f == nil || strings.TrimSpace(f.URL) == "" {
 			continue
 		}
-		title := f.Title
-		if title == "" {
-			title = f.URL
+		o := feedOutline(f)
+		if len(f.Tags) == 0 {
+			flat = append(flat, o)
+			continue
+		}
+		for _, tag := range f.Tags {
+			key := strings.ToLower(tag)
+			g, ok := groups[key]
+			if !ok {
+				g = &outline{Text: tag, Title: tag}
+				groups[key] = g
+			}
+			g.Children = append(g.Children, o)
 		}
-		doc.Body.Outlines = append(doc.Body.Outlines, outline{
-			Text:   title,
-			Title:  title,
-			Type:   "rss",
-			XMLURL: f.URL,
-		})
 	}
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		doc.Body.Outlines = append(doc.Body.Outlines, *groups[key])
+	}
+	doc.Body.Outlines = append(doc.Body.Outlines, flat...)
 
 	out, err := xml.MarshalIndent(doc, "", "  ")
 	if err != nil {
@@ -95,10 +114,26 @@ func Export(feeds []*storage.Feed, created time.Time) ([]byte, error) {
 	return append([]byte(xml.Header), append(out, '\n')...), nil
 }
 
+func feedOutline(f *storage.Feed) outline {
+	title := f.Title
+	if title == "" {
+		title = f.URL
+	}
+	return outline{
+		Text:    title,
+		Title:   title,
+		Type:    "rss",
+		XMLURL:  f.URL,
+		HTMLURL: f.SiteURL,
+	}
+}
+
 // Parse reads an OPML document and returns the feeds it lists. The outline
 // tree is walked depth-first so feeds nested under category outlines are
-// recovered too. Duplicate xmlUrls are collapsed, keeping the first title
-// seen. A document with no feed outlines parses cleanly to an empty slice.
+// recovered too, with the nearest enclosing category recorded in
+// Categories. Duplicate xmlUrls are collapsed, keeping the first title
+// seen and every category the feed was listed under. A document with no
+// feed outlines parses cleanly to an empty slice.
 func Parse(r io.Reader) ([]Feed, error) {
 	var doc document
 	// Bound total input so a pathological document can't exhaust memory,
@@ -112,24 +147,37 @@ func Parse(r io.Reader) ([]Feed, error) {
 	}
 
 	var feeds []Feed
-	seen := make(map[string]bool)
-	var walk func(outlines []outline)
-	walk = func(outlines []outline) {
+	seen := make(map[string]int)
+	var walk func(outlines []outline, category string)
+	walk = func(outlines []outline, category string) {
 		for _, o := range outlines {
+			title := o.Title
+			if title == "" {
+				title = o.Text
+			}
+			title = strings.TrimSpace(title)
 			url := strings.TrimSpace(o.XMLURL)
-			if url != "" && !seen[url] {
-				seen[url] = true
-				title := o.Title
-				if title == "" {
-					title = o.Text
+			if url == "" {
+				// A container: its children are filed under it.
+				if len(o.Children) > 0 {
+					walk(o.Children, title)
 				}
-				feeds = append(feeds, Feed{URL: url, Title: strings.TrimSpace(title)})
+				continue
+			}
+			i, ok := seen[url]
+			if !ok {
+				i = len(feeds)
+				seen[url] = i
+				feeds = append(feeds, Feed{URL: url, Title: title})
+			}
+			if category != "" && !slices.ContainsFunc(feeds[i].Categories, func(c string) bool { return strings.EqualFold(c, category) }) {
+				feeds[i].Categories = append(feeds[i].Categories, category)
 			}
 			if len(o.Children) > 0 {
-				walk(o.Children)
+				walk(o.Children, category)
 			}
 		}
 	}
-	walk(doc.Body.Outlines)
+	walk(doc.Body.Outlines, "")
 	return feeds, nil
 }
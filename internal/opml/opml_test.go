@@ -1,6 +1,7 @@
 package opml
 
 import (
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -33,6 +34,64 @@ func TestExportParseRoundTrip(t *testing.T) {
 	}
 }
 
+func TestExportGroupsByTagAndRoundTrips(t *testing.T) {
+	feeds := []*storage.Feed{
+		{URL: "http://a.example/feed", Title: "Alpha", Tags: []string{"Tech", "news"}},
+		{URL: "http://b.example/feed", Title: "Beta"},
+		{URL: "http://c.example/feed", Title: "Gamma", Tags: []string{"tech"}},
+	}
+	data, err := Export(feeds, time.Time{})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	out := string(data)
+	news := strings.Index(out, `<outline text="news" title="news">`)
+	tech := strings.Index(out, `<outline text="Tech" title="Tech">`)
+	beta := strings.Index(out, `xmlUrl="http://b.example/feed"`)
+	if news < 0 || tech < 0 || !(news < tech && tech < beta) {
+		t.Fatalf("want sorted category outlines, then untagged feeds, got:\n%s", out)
+	}
+	if strings.Count(out, `xmlUrl="http://a.example/feed"`) != 2 {
+		t.Errorf("a feed with two tags should be listed under both:\n%s", out)
+	}
+
+	got, err := Parse(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := map[string][]string{
+		"http://a.example/feed": {"news", "Tech"},
+		"http://b.example/feed": nil,
+		"http://c.example/feed": {"Tech"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("round-trip recovered %d feeds, want %d: %+v", len(got), len(want), got)
+	}
+	for _, f := range got {
+		if !slices.Equal(f.Categories, want[f.URL]) {
+			t.Errorf("%s categories = %v, want %v", f.URL, f.Categories, want[f.URL])
+		}
+	}
+}
+
+func TestExportIncludesSiteURL(t *testing.T) {
+	feeds := []*storage.Feed{
+		{URL: "http://a.example/feed", Title: "Alpha", SiteURL: "http://a.example/"},
+		{URL: "http://b.example/feed", Title: "Beta"},
+	}
+	data, err := Export(feeds, time.Time{})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	out := string(data)
+	if !strings.Contains(out, `htmlUrl="http://a.example/"`) {
+		t.Errorf("export should carry the site URL as htmlUrl:\n%s", out)
+	}
+	if strings.Count(out, "htmlUrl") != 1 {
+		t.Errorf("feeds without a site URL should omit htmlUrl:\n%s", out)
+	}
+}
+
 func TestExportSkipsURLless(t *testing.T) {
 	feeds := []*storage.Feed{
 		{Title: "no url"},
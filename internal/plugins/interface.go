@@ -34,7 +34,8 @@ type Plugin interface {
 	EnhanceFeed(ctx context.Context, url string, client *http.Client) (*FeedInfo, error)
 
 	// Priority returns the priority of this plugin (higher = higher priority)
-	// Useful when multiple plugins can handle the same URL
+	// Loaders register higher-priority plugins first, so they are tried
+	// first when multiple plugins can handle the same URL
 	Priority() int
 }
 
@@ -42,6 +43,10 @@ type Plugin interface {
 // safe to call from multiple goroutines; the mutex covers both
 // registration mutations (Register, Replace, Unregister) and the
 // read-side iteration FindPlugin and ListPlugins perform.
+//
+// Plugin selection is deterministic: the first plugin in registration
+// order whose CanHandle accepts a URL wins. A generic catch-all
+// registered after a specific plugin therefore never shadows it.
 type Registry struct {
 	mu      sync.RWMutex
 	plugins []Plugin
@@ -58,7 +63,8 @@ func NewRegistry(timeout time.Duration) *Registry {
 	}
 }
 
-// Register adds a plugin to the registry
+// Register appends a plugin to the registry. FindPlugin tries plugins in
+// registration order, so register specific plugins before generic ones.
 func (r *Registry) Register(plugin Plugin) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -67,8 +73,9 @@ func (r *Registry) Register(plugin Plugin) {
 
 // Replace swaps the plugin with the same name as p and returns the
 // previous instance, or appends p if no plugin with that name was
-// registered. Callers should release any resources owned by the
-// returned plugin.
+// registered. A replaced plugin keeps its original registration slot,
+// so hot-reloading a file does not change the order plugins are tried. Callers
+// should release any resources owned by the returned plugin.
 func (r *Registry) Replace(p Plugin) Plugin {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -97,25 +104,25 @@ func (r *Registry) Unregister(name string) Plugin {
 	return nil
 }
 
-// FindPlugin returns the best plugin for handling a given URL
-// Returns the plugin with highest priority that can handle the URL
+// FindPlugin returns the first plugin, in registration order, that can
+// handle url, so a generic plugin registered later never overrides an
+// earlier specific one whatever their priorities. Priority only orders
+// registration, for loaders such as the Lua one. Returns nil when no
+// plugin matches.
 func (r *Registry) FindPlugin(url string) Plugin {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	var bestPlugin Plugin
-	highestPriority := -1
-
 	for _, plugin := range r.plugins {
-		if plugin.CanHandle(url) && plugin.Priority() > highestPriority {
-			bestPlugin = plugin
-			highestPriority = plugin.Priority()
+		if plugin.CanHandle(url) {
+			return plugin
 		}
 	}
-
-	return bestPlugin
+	return nil
 }
 
-// EnhanceFeed attempts to enhance feed information using available plugins
+// EnhanceFeed enhances url with the single plugin FindPlugin selects;
+// other matching plugins are not consulted, even if the chosen one
+// fails. Without a match it returns the URL unchanged.
 func (r *Registry) EnhanceFeed(ctx context.Context, url string) (*FeedInfo, error) {
 	plugin := r.FindPlugin(url)
 	if plugin == nil {
@@ -67,9 +67,8 @@ func TestRegistry_Register(t *testing.T) {
 func TestRegistry_FindPlugin(t *testing.T) {
 	registry := NewRegistry(5 * time.Second)
 
-	// Register plugins with different priorities
 	plugin1 := &mockPlugin{
-		name:     "low-priority",
+		name:     "first",
 		priority: 10,
 		canHandle: func(url string) bool {
 			return url == "http://example.com"
@@ -77,7 +76,7 @@ func TestRegistry_FindPlugin(t *testing.T) {
 	}
 
 	plugin2 := &mockPlugin{
-		name:     "high-priority",
+		name:     "second",
 		priority: 100,
 		canHandle: func(url string) bool {
 			return url == "http://example.com"
@@ -96,9 +95,9 @@ func TestRegistry_FindPlugin(t *testing.T) {
 	registry.Register(plugin2)
 	registry.Register(plugin3)
 
-	t.Run("finds highest priority plugin", func(t *testing.T) {
+	t.Run("finds first registered plugin", func(t *testing.T) {
 		result := registry.FindPlugin("http://example.com")
-		assert.Equal(t, plugin2, result)
+		assert.Equal(t, plugin1, result)
 	})
 
 	t.Run("finds specific plugin", func(t *testing.T) {
@@ -112,6 +111,48 @@ func TestRegistry_FindPlugin(t *testing.T) {
 	})
 }
 
+func TestRegistry_FindPlugin_FirstRegisteredMatchWins(t *testing.T) {
+	registry := NewRegistry(5 * time.Second)
+
+	specific := &mockPlugin{
+		name:     "specific",
+		priority: 10,
+		canHandle: func(url string) bool {
+			return url == "http://example.com/feed"
+		},
+	}
+	generic := &mockPlugin{
+		name:      "generic",
+		priority:  100,
+		canHandle: func(string) bool { return true },
+	}
+
+	registry.Register(specific)
+	registry.Register(generic)
+
+	assert.Equal(t, specific, registry.FindPlugin("http://example.com/feed"),
+		"later generic plugin must not shadow an earlier specific one, even with a higher priority")
+	assert.Equal(t, generic, registry.FindPlugin("http://other.com"))
+
+	// Replacing keeps the original slot, so the order is stable across
+	// hot reloads.
+	reloaded := &mockPlugin{name: "specific", priority: 10, canHandle: specific.canHandle}
+	registry.Replace(reloaded)
+	assert.Equal(t, reloaded, registry.FindPlugin("http://example.com/feed"))
+}
+
+func TestRegistry_FindPlugin_NegativePriority(t *testing.T) {
+	registry := NewRegistry(5 * time.Second)
+	plugin := &mockPlugin{
+		name:      "fallback",
+		priority:  -10,
+		canHandle: func(string) bool { return true },
+	}
+	registry.Register(plugin)
+
+	assert.Equal(t, plugin, registry.FindPlugin("http://example.com"))
+}
+
 func TestRegistry_EnhanceFeed(t *testing.T) {
 	registry := NewRegistry(5 * time.Second)
 
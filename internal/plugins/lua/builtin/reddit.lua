@@ -1,29 +1,48 @@
 -- reddit.lua: turn /r/<subreddit> URLs into Reddit's RSS endpoint.
 --
--- Reddit exposes RSS for any subreddit by appending ".rss" to the
--- listing URL. The plugin extracts the subreddit name for a friendlier
--- title; everything else falls back to the raw URL on a miss.
+-- Reddit exposes RSS for any listing by appending ".rss" to its path:
+-- subreddits, sorted views (/r/<name>/top), and comment threads
+-- (/r/<name>/comments/<id>/...). Old (old.reddit.com), new (www/new)
+-- and mobile hosts all serve the same endpoints, so the host is kept
+-- as pasted. Query strings such as ?t=week survive the rewrite, and a
+-- URL already pointing at .rss or .json is left alone.
+
+-- is_reddit claims reddit.com and its subdomains only: the host must be
+-- reddit.com itself or end in ".reddit.com", so look-alikes such as
+-- evilreddit.com are left to other handlers.
+local function is_reddit(url)
+  local host = string.match(url, "^https?://([%w%.%-]+)/r/")
+  if host == nil then
+    return false
+  end
+  host = string.lower(host)
+  return host == "reddit.com" or string.sub(host, -#".reddit.com") == ".reddit.com"
+end
 
 return {
   name = "reddit",
   priority = 50,
 
   can_handle = function(url)
-    return string.find(url, "://www.reddit.com/r/", 1, true) ~= nil
-        or string.find(url, "://reddit.com/r/", 1, true) ~= nil
+    return is_reddit(url)
   end,
 
   enhance = function(url)
-    local trimmed = url
-    if string.sub(trimmed, -1) == "/" then
-      trimmed = string.sub(trimmed, 1, -2)
+    local path, query = string.match(url, "^([^?#]*)(.*)$")
+    while string.sub(path, -1) == "/" do
+      path = string.sub(path, 1, -2)
     end
 
-    local subreddit = regex.match("/r/([^/]+)", url) or "unknown"
+    local subreddit = regex.match("/r/([A-Za-z0-9_]+)", url) or "unknown"
+
+    local feed_url = path .. ".rss" .. query
+    if string.find(path, "%.rss$") or string.find(path, "%.json$") then
+      feed_url = url
+    end
 
     return {
-      feed_url    = trimmed .. ".rss",
-      title       = "Reddit - r/" .. subreddit,
+      feed_url    = feed_url,
+      title       = "r/" .. subreddit,
       description = "Posts from r/" .. subreddit .. " subreddit",
       metadata    = { subreddit = subreddit },
     }
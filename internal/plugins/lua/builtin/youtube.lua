@@ -1,19 +1,25 @@
 -- youtube.lua: resolve YouTube channel URLs to their RSS feed.
 --
 -- Direct cases (no network):
---   /channel/UC...  -> RSS via channel_id
+--   /channel/UC...        -> RSS via channel_id
+--   playlist?list=PL...   -> RSS via playlist_id
+--   /feeds/videos.xml?... -> passed through unchanged
 --
 -- Resolved cases (one HTTP GET):
 --   /@handle, /c/<name>, /user/<name>, or any channel URL whose canonical
 --   page advertises the RSS feed via <link rel="alternate"
 --   type="application/rss+xml" href="...?channel_id=UC...">.
+--   /user/<name> falls back to the ?user= feed when the page can't be
+--   fetched; YouTube still serves those for legacy usernames.
 --
 -- We intentionally do not parse HTML beyond the channel_id link tag — a
 -- full goquery-style DOM walk is out of scope for the sandboxed runtime.
 -- Plugins that want richer extraction can still use http.get + regex.
 
+local feed_base = "https://www.youtube.com/feeds/videos.xml"
+
 local function rss_url(channel_id)
-  return "https://www.youtube.com/feeds/videos.xml?channel_id=" .. channel_id
+  return feed_base .. "?channel_id=" .. channel_id
 end
 
 local function fetch_channel_id(url)
@@ -36,6 +42,28 @@ return {
   end,
 
   enhance = function(url)
+    -- Already a feed URL: nothing to rewrite.
+    if string.find(url, "/feeds/videos.xml", 1, true) then
+      return {
+        feed_url    = url,
+        title       = "YouTube",
+        description = "YouTube RSS feed",
+        metadata    = {},
+      }
+    end
+
+    -- Playlist form. Checked before the channel forms because watch URLs
+    -- carry list= too, and the playlist is what the user pasted for.
+    local playlist = regex.match("[?&]list=([A-Za-z0-9_%-]+)", url)
+    if playlist then
+      return {
+        feed_url    = feed_base .. "?playlist_id=" .. playlist,
+        title       = "YouTube Playlist - " .. playlist,
+        description = "YouTube RSS feed",
+        metadata    = { playlist_id = playlist },
+      }
+    end
+
     -- Direct channel-id form.
     local cid = regex.match("/channel/([A-Za-z0-9_%-]+)", url)
     if cid then
@@ -79,6 +107,15 @@ return {
           metadata    = { channel_id = resolved, channel_handle = legacy },
         }
       end
+      local user = regex.match("/user/([A-Za-z0-9_%.%-]+)", url)
+      if user then
+        return {
+          feed_url    = feed_base .. "?user=" .. user,
+          title       = "YouTube - " .. user,
+          description = "YouTube RSS feed",
+          metadata    = { user = user },
+        }
+      end
     end
 
     -- Last resort: try resolving from the page itself.
@@ -86,6 +86,16 @@ func TestRedditBuiltinEnhances(t *testing.T) {
 	if plugin.CanHandle("https://example.com") {
 		t.Fatal("reddit plugin should not handle non-reddit URLs")
 	}
+	for _, url := range []string{"https://reddit.com/r/golang", "https://old.reddit.com/r/golang", "https://WWW.Reddit.com/r/golang"} {
+		if !plugin.CanHandle(url) {
+			t.Errorf("reddit plugin should handle %s", url)
+		}
+	}
+	for _, url := range []string{"https://evilreddit.com/r/golang", "https://reddit.com.evil.net/r/golang", "https://evil.net/x.reddit.com/r/golang"} {
+		if plugin.CanHandle(url) {
+			t.Errorf("reddit plugin should not claim %s", url)
+		}
+	}
 
 	info, err := plugin.EnhanceFeed(context.Background(), "https://www.reddit.com/r/golang/", nil)
 	if err != nil {
@@ -102,6 +112,54 @@ func TestRedditBuiltinEnhances(t *testing.T) {
 	}
 }
 
+func TestRedditBuiltinURLShapes(t *testing.T) {
+	tmp := filepath.Join(t.TempDir(), "plugins")
+	if err := EnsureDefaults(tmp); err != nil {
+		t.Fatal(err)
+	}
+	plugin, err := LoadFile(filepath.Join(tmp, "reddit.lua"), Bindings{})
+	if err != nil {
+		t.Fatalf("load reddit.lua: %v", err)
+	}
+	defer plugin.Close()
+
+	cases := []struct {
+		name, url, want string
+	}{
+		{"subreddit", "https://reddit.com/r/golang", "https://reddit.com/r/golang.rss"},
+		{"old reddit", "https://old.reddit.com/r/golang/", "https://old.reddit.com/r/golang.rss"},
+		{"sorted with query", "https://www.reddit.com/r/golang/top/?t=week", "https://www.reddit.com/r/golang/top.rss?t=week"},
+		{
+			"comment thread",
+			"https://www.reddit.com/r/golang/comments/abc123/some_title/",
+			"https://www.reddit.com/r/golang/comments/abc123/some_title.rss",
+		},
+		{"already rss", "https://www.reddit.com/r/golang/.rss", "https://www.reddit.com/r/golang/.rss"},
+		{"already json", "https://www.reddit.com/r/golang.json", "https://www.reddit.com/r/golang.json"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !plugin.CanHandle(tc.url) {
+				t.Fatalf("reddit plugin should handle %s", tc.url)
+			}
+			info, err := plugin.EnhanceFeed(context.Background(), tc.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.FeedURL != tc.want {
+				t.Errorf("feed url = %q, want %q", info.FeedURL, tc.want)
+			}
+			if info.Title != "r/golang" {
+				t.Errorf("title = %q, want r/golang", info.Title)
+			}
+		})
+	}
+
+	if plugin.CanHandle("https://notreddit.example/r/golang") {
+		t.Error("reddit plugin should not handle non-reddit hosts")
+	}
+}
+
 // youtubeStubHTML returns the minimal HTML the youtube plugin's
 // fetch_channel_id helper looks for: a single canonical link tag with
 // channel_id=<id>.
@@ -200,3 +258,55 @@ func TestYouTubeBuiltinDirectChannelID(t *testing.T) {
 		t.Errorf("metadata: %v", info.Metadata)
 	}
 }
+
+func TestYouTubeBuiltinOfflineRewrites(t *testing.T) {
+	tmp := filepath.Join(t.TempDir(), "plugins")
+	if err := EnsureDefaults(tmp); err != nil {
+		t.Fatal(err)
+	}
+	// No HTTP client: every case here must resolve without the network.
+	plugin, err := LoadFile(filepath.Join(tmp, "youtube.lua"), Bindings{})
+	if err != nil {
+		t.Fatalf("load youtube.lua: %v", err)
+	}
+	defer plugin.Close()
+
+	cases := []struct {
+		name, url, want string
+	}{
+		{
+			name: "playlist",
+			url:  "https://www.youtube.com/playlist?list=PLabc_123-xyz",
+			want: "https://www.youtube.com/feeds/videos.xml?playlist_id=PLabc_123-xyz",
+		},
+		{
+			name: "watch in playlist",
+			url:  "https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PLabc123",
+			want: "https://www.youtube.com/feeds/videos.xml?playlist_id=PLabc123",
+		},
+		{
+			name: "legacy user",
+			url:  "https://www.youtube.com/user/somecreator",
+			want: "https://www.youtube.com/feeds/videos.xml?user=somecreator",
+		},
+		{
+			name: "already a feed",
+			url:  "https://www.youtube.com/feeds/videos.xml?channel_id=UCabc",
+			want: "https://www.youtube.com/feeds/videos.xml?channel_id=UCabc",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !plugin.CanHandle(tc.url) {
+				t.Fatalf("youtube plugin should handle %s", tc.url)
+			}
+			info, err := plugin.EnhanceFeed(context.Background(), tc.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.FeedURL != tc.want {
+				t.Errorf("feed url = %q, want %q", info.FeedURL, tc.want)
+			}
+		})
+	}
+}
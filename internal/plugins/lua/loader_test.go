@@ -2,11 +2,16 @@ package lua
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/pders01/fwrd/internal/plugins"
 )
 
 const goodPluginScript = `return {
@@ -169,3 +174,34 @@ func TestLuaPluginConcurrentSafe(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestLoadAndRegisterOrdersByPriority(t *testing.T) {
+	dir := t.TempDir()
+	script := func(name string, priority int) string {
+		return fmt.Sprintf(`return {
+  name = %q,
+  priority = %d,
+  can_handle = function(url) return true end,
+  enhance = function(url) return { feed_url = url } end,
+}`, name, priority)
+	}
+	writePlugin(t, dir, "a_generic.lua", script("generic", 1))
+	writePlugin(t, dir, "b_specific.lua", script("specific", 90))
+	writePlugin(t, dir, "c_tied.lua", script("tied", 90))
+
+	reg := plugins.NewRegistry(time.Second)
+	if _, err := LoadAndRegister(reg, dir, Bindings{}); err != nil {
+		t.Fatalf("LoadAndRegister: %v", err)
+	}
+	var names []string
+	for _, p := range reg.ListPlugins() {
+		names = append(names, p.Name())
+		t.Cleanup(func() { closeIfPossible(p) })
+	}
+	if want := []string{"specific", "tied", "generic"}; !slices.Equal(names, want) {
+		t.Errorf("registered %v, want %v", names, want)
+	}
+	if got := reg.FindPlugin("https://example.com/feed"); got == nil || got.Name() != "specific" {
+		t.Errorf("FindPlugin = %v, want the highest-priority plugin", got)
+	}
+}
@@ -2,8 +2,10 @@ package lua
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/pders01/fwrd/internal/plugins"
 )
@@ -32,6 +34,9 @@ func DefaultPluginDir() string {
 // failures via b.Logger (if set). Returns the number of plugins
 // registered.
 //
+// The registry uses the first plugin that can handle a URL, so plugins
+// are registered highest priority first, ties in file name order.
+//
 // A missing directory is treated as zero plugins, not an error: this
 // keeps fwrd usable on a fresh machine where the plugin dir has not yet
 // been created.
@@ -47,6 +52,10 @@ func LoadAndRegister(reg *plugins.Registry, dir string, b Bindings) (int, error)
 	if err != nil {
 		return 0, err
 	}
+	// LoadDir sorts by path, which the stable sort keeps for ties.
+	sort.SliceStable(results, func(i, j int) bool {
+		return priority(results[i]) > priority(results[j])
+	})
 
 	count := 0
 	for _, r := range results {
@@ -65,3 +74,12 @@ func LoadAndRegister(reg *plugins.Registry, dir string, b Bindings) (int, error)
 	}
 	return count, nil
 }
+
+// priority is a load result's plugin priority; failed loads, which are
+// skipped, sort last.
+func priority(r LoadResult) int {
+	if r.Err != nil || r.Plugin == nil {
+		return math.MinInt
+	}
+	return r.Plugin.Priority()
+}
@@ -3,6 +3,8 @@ package search
 import (
 	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -69,6 +71,26 @@ func openOrCreateIndex(indexPath string) (idx bleve.Index, fresh bool, err error
 	}
 }
 
+// restrictIndexPermissions makes the index readable only by its owner:
+// 0700 for directories, 0600 for files. Bleve creates segments and its
+// bolt root that way already, but writes index_meta.json with 0666, and
+// indexes built by older releases may be more open still. The index holds
+// article text, which on a shared machine is nobody else's business.
+func restrictIndexPermissions(indexPath string) error {
+	return filepath.WalkDir(indexPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.Chmod(path, 0o700)
+		case d.Type().IsRegular():
+			return os.Chmod(path, 0o600)
+		}
+		return nil
+	})
+}
+
 type bleveEngine struct {
 	store   *storage.Store
 	idx     bleve.Index
@@ -115,6 +137,10 @@ func newBleveEngine(store *storage.Store, indexPath string, permissive bool) (Se
 	if err != nil {
 		return nil, err
 	}
+	if err := restrictIndexPermissions(indexPath); err != nil {
+		_ = idx.Close()
+		return nil, fmt.Errorf("securing index permissions: %w", err)
+	}
 
 	be := &bleveEngine{store: store, idx: idx}
 
@@ -210,6 +236,17 @@ const (
 	boostURLPrefix         = 0.3
 )
 
+// phraseFields lists where quoted phrases are matched. URLs are left out
+// because their words are rarely separated the way a phrase expects.
+var phraseFields = []struct {
+	field string
+	boost float64
+}{
+	{"title", boostTitleMatch},
+	{"description", boostDescriptionMatch},
+	{"content", boostContentMatch},
+}
+
 func (b *bleveEngine) reindexAll() error {
 	feeds, err := b.store.GetAllFeeds()
 	if err != nil {
@@ -251,7 +288,7 @@ func (b *bleveEngine) reindexAll() error {
 				return err
 			}
 			totalProcessed += batchCount
-			debuglog.Infof("Processed %d documents so far", totalProcessed)
+			debuglog.Debugf("Processed %d documents so far", totalProcessed)
 			batch = b.idx.NewBatch()
 			batchCount = 0
 		}
@@ -298,7 +335,7 @@ func (b *bleveEngine) indexArticlesInChunks(feedID string, batch **bleve.Batch,
 				if err := b.commitBatch(*batch); err != nil {
 					return err
 				}
-				debuglog.Infof("Committed batch during article processing: %d documents", *batchCount)
+				debuglog.Debugf("Committed batch during article processing: %d documents", *batchCount)
 				*batch = b.idx.NewBatch()
 				*batchCount = 0
 			}
@@ -325,7 +362,7 @@ func (b *bleveEngine) commitBatch(batch *bleve.Batch) error {
 		return fmt.Errorf("failed to commit batch: %w", err)
 	}
 
-	debuglog.Infof("Successfully committed batch with %d documents", batch.Size())
+	debuglog.Debugf("Successfully committed batch with %d documents", batch.Size())
 	return nil
 }
 
@@ -333,10 +370,21 @@ func (b *bleveEngine) Search(query string, limit int) ([]*Result, error) {
 	if len(strings.TrimSpace(query)) < 2 {
 		return []*Result{}, nil
 	}
-	// Tokenize input and build an OR of per-term matches across key fields with boosts
-	tokens := tokenize(query)
+	// Tokenize input and build an OR of per-term matches across key fields with boosts.
+	// Quoted phrases become phrase queries so their words must be adjacent.
+	tokens := parseQuery(query)
 	var qs []bleveQuery.Query
 	for _, tok := range tokens {
+		if isPhrase(tok) {
+			for _, pf := range phraseFields {
+				qp := bleve.NewMatchPhraseQuery(tok)
+				qp.SetField(pf.field)
+				qp.SetBoost(pf.boost)
+				qs = append(qs, qp)
+			}
+			continue
+		}
+
 		qt := bleve.NewMatchQuery(tok)
 		qt.SetField("title")
 		qt.SetBoost(boostTitleMatch)
@@ -385,6 +433,7 @@ func (b *bleveEngine) Search(query string, limit int) ([]*Result, error) {
 		return nil, err
 	}
 	out := make([]*Result, 0, len(res.Hits))
+	feeds := feedLookup{store: b.store}
 	for _, h := range res.Hits {
 		r := &Result{Score: h.Score}
 		if id, ok := strings.CutPrefix(h.ID, docPrefixFeed); ok {
@@ -413,9 +462,7 @@ func (b *bleveEngine) Search(query string, limit int) ([]*Result, error) {
 			}
 			if fid, ok := h.Fields["feed_id"].(string); ok {
 				a.FeedID = fid
-				if f, err := b.store.GetFeed(fid); err == nil {
-					r.Feed = f
-				}
+				r.Feed = feeds.get(fid)
 			}
 			r.Article = a
 			r.IsArticle = true
@@ -437,7 +484,7 @@ func (b *bleveEngine) SearchInArticle(article *storage.Article, query string) ([
 	}
 	// Local search within content/title/description without using the global index
 	// to keep implementation light.
-	terms := tokenize(query)
+	terms := parseQuery(query)
 	feed := &storage.Feed{ID: article.FeedID, Title: "Current Article"}
 	if res := (&Engine{store: b.store}).searchArticle(feed, article, terms); res != nil {
 		return []*Result{res}, nil
@@ -470,7 +517,7 @@ func (b *bleveEngine) OnDataUpdated(feed *storage.Feed, articles []*storage.Arti
 
 	// Process articles in chunks to prevent OOM for large article collections
 	if len(articles) > maxBatchSize {
-		debuglog.Infof("Processing %d articles in chunks to prevent OOM", len(articles))
+		debuglog.Debugf("Processing %d articles in chunks to prevent OOM", len(articles))
 	}
 
 	for _, a := range articles {
@@ -571,5 +618,27 @@ func (b *bleveEngine) CommitBatch() {
 // Close closes the underlying index
 func (b *bleveEngine) Close() error { return b.idx.Close() }
 
+// feedLookup memoizes store.GetFeed for one result assembly so a page of
+// hits from the same feed costs one read and shares one *storage.Feed.
+type feedLookup struct {
+	store *storage.Store
+	feeds map[string]*storage.Feed
+}
+
+func (l *feedLookup) get(id string) *storage.Feed {
+	if f, ok := l.feeds[id]; ok {
+		return f
+	}
+	if l.feeds == nil {
+		l.feeds = make(map[string]*storage.Feed)
+	}
+	f, err := l.store.GetFeed(id)
+	if err != nil {
+		f = nil
+	}
+	l.feeds[id] = f
+	return f
+}
+
 func docIDForFeed(feedID string) string   { return docPrefixFeed + feedID }
 func docIDForArticle(artID string) string { return docPrefixArticle + artID }
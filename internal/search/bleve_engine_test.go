@@ -3,8 +3,10 @@ package search
 import (
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -51,6 +53,85 @@ func TestBleveEngineIndexesAndSearches(t *testing.T) {
 	require.True(t, fi.IsDir())
 }
 
+func TestBleveEngineQuotedPhrase(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.NewStore(filepath.Join(dir, "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	feed := &storage.Feed{ID: "f1", Title: "Test Feed", URL: "https://example.com/feed"}
+	require.NoError(t, store.SaveFeed(feed))
+	require.NoError(t, store.SaveArticles([]*storage.Article{
+		{ID: "a1", FeedID: feed.ID, Title: "Machine learning in production", URL: "https://example.com/1"},
+		{ID: "a2", FeedID: feed.ID, Title: "Learning to love the machine", URL: "https://example.com/2"},
+	}))
+
+	eng, err := newBleveEngine(store, filepath.Join(dir, "index.bleve"), true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if c, ok := eng.(io.Closer); ok {
+			_ = c.Close()
+		}
+	})
+
+	res, err := eng.Search("machine learning", 10)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	res, err = eng.Search(`"machine learning"`, 10)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, "a1", res[0].Article.ID)
+}
+
+func TestBleveEngineRestrictsIndexPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix permission bits")
+	}
+	dir := t.TempDir()
+	store, err := storage.NewStore(filepath.Join(dir, "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	idxPath := filepath.Join(dir, "index.bleve")
+	eng, err := newBleveEngine(store, idxPath, true)
+	require.NoError(t, err)
+	require.NoError(t, eng.(io.Closer).Close())
+
+	// Loosen everything, as an index from an older build might be, and
+	// reopen: the engine tightens it again.
+	require.NoError(t, filepath.WalkDir(idxPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.Chmod(path, 0o755)
+		}
+		return os.Chmod(path, 0o644)
+	}))
+	eng, err = newBleveEngine(store, idxPath, true)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = eng.(io.Closer).Close() })
+
+	require.NoError(t, filepath.WalkDir(idxPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		want := fs.FileMode(0o600)
+		if d.IsDir() {
+			want = 0o700
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s: mode %o, want %o", path, got, want)
+		}
+		return nil
+	}))
+}
+
 // TestBleveEngineIndexesFeedLargerThanChunkSize seeds a feed with more
 // articles than maxArticlesPerFeed to verify cursor-based chunked indexing
 // terminates and indexes the full set. The previous offset-based loop
@@ -8,6 +8,7 @@ import (
 	"unicode"
 
 	"github.com/pders01/fwrd/internal/storage"
+	"golang.org/x/text/unicode/norm"
 )
 
 type Result struct {
@@ -26,10 +27,18 @@ type Match struct {
 
 type Engine struct {
 	store *storage.Store
+	// scanLimit caps how many articles one Search examines.
+	scanLimit int
 }
 
-func NewEngine(store *storage.Store) *Engine {
-	return &Engine{store: store}
+// NewEngine returns a brute-force engine over store that reads at most
+// scanLimit articles per query, or basicSearchScanLimit when scanLimit
+// is <= 0.
+func NewEngine(store *storage.Store, scanLimit int) *Engine {
+	if scanLimit <= 0 {
+		scanLimit = basicSearchScanLimit
+	}
+	return &Engine{store: store, scanLimit: scanLimit}
 }
 
 func (e *Engine) Search(query string, limit int) ([]*Result, error) {
@@ -37,7 +46,7 @@ func (e *Engine) Search(query string, limit int) ([]*Result, error) {
 		return []*Result{}, nil
 	}
 
-	terms := tokenize(query)
+	terms := parseQuery(query)
 	if len(terms) == 0 {
 		return []*Result{}, nil
 	}
@@ -49,21 +58,35 @@ func (e *Engine) Search(query string, limit int) ([]*Result, error) {
 		return nil, err
 	}
 
+	feedsByID := make(map[string]*storage.Feed, len(feeds))
 	for _, feed := range feeds {
+		feedsByID[feed.ID] = feed
 		if result := e.searchFeed(feed, terms); result != nil {
 			results = append(results, result)
 		}
+	}
 
-		articles, err := e.store.GetArticles(feed.ID, basicSearchArticleScanLimit)
-		if err != nil {
-			continue
-		}
-
-		for _, article := range articles {
+	// Walk articles newest first and stop once enough candidates have
+	// matched for the final cut to be meaningful, or the scan budget is
+	// spent. Recent articles also carry the recency boost, so the ones
+	// skipped are the least likely to rank.
+	wanted := limit * basicSearchCandidateFactor
+	if limit <= 0 {
+		wanted = e.scanLimit
+	}
+	matched, scanned := 0, 0
+	err = e.store.ScanArticlesByDate(func(article *storage.Article) bool {
+		scanned++
+		if feed, ok := feedsByID[article.FeedID]; ok {
 			if result := e.searchArticle(feed, article, terms); result != nil {
 				results = append(results, result)
+				matched++
 			}
 		}
+		return matched < wanted && scanned < e.scanLimit
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	sort.Slice(results, func(i, j int) bool {
@@ -83,7 +106,7 @@ func (e *Engine) SearchInArticle(article *storage.Article, query string) ([]*Res
 		return []*Result{}, nil
 	}
 
-	terms := tokenize(query)
+	terms := parseQuery(query)
 	if len(terms) == 0 {
 		return []*Result{}, nil
 	}
@@ -195,20 +218,38 @@ func (e *Engine) searchArticle(feed *storage.Feed, article *storage.Article, ter
 	return nil
 }
 
+// phraseWordScore is what each word of a matched quoted phrase adds in
+// scoreField: a little more than the 2.0 + 1.5 an exact single word
+// earns, so a contiguous phrase beats its words found apart.
+const phraseWordScore = 4.0
+
 // scoreField calculates relevance score for a field
 func (e *Engine) scoreField(text string, terms []string, weight float64) float64 {
 	if text == "" {
 		return 0
 	}
 
-	lower := strings.ToLower(text)
+	lower := foldText(text)
 	words := tokenize(text)
+	// Phrases are matched against the token stream rather than the raw
+	// text so punctuation and repeated spaces between words don't matter.
+	joined := " " + strings.Join(words, " ") + " "
 
 	var score float64
 	matchedTerms := 0
 
 	for _, term := range terms {
-		termLower := strings.ToLower(term)
+		termLower := foldText(term)
+
+		if isPhrase(termLower) {
+			// A quoted phrase only counts when its words appear
+			// contiguously.
+			if strings.Contains(joined, " "+termLower+" ") {
+				score += phraseWordScore * float64(strings.Count(termLower, " ")+1)
+				matchedTerms++
+			}
+			continue
+		}
 
 		// Exact phrase match (highest score)
 		if strings.Contains(lower, termLower) {
@@ -218,7 +259,7 @@ func (e *Engine) scoreField(text string, terms []string, weight float64) float64
 
 		// Word boundary matches (medium score)
 		for _, word := range words {
-			wordLower := strings.ToLower(word)
+			wordLower := word
 			switch {
 			case wordLower == termLower:
 				score += 1.5
@@ -266,11 +307,11 @@ func (e *Engine) findBestSnippet(text string, terms []string, maxLength int) str
 
 	// Sliding window to find best snippet
 	for i := 0; i <= len(words)-windowSize; i++ {
-		windowText := strings.Join(words[i:i+windowSize], " ")
+		windowText := foldText(strings.Join(words[i:i+windowSize], " "))
 		score := 0.0
 
 		for _, term := range terms {
-			if strings.Contains(strings.ToLower(windowText), strings.ToLower(term)) {
+			if strings.Contains(windowText, foldText(term)) {
 				score += 1.0
 			}
 		}
@@ -285,8 +326,53 @@ func (e *Engine) findBestSnippet(text string, terms []string, maxLength int) str
 	return truncate(snippet, maxLength)
 }
 
-// tokenize breaks text into searchable terms
+// foldText lowercases text and strips diacritics (NFKD, then drop the
+// combining marks) so "Café" and "cafe" compare equal. Compatibility
+// decomposition also flattens ligatures and full-width forms.
+func foldText(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range norm.NFKD.String(text) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// tokenize breaks text into searchable terms, folded with foldText.
 func tokenize(text string) []string {
+	return splitWords(foldText(text))
+}
+
+// parseQuery turns a user query into search terms. Text inside double
+// quotes becomes a single phrase term with its words joined by one space
+// (see isPhrase); everything else is split into individual words. An
+// unbalanced quote runs to the end of the query. Terms are lowercased but
+// not folded, since the bleve index stores accented text as-is; the
+// basic engine folds them itself.
+func parseQuery(query string) []string {
+	var terms []string
+	for i, segment := range strings.Split(query, `"`) {
+		words := splitWords(segment)
+		if i%2 == 1 && len(words) > 1 {
+			terms = append(terms, strings.Join(words, " "))
+			continue
+		}
+		terms = append(terms, words...)
+	}
+	return terms
+}
+
+// isPhrase reports whether term came from a quoted multi-word segment.
+func isPhrase(term string) bool {
+	return strings.Contains(term, " ")
+}
+
+// splitWords breaks text into lowercase runs of letters and digits,
+// dropping single characters.
+func splitWords(text string) []string {
 	var terms []string
 	current := strings.Builder{}
 
@@ -316,11 +402,17 @@ func truncate(text string, maxLen int) string {
 	return text[:maxLen-1] + "…"
 }
 
-// basicSearchArticleScanLimit caps how many articles per feed the
-// brute-force engine examines for a single Search call. Without this
-// the search latency scales with the largest feed; the bleve engine is
-// preferred when this matters.
-const basicSearchArticleScanLimit = 200
+// basicSearchScanLimit is the default cap on how many articles the
+// brute-force engine examines for a single Search call, so latency stays
+// bounded however large the database grows; the bleve engine is preferred
+// when recall over the full archive matters. It matches
+// config.DefaultSearchScanLimit.
+const basicSearchScanLimit = 5000
+
+// basicSearchCandidateFactor sets how many matching articles, as a
+// multiple of the requested limit, Search collects before it stops
+// scanning.
+const basicSearchCandidateFactor = 5
 
 // recencyWindow caps how old an article can be and still receive any
 // recency boost; older articles get 0.
@@ -0,0 +1,95 @@
+package search
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pders01/fwrd/internal/storage"
+)
+
+// seedSearchStore fills a store with feeds*perFeed articles, every tenth of
+// which mentions "kubernetes".
+func seedSearchStore(tb testing.TB, feeds, perFeed int) *storage.Store {
+	tb.Helper()
+	store, err := storage.NewStore(filepath.Join(tb.TempDir(), "search.db"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { store.Close() })
+
+	base := time.Now().Add(-time.Duration(feeds*perFeed) * time.Minute)
+	for f := range feeds {
+		feed := &storage.Feed{ID: fmt.Sprintf("feed%03d", f), Title: fmt.Sprintf("Feed %d", f)}
+		if err := store.SaveFeed(feed); err != nil {
+			tb.Fatal(err)
+		}
+		articles := make([]*storage.Article, perFeed)
+		for i := range perFeed {
+			topic := "gardening tips"
+			if i%10 == 0 {
+				topic = "kubernetes operators"
+			}
+			articles[i] = &storage.Article{
+				ID:          fmt.Sprintf("%s:a%04d", feed.ID, i),
+				FeedID:      feed.ID,
+				Title:       fmt.Sprintf("Post %d about %s", i, topic),
+				Description: "A short summary of " + topic,
+				Content:     "Body text that goes on for a while about " + topic + " and other things.",
+				Published:   base.Add(time.Duration(f*perFeed+i) * time.Minute),
+			}
+		}
+		if err := store.SaveArticles(articles); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return store
+}
+
+// searchPerFeed is the previous Search strategy: load the newest
+// articles of every feed and score them all. Kept here as the baseline
+// for BenchmarkSearch_PerFeedScan.
+func searchPerFeed(e *Engine, query string, limit int) []*Result {
+	terms := tokenize(query)
+	feeds, _ := e.store.GetAllFeeds()
+	var results []*Result
+	for _, feed := range feeds {
+		if r := e.searchFeed(feed, terms); r != nil {
+			results = append(results, r)
+		}
+		articles, err := e.store.GetArticles(feed.ID, 200)
+		if err != nil {
+			continue
+		}
+		for _, a := range articles {
+			if r := e.searchArticle(feed, a, terms); r != nil {
+				results = append(results, r)
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+func BenchmarkSearch_DateIndexScan(b *testing.B) {
+	e := NewEngine(seedSearchStore(b, 50, 200), 0)
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := e.Search("kubernetes", 20); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearch_PerFeedScan(b *testing.B) {
+	e := NewEngine(seedSearchStore(b, 50, 200), 0)
+	b.ResetTimer()
+	for b.Loop() {
+		searchPerFeed(e, "kubernetes", 20)
+	}
+}
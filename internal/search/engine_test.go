@@ -11,14 +11,14 @@ import (
 
 func TestNewEngine(t *testing.T) {
 	store := &storage.Store{}
-	engine := NewEngine(store)
+	engine := NewEngine(store, 0)
 	assert.NotNil(t, engine)
 	assert.Equal(t, store, engine.store)
 }
 
 func TestSearchMinLength(t *testing.T) {
 	store := &storage.Store{}
-	engine := NewEngine(store)
+	engine := NewEngine(store, 0)
 
 	tests := []struct {
 		name  string
@@ -79,7 +79,7 @@ func TestMatchStructure(t *testing.T) {
 
 func TestSearchInArticle(t *testing.T) {
 	store := &storage.Store{}
-	engine := NewEngine(store)
+	engine := NewEngine(store, 0)
 
 	article := &storage.Article{
 		ID:      "test-1",
@@ -124,7 +124,7 @@ func TestSearchInArticle(t *testing.T) {
 
 func TestSearchInArticleNilArticle(t *testing.T) {
 	store := &storage.Store{}
-	engine := NewEngine(store)
+	engine := NewEngine(store, 0)
 
 	results, err := engine.SearchInArticle(nil, "test query")
 	assert.NoError(t, err)
@@ -173,6 +173,16 @@ func TestTokenize(t *testing.T) {
 			input:    "test@email.com hello-world",
 			expected: []string{"test", "email", "com", "hello", "world"},
 		},
+		{
+			name:     "diacritics folded",
+			input:    "Café Ångström naïve",
+			expected: []string{"cafe", "angstrom", "naive"},
+		},
+		{
+			name:     "compatibility forms folded",
+			input:    "ﬁnance ＧＯ",
+			expected: []string{"finance", "go"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -183,6 +193,51 @@ func TestTokenize(t *testing.T) {
 	}
 }
 
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "unquoted words",
+			input:    "Go Generics",
+			expected: []string{"go", "generics"},
+		},
+		{
+			name:     "quoted phrase",
+			input:    `"Go Generics" tutorial`,
+			expected: []string{"go generics", "tutorial"},
+		},
+		{
+			name:     "punctuation inside phrase",
+			input:    `"rust,  async"`,
+			expected: []string{"rust async"},
+		},
+		{
+			name:     "single quoted word",
+			input:    `"bleve"`,
+			expected: []string{"bleve"},
+		},
+		{
+			name:     "unbalanced quote runs to end",
+			input:    `news "open source`,
+			expected: []string{"news", "open source"},
+		},
+		{
+			name:     "accents kept",
+			input:    `"Café Society"`,
+			expected: []string{"café society"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseQuery(tt.input))
+		})
+	}
+}
+
 func TestTruncate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -253,7 +308,7 @@ func TestCalculateRecencyBoost(t *testing.T) {
 }
 
 func TestScoreField(t *testing.T) {
-	engine := NewEngine(&storage.Store{})
+	engine := NewEngine(&storage.Store{}, 0)
 
 	tests := []struct {
 		name     string
@@ -304,6 +359,20 @@ func TestScoreField(t *testing.T) {
 			weight:   1.0,
 			minScore: 2.0,
 		},
+		{
+			name:     "unaccented term matches accented text",
+			text:     "Crème Brûlée recipes",
+			terms:    []string{"creme", "brulee"},
+			weight:   1.0,
+			minScore: 4.0,
+		},
+		{
+			name:     "accented term matches plain text",
+			text:     "resume writing tips",
+			terms:    []string{"résumé"},
+			weight:   1.0,
+			minScore: 2.0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -315,7 +384,7 @@ func TestScoreField(t *testing.T) {
 }
 
 func TestFindBestSnippet(t *testing.T) {
-	engine := NewEngine(&storage.Store{})
+	engine := NewEngine(&storage.Store{}, 0)
 
 	tests := []struct {
 		name      string
@@ -352,6 +421,13 @@ func TestFindBestSnippet(t *testing.T) {
 			maxLength: 50,
 			contains:  "quick",
 		},
+		{
+			name:      "accent-insensitive window",
+			text:      "Lots of filler words before we finally reach the São Paulo café district and beyond it",
+			terms:     []string{"sao", "cafe"},
+			maxLength: 40,
+			contains:  "São Paulo",
+		},
 	}
 
 	for _, tt := range tests {
@@ -368,7 +444,7 @@ func TestFindBestSnippet(t *testing.T) {
 }
 
 func TestSearchFeed(t *testing.T) {
-	engine := NewEngine(&storage.Store{})
+	engine := NewEngine(&storage.Store{}, 0)
 
 	feed := &storage.Feed{
 		ID:          "feed1",
@@ -421,7 +497,7 @@ func TestSearchFeed(t *testing.T) {
 }
 
 func TestSearchArticle(t *testing.T) {
-	engine := NewEngine(&storage.Store{})
+	engine := NewEngine(&storage.Store{}, 0)
 
 	feed := &storage.Feed{
 		ID:    "feed1",
@@ -483,3 +559,76 @@ func TestSearchArticle(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchArticle_QuotedPhrase(t *testing.T) {
+	engine := NewEngine(&storage.Store{}, 0)
+	feed := &storage.Feed{ID: "feed1", Title: "Test Feed"}
+
+	contiguous := &storage.Article{ID: "a1", Title: "Machine learning in production"}
+	scattered := &storage.Article{ID: "a2", Title: "Learning to love the machine"}
+
+	// Unquoted, both titles match on the individual words.
+	terms := parseQuery("machine learning")
+	assert.NotNil(t, engine.searchArticle(feed, contiguous, terms))
+	assert.NotNil(t, engine.searchArticle(feed, scattered, terms))
+
+	// Quoted, only the contiguous title matches.
+	terms = parseQuery(`"machine learning"`)
+	assert.NotNil(t, engine.searchArticle(feed, contiguous, terms))
+	assert.Nil(t, engine.searchArticle(feed, scattered, terms))
+
+	// Mixed with a loose word, the phrase still has to be contiguous for
+	// the title to count towards it.
+	terms = parseQuery(`"machine learning" love`)
+	assert.Greater(t,
+		engine.scoreField(contiguous.Title, terms, 1.0),
+		engine.scoreField(scattered.Title, terms, 1.0))
+
+	// Phrase matching folds diacritics and ignores punctuation between words.
+	folded := &storage.Article{ID: "a3", Title: "Notes from the Café - Society page"}
+	assert.NotNil(t, engine.searchArticle(feed, folded, parseQuery(`"cafe society"`)))
+}
+
+func TestSearch_BoundedScanKeepsScoreOrder(t *testing.T) {
+	e := NewEngine(seedSearchStore(t, 5, 100), 0)
+
+	results, err := e.Search("kubernetes", 10)
+	assert.NoError(t, err)
+	assert.Len(t, results, 10)
+	for i := 1; i < len(results); i++ {
+		assert.GreaterOrEqual(t, results[i-1].Score, results[i].Score, "results must stay sorted by score")
+	}
+	for _, r := range results {
+		assert.True(t, r.IsArticle)
+		assert.Contains(t, r.Article.Title, "kubernetes")
+	}
+
+	// A term only present in the oldest article is still found when few
+	// candidates match.
+	oldest, err := e.store.GetArticle("feed000:a0000")
+	assert.NoError(t, err)
+	oldest.Title = "Unique zeppelin post"
+	assert.NoError(t, e.store.SaveArticles([]*storage.Article{oldest}))
+	results, err = e.Search("zeppelin", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "feed000:a0000", results[0].Article.ID)
+	}
+}
+
+func TestSearch_ScanLimitBoundsTheScan(t *testing.T) {
+	store := seedSearchStore(t, 1, 100)
+	oldest, err := store.GetArticle("feed000:a0000")
+	assert.NoError(t, err)
+	oldest.Title = "Unique zeppelin post"
+	assert.NoError(t, store.SaveArticles([]*storage.Article{oldest}))
+
+	// Fifty newest articles never reach the oldest one.
+	results, err := NewEngine(store, 50).Search("zeppelin", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	results, err = NewEngine(store, 0).Search("zeppelin", 10)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// GroupByFeed reorders ranked results so each feed's hits sit together:
+// the feed itself first, then its articles in their original order.
+// Groups keep the rank of their best hit. A feed that only matched
+// through its articles gets a header result (zero score, no matches) so
+// every article is listed under its feed. Articles whose title is
+// near-identical to the feed's or to an earlier article in the same
+// group are dropped.
+func GroupByFeed(results []*Result) []*Result {
+	type group struct {
+		header   *Result
+		articles []*Result
+		seen     map[string]bool
+	}
+	var order []string
+	groups := make(map[string]*group)
+	groupFor := func(id string) *group {
+		g, ok := groups[id]
+		if !ok {
+			g = &group{seen: make(map[string]bool)}
+			groups[id] = g
+			order = append(order, id)
+		}
+		return g
+	}
+
+	for _, r := range results {
+		if !r.IsArticle {
+			if r.Feed == nil {
+				continue
+			}
+			g := groupFor(r.Feed.ID)
+			g.header = r
+			continue
+		}
+		if r.Article == nil {
+			continue
+		}
+		g := groupFor(resultFeedID(r))
+		if g.header == nil && r.Feed != nil {
+			g.header = &Result{Feed: r.Feed}
+		}
+		g.articles = append(g.articles, r)
+	}
+
+	out := make([]*Result, 0, len(results))
+	for _, id := range order {
+		g := groups[id]
+		if g.header != nil {
+			g.seen[titleKey(g.header.Feed.Title)] = true
+			out = append(out, g.header)
+		}
+		for _, a := range g.articles {
+			key := titleKey(a.Article.Title)
+			if key != "" && g.seen[key] {
+				continue
+			}
+			g.seen[key] = true
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// resultFeedID is the feed an article result belongs to, preferring the
+// attached feed over the article's own FeedID.
+func resultFeedID(r *Result) string {
+	if r.Feed != nil && r.Feed.ID != "" {
+		return r.Feed.ID
+	}
+	return r.Article.FeedID
+}
+
+// titleKey folds a title down to its lowercase letters and digits so
+// titles differing only in case, accents, punctuation or spacing compare
+// equal.
+func titleKey(title string) string {
+	var b strings.Builder
+	for _, r := range foldText(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pders01/fwrd/internal/storage"
+)
+
+func TestGroupByFeed(t *testing.T) {
+	feedA := &storage.Feed{ID: "fa", Title: "Go Blog"}
+	feedB := &storage.Feed{ID: "fb", Title: "Rust Blog"}
+	article := func(id string, feed *storage.Feed, title string) *Result {
+		return &Result{Feed: feed, Article: &storage.Article{ID: id, FeedID: feed.ID, Title: title}, IsArticle: true}
+	}
+	results := []*Result{
+		article("b1", feedB, "Rust 1.80"),
+		{Feed: feedA},
+		article("a1", feedA, "Go 1.24"),
+		article("a2", feedA, "go 1.24!"),
+		article("a3", feedA, "The Go Blog"),
+		article("a4", feedA, "Go blog"),
+		article("b2", feedB, "Go 1.24"),
+	}
+
+	var got []string
+	for _, r := range GroupByFeed(results) {
+		if r.IsArticle {
+			got = append(got, r.Article.ID)
+		} else {
+			got = append(got, r.Feed.ID)
+		}
+	}
+	// fb ranks first through b1 and gets a synthetic header; a2 repeats a1
+	// and a4 repeats the feed title. Titles only collapse within a feed.
+	assert.Equal(t, []string{"fb", "b1", "b2", "fa", "a1", "a3"}, got)
+}
+
+func TestGroupByFeed_EmptyAndFeedless(t *testing.T) {
+	assert.Empty(t, GroupByFeed(nil))
+
+	orphan := &Result{Article: &storage.Article{ID: "x", FeedID: "gone", Title: "Orphan"}, IsArticle: true}
+	assert.Equal(t, []*Result{orphan}, GroupByFeed([]*Result{orphan}))
+}
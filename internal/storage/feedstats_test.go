@@ -100,14 +100,19 @@ func TestFeedStats_CountsAndUnreadTransitions(t *testing.T) {
 		t.Fatalf("after unread, f1 unread = %d, want 2", got)
 	}
 
-	// Re-saving an article as read removes it from the unread index without
-	// a separate mark call (idempotent membership).
-	if err := store.SaveArticles([]*Article{art("a2", "f1", true)}); err != nil {
-		t.Fatalf("re-save read: %v", err)
+	// Re-saving a read article from the feed (which reports it unread)
+	// keeps the stored read state, so the unread index is unchanged.
+	if err := store.MarkArticleRead("a2", true); err != nil {
+		t.Fatalf("MarkArticleRead: %v", err)
+	}
+	updated := art("a2", "f1", false)
+	updated.Title = "a2 (edited)"
+	if err := store.SaveArticles([]*Article{updated}); err != nil {
+		t.Fatalf("re-save: %v", err)
 	}
 	stats, _ = store.FeedStats()
 	if got := stats["f1"].Unread; got != 1 {
-		t.Fatalf("after re-save read, f1 unread = %d, want 1", got)
+		t.Fatalf("after re-save, f1 unread = %d, want 1", got)
 	}
 
 	// Starring must not touch the unread count.
@@ -120,6 +125,53 @@ func TestFeedStats_CountsAndUnreadTransitions(t *testing.T) {
 	}
 }
 
+func TestCountArticles(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	if err := store.SaveArticles([]*Article{
+		art("a1", "f1", false),
+		art("a2", "f1", true),
+		art("b1", "f2", false),
+	}); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+
+	for feedID, want := range map[string]int{"f1": 2, "f2": 1, "missing": 0} {
+		got, err := store.CountArticles(feedID)
+		if err != nil {
+			t.Fatalf("CountArticles(%q): %v", feedID, err)
+		}
+		if got != want {
+			t.Errorf("CountArticles(%q) = %d, want %d", feedID, got, want)
+		}
+	}
+}
+
+func TestCountUnread(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	if err := store.SaveArticles([]*Article{
+		art("a1", "f1", false),
+		art("a2", "f1", true),
+		art("a3", "f1", false),
+		art("b1", "f2", true),
+	}); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+
+	for feedID, want := range map[string]int{"f1": 2, "f2": 0, "missing": 0} {
+		got, err := store.CountUnread(feedID)
+		if err != nil {
+			t.Fatalf("CountUnread(%q): %v", feedID, err)
+		}
+		if got != want {
+			t.Errorf("CountUnread(%q) = %d, want %d", feedID, got, want)
+		}
+	}
+}
+
 // TestFeedStats_DeleteFeedClearsIndex confirms a deleted feed disappears from
 // both the total and unread index.
 func TestFeedStats_DeleteFeedClearsIndex(t *testing.T) {
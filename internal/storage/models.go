@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"encoding/json"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -9,16 +12,128 @@ type Feed struct {
 	URL          string    `json:"url"`
 	Title        string    `json:"title"`
 	Description  string    `json:"description"`
-	LastFetched  time.Time `json:"last_fetched"`
+	LastSuccess  time.Time `json:"last_fetched"`
 	ETag         string    `json:"etag"`
 	LastModified string    `json:"last_modified"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	// LastError holds the message from the most recent failed refresh, or
 	// "" when the last attempt succeeded. LastErrorAt timestamps that
-	// failure. LastFetched still tracks the last *successful* fetch, so the
+	// failure. LastSuccess still tracks the last *successful* fetch, so the
 	// two together distinguish "stale because failing" from "just stale".
 	LastError   string    `json:"last_error,omitempty"`
 	LastErrorAt time.Time `json:"last_error_at,omitzero"`
+	// LastAttempt is when a refresh last went out for the feed, whatever
+	// came of it; LastSuccess, under its older last_fetched key, is when
+	// one last succeeded (a 304 included). A LastSuccess well behind
+	// LastAttempt marks a feed that has been failing for that long.
+	LastAttempt time.Time `json:"last_attempt,omitzero"`
+	// Icon is a short user-chosen marker, usually an emoji, shown before
+	// the title in the TUI feed list. Empty means none was set.
+	Icon string `json:"icon,omitempty"`
+	// Tags are user-chosen labels for grouping feeds, in the order they
+	// were added; the TUI feed list can be narrowed to one of them.
+	Tags []string `json:"tags,omitempty"`
+	// ForceFormat, when set to "rss", "atom" or "json", makes refreshes
+	// parse the body as that format instead of detecting it. Empty means
+	// detect.
+	ForceFormat string `json:"force_format,omitempty"`
+	// UserAgentPreset names the feed.user_agents entry sent as this
+	// feed's User-Agent. Empty means the global feed.user_agent.
+	UserAgentPreset string `json:"user_agent_preset,omitempty"`
+	// Order is the feed's 1-based place in a manual sort, or 0 for none.
+	// Feeds with an order list first, ahead of the rest by title.
+	Order int `json:"order,omitempty"`
+	// Pinned feeds list before all others, whatever their order.
+	Pinned bool `json:"pinned,omitempty"`
+	// ReadingOrder is the order the feed's articles list in, one of
+	// ReadingOrderNewest or ReadingOrderOldest. Empty means newest.
+	ReadingOrder string `json:"reading_order,omitempty"`
+	// LastVisited is when the feed's article list was last opened in the
+	// TUI. Zero means never.
+	LastVisited time.Time `json:"last_visited,omitzero"`
+	// SiteURL is the homepage the feed belongs to, taken from its channel
+	// link on add and refresh. Empty when the feed names none.
+	SiteURL string `json:"site_url,omitempty"`
+	// HubURLs and SelfURL are the WebSub hubs and canonical feed URL the
+	// feed advertises in its atom:link rel="hub" and rel="self" elements,
+	// as of the last successful parse.
+	HubURLs []string `json:"hub_urls,omitempty"`
+	SelfURL string   `json:"self_url,omitempty"`
+	// Include and Exclude are keyword filters, each entry a
+	// case-insensitive regular expression matched against an article's
+	// title, description, content and tags. With Include set only
+	// articles matching one of its entries are stored; an article
+	// matching any Exclude entry never is. See Feed.Admits.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Reading orders a Feed's ReadingOrder may name.
+const (
+	ReadingOrderNewest = "newest"
+	ReadingOrderOldest = "oldest"
+)
+
+// OldestFirst reports whether the feed's articles read in publication
+// order, oldest first.
+func (f *Feed) OldestFirst() bool {
+	return f.ReadingOrder == ReadingOrderOldest
+}
+
+// CompileFilterRule compiles one Include or Exclude entry. A plain
+// keyword is a valid rule and matches as a substring.
+func CompileFilterRule(rule string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + rule)
+}
+
+// articleFilter is a feed's Include and Exclude rules, compiled.
+type articleFilter struct {
+	include, exclude []*regexp.Regexp
+}
+
+func (f *Feed) filter() articleFilter {
+	return articleFilter{include: compileRules(f.Include), exclude: compileRules(f.Exclude)}
+}
+
+// compileRules compiles rules, matching any that do not compile as a
+// literal: a rule saved by hand or an older version is still honored
+// rather than silently dropped.
+func compileRules(rules []string) []*regexp.Regexp {
+	out := make([]*regexp.Regexp, 0, len(rules))
+	for _, rule := range rules {
+		re, err := CompileFilterRule(rule)
+		if err != nil {
+			re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(rule))
+		}
+		out = append(out, re)
+	}
+	return out
+}
+
+func (af articleFilter) admits(a *Article) bool {
+	if len(af.include) == 0 && len(af.exclude) == 0 {
+		return true
+	}
+	text := a.Title + "\n" + a.Description + "\n" + a.Content + "\n" + strings.Join(a.Tags, "\n")
+	for _, re := range af.exclude {
+		if re.MatchString(text) {
+			return false
+		}
+	}
+	if len(af.include) == 0 {
+		return true
+	}
+	for _, re := range af.include {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Admits reports whether a passes the feed's Include and Exclude rules.
+func (f *Feed) Admits(a *Article) bool {
+	return f.filter().admits(a)
 }
 
 type Article struct {
@@ -32,5 +147,64 @@ type Article struct {
 	Updated     time.Time `json:"updated"`
 	Read        bool      `json:"read"`
 	Starred     bool      `json:"starred"`
-	MediaURLs   []string  `json:"media_urls"`
+	// Media keeps the media_urls key it had when entries were bare URL
+	// strings; Media still decodes those, so older records read fine.
+	Media []Media `json:"media_urls"`
+	// Tags are the item's categories as the feed gives them, duplicates
+	// and blanks dropped.
+	Tags []string `json:"tags,omitempty"`
+	// FetchedAt is when the article was first saved. SaveArticles sets
+	// it and keeps it across re-saves; it stands in for Published when
+	// a feed omits or mangles pubDate.
+	FetchedAt time.Time `json:"fetched_at,omitzero"`
+}
+
+// adoptStoredState copies the fields fwrd owns, rather than the feed, from
+// the stored version of an article being re-saved. A parsed article always
+// arrives unread and unstarred with no FetchedAt, so without this every
+// refresh would reset them. New store-owned fields belong here too.
+func (a *Article) adoptStoredState(stored *Article) {
+	a.Read = stored.Read
+	a.Starred = stored.Starred
+	if !stored.FetchedAt.IsZero() {
+		a.FetchedAt = stored.FetchedAt
+	}
+}
+
+// SortTime is the timestamp articles are ordered by: Published, or
+// FetchedAt when the feed gave no publish date.
+func (a *Article) SortTime() time.Time {
+	if a.Published.IsZero() {
+		return a.FetchedAt
+	}
+	return a.Published
+}
+
+// MediaURLs returns the URL of each media entry, in order.
+func (a *Article) MediaURLs() []string {
+	urls := make([]string, len(a.Media))
+	for i, m := range a.Media {
+		urls[i] = m.URL
+	}
+	return urls
+}
+
+// Media is one attachment of an article: an enclosure, the item image, or
+// an <img>/<video> in its HTML. MIME and Length come from enclosure
+// attributes and are empty when the feed did not supply them.
+type Media struct {
+	URL    string `json:"url"`
+	MIME   string `json:"type,omitempty"`
+	Length int64  `json:"length,omitempty"`
+}
+
+// UnmarshalJSON accepts the plain URL string older versions stored as
+// well as the object form.
+func (m *Media) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*m = Media{}
+		return json.Unmarshal(data, &m.URL)
+	}
+	type plain Media
+	return json.Unmarshal(data, (*plain)(m))
 }
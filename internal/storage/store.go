@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strings"
 	"sync/atomic"
@@ -21,6 +22,23 @@ import (
 // process already has the database open.
 var ErrDatabaseLocked = errors.New("database is locked by another fwrd process")
 
+// ErrStoreClosed is returned by write and point-lookup methods called on a
+// nil or zero-value Store (no open bbolt handle). List-style readers such as
+// GetAllFeeds return an empty result instead, so read-only views degrade
+// quietly while mutations fail loudly rather than nil-panicking.
+var ErrStoreClosed = errors.New("store is not open")
+
+// ErrFeedNotFound and ErrArticleNotFound are returned (wrapped with the
+// offending ID) by point lookups and mutations when the record does not
+// exist. Callers should test for them with errors.Is.
+var (
+	ErrFeedNotFound    = errors.New("feed not found")
+	ErrArticleNotFound = errors.New("article not found")
+)
+
+// ErrFeedExists is returned by MoveFeed when the destination ID is taken.
+var ErrFeedExists = errors.New("feed already exists")
+
 // MemoryPath is the sentinel database path that requests an isolated,
 // process-local store backed by a unique temp file. bbolt has no real
 // in-memory mode, so the store creates the file in os.TempDir() and
@@ -46,6 +64,10 @@ var (
 // rebuild on Open.
 var unreadIndexFlag = []byte("unread_index_v1")
 
+// mediaObjectsFlag marks (in metaBucket) that articles storing media as
+// bare URL strings have been rewritten in the Media object form.
+var mediaObjectsFlag = []byte("media_objects_v1")
+
 type Store struct {
 	db       *bolt.DB
 	tempPath string // non-empty when the store owns a temp file (MemoryPath)
@@ -73,11 +95,27 @@ type FeedStat struct {
 	Total  int
 }
 
+// Counts holds database-wide totals for the stats command.
+type Counts struct {
+	Feeds    int
+	Articles int
+	Unread   int
+	// Size is the database size in bytes.
+	Size int64
+	// Oldest and Newest bound the articles' sort times; both are zero
+	// when there are no articles.
+	Oldest time.Time
+	Newest time.Time
+	// ByFeed holds the per-feed counts FeedStats reports.
+	ByFeed map[string]FeedStat
+}
+
 // makeDateIndexKey creates a key for the date index that ensures newest-first ordering
 // when iterated with a cursor. Uses reverse timestamp (max - timestamp) for descending order.
-func makeDateIndexKey(published time.Time, articleID string) []byte {
+// Pass the article's SortTime so undated articles order by when they were fetched.
+func makeDateIndexKey(sortTime time.Time, articleID string) []byte {
 	// Use nanoseconds since epoch, inverted for reverse order
-	timestamp := published.UnixNano()
+	timestamp := sortTime.UnixNano()
 	reverseTimestamp := ^timestamp // Bitwise NOT for reverse ordering
 
 	key := make([]byte, 8+len(articleID))
@@ -86,10 +124,15 @@ func makeDateIndexKey(published time.Time, articleID string) []byte {
 	return key
 }
 
+// dateIndexKeyTime recovers the sort time encoded by makeDateIndexKey.
+func dateIndexKeyTime(key []byte) time.Time {
+	return time.Unix(0, ^int64(binary.BigEndian.Uint64(key[:8])))
+}
+
 // seekDateCursor positions a date-index cursor just past the entry identified
 // by the given article ID. The cursor is encoded as the article ID so callers
 // can pass back any article from a previous page; we look up the article's
-// Published timestamp once to reconstruct the composite index key, then use
+// sort timestamp once to reconstruct the composite index key, then use
 // bbolt's B+tree Seek (O(log n)) instead of a linear scan from First().
 //
 // Returns (nil, nil) when the cursor article has been deleted or when Seek
@@ -110,7 +153,7 @@ func seekDateCursor(ab *bolt.Bucket, dateCursor *bolt.Cursor, cursor string) (ke
 	if err := json.Unmarshal(raw, &art); err != nil {
 		return nil, nil
 	}
-	want := makeDateIndexKey(art.Published, art.ID)
+	want := makeDateIndexKey(art.SortTime(), art.ID)
 	key, articleID = dateCursor.Seek(want)
 	if key == nil {
 		return nil, nil
@@ -130,6 +173,13 @@ func NewStore(dbPath string) (*Store, error) {
 	return NewStoreWithTimeout(dbPath, DefaultOpenTimeout)
 }
 
+// NewTempStore opens an empty store of its own in a temp file that Close
+// deletes. It is the MemoryPath store, so it indexes and sorts exactly as
+// any other store on disk does.
+func NewTempStore() (*Store, error) {
+	return NewStore(MemoryPath)
+}
+
 func NewStoreWithTimeout(dbPath string, timeout time.Duration) (*Store, error) {
 	tempPath := ""
 	if dbPath == MemoryPath {
@@ -165,7 +215,10 @@ func NewStoreWithTimeout(dbPath string, timeout time.Duration) (*Store, error) {
 				return createErr
 			}
 		}
-		return buildUnreadIndexIfNeeded(tx)
+		if err := buildUnreadIndexIfNeeded(tx); err != nil {
+			return err
+		}
+		return upgradeLegacyMediaIfNeeded(tx)
 	})
 
 	if err != nil {
@@ -212,6 +265,52 @@ func buildUnreadIndexIfNeeded(tx *bolt.Tx) error {
 	return nil
 }
 
+// upgradeLegacyMediaIfNeeded rewrites articles saved when media was a list
+// of URL strings into the object form, once per database. Reading never
+// depends on it, since Media decodes both shapes, but it means nothing
+// newer has to keep producing or comparing the old one.
+func upgradeLegacyMediaIfNeeded(tx *bolt.Tx) error {
+	meta := tx.Bucket(metaBucket)
+	if meta != nil && meta.Get(mediaObjectsFlag) != nil {
+		return nil
+	}
+	if ab := tx.Bucket(articlesBucket); ab != nil {
+		// bbolt forbids writing to a bucket inside its own ForEach, so
+		// collect the upgrades first.
+		upgraded := map[string][]byte{}
+		err := ab.ForEach(func(k, v []byte) error {
+			var raw struct {
+				Media []json.RawMessage `json:"media_urls"`
+			}
+			if json.Unmarshal(v, &raw) != nil || len(raw.Media) == 0 || raw.Media[0][0] != '"' {
+				return nil
+			}
+			var a Article
+			if json.Unmarshal(v, &a) != nil {
+				return nil
+			}
+			data, err := json.Marshal(&a)
+			if err != nil {
+				return err
+			}
+			upgraded[string(k)] = data
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for k, data := range upgraded {
+			if err := ab.Put([]byte(k), data); err != nil {
+				return err
+			}
+		}
+	}
+	if meta != nil {
+		return meta.Put(mediaObjectsFlag, []byte{1})
+	}
+	return nil
+}
+
 // setUnreadMembership adds or removes an article's ID from its feed's unread
 // sub-bucket so FeedStats can count unread without decoding. Call within a
 // write tx whenever an article's Read state is established or changes.
@@ -234,6 +333,9 @@ func setUnreadMembership(tx *bolt.Tx, feedID, articleID string, unread bool) err
 }
 
 func (s *Store) Close() error {
+	if s == nil || s.db == nil {
+		return nil
+	}
 	closeErr := s.db.Close()
 	if s.tempPath != "" {
 		if rmErr := os.Remove(s.tempPath); rmErr != nil && !os.IsNotExist(rmErr) && closeErr == nil {
@@ -244,6 +346,9 @@ func (s *Store) Close() error {
 }
 
 func (s *Store) SaveFeed(feed *Feed) error {
+	if s == nil || s.db == nil {
+		return ErrStoreClosed
+	}
 	err := s.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket(feedsBucket)
 		data, err := json.Marshal(feed)
@@ -258,13 +363,46 @@ func (s *Store) SaveFeed(feed *Feed) error {
 	return err
 }
 
+// MarkFeedVisited sets the feed's LastVisited to at, reading and writing
+// the stored record in one transaction so nothing else about the feed is
+// overwritten. A feed that no longer exists is left alone.
+func (s *Store) MarkFeedVisited(id string, at time.Time) error {
+	if s == nil || s.db == nil {
+		return ErrStoreClosed
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(feedsBucket)
+		data := b.Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		var feed Feed
+		if err := json.Unmarshal(data, &feed); err != nil {
+			return err
+		}
+		feed.LastVisited = at
+		data, err := json.Marshal(&feed)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(id), data)
+	})
+	if err == nil {
+		s.writeGen.Add(1)
+	}
+	return err
+}
+
 func (s *Store) GetFeed(id string) (*Feed, error) {
+	if s == nil || s.db == nil {
+		return nil, ErrStoreClosed
+	}
 	var feed Feed
 	err := s.db.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket(feedsBucket)
 		data := b.Get([]byte(id))
 		if data == nil {
-			return fmt.Errorf("feed not found")
+			return fmt.Errorf("%w: %s", ErrFeedNotFound, id)
 		}
 		return json.Unmarshal(data, &feed)
 	})
@@ -287,8 +425,20 @@ func (s *Store) GetAllFeeds() ([]*Feed, error) {
 			return nil
 		})
 	})
-	// Sort feeds by Title (case-insensitive), fallback to URL
+	// Pinned feeds first; within pinned and unpinned alike, manually
+	// ordered feeds first, then the rest by Title (case-insensitive),
+	// falling back to URL.
 	sort.Slice(feeds, func(i, j int) bool {
+		if feeds[i].Pinned != feeds[j].Pinned {
+			return feeds[i].Pinned
+		}
+		oi, oj := feeds[i].Order, feeds[j].Order
+		if oi != oj {
+			if oi == 0 || oj == 0 {
+				return oj == 0
+			}
+			return oi < oj
+		}
 		ti := feeds[i].Title
 		tj := feeds[j].Title
 		if ti == "" {
@@ -302,6 +452,63 @@ func (s *Store) GetAllFeeds() ([]*Feed, error) {
 	return feeds, err
 }
 
+// SetFeedOrder makes ids the manual feed order: the first ID gets Order
+// 1, the next 2, and so on. Feeds left out lose any order they had and
+// list after the ordered ones. An unknown ID fails the whole call with
+// ErrFeedNotFound and nothing is changed.
+func (s *Store) SetFeedOrder(ids []string) error {
+	if s == nil || s.db == nil {
+		return ErrStoreClosed
+	}
+	order := make(map[string]int, len(ids))
+	for i, id := range ids {
+		order[id] = i + 1
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(feedsBucket)
+		for _, id := range ids {
+			if b.Get([]byte(id)) == nil {
+				return fmt.Errorf("%w: %s", ErrFeedNotFound, id)
+			}
+		}
+		type update struct {
+			key  []byte
+			data []byte
+		}
+		var updates []update
+		err := b.ForEach(func(k, v []byte) error {
+			var feed Feed
+			if err := json.Unmarshal(v, &feed); err != nil {
+				return err
+			}
+			if feed.Order == order[feed.ID] {
+				return nil
+			}
+			feed.Order = order[feed.ID]
+			data, err := json.Marshal(&feed)
+			if err != nil {
+				return err
+			}
+			updates = append(updates, update{key: append([]byte(nil), k...), data: data})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// Bolt forbids writing a bucket while iterating it.
+		for _, u := range updates {
+			if err := b.Put(u.key, u.data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		s.writeGen.Add(1)
+	}
+	return err
+}
+
 // FeedStats returns per-feed unread and total article counts for every feed
 // that has articles, in a single read transaction. Both counts come from
 // Bucket.Stats().KeyN on the per-feed index sub-buckets, so no article JSON
@@ -312,100 +519,251 @@ func (s *Store) FeedStats() (map[string]FeedStat, error) {
 	if s == nil || s.db == nil {
 		return stats, nil
 	}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return feedStatsTx(tx, stats)
+	})
+	return stats, err
+}
+
+// CountArticles returns how many articles feedID has, read from the key
+// count of its articles_by_feed sub-bucket. Unknown feeds count as 0.
+func (s *Store) CountArticles(feedID string) (int, error) {
+	if s == nil || s.db == nil {
+		return 0, ErrStoreClosed
+	}
+	var n int
 	err := s.db.View(func(tx *bolt.Tx) error {
 		if idxRoot := tx.Bucket(articlesByFeedBucket); idxRoot != nil {
-			if err := idxRoot.ForEach(func(feedID, _ []byte) error {
-				if fb := idxRoot.Bucket(feedID); fb != nil {
-					st := stats[string(feedID)]
-					st.Total = fb.Stats().KeyN
-					stats[string(feedID)] = st
-				}
-				return nil
-			}); err != nil {
-				return err
+			if fb := idxRoot.Bucket([]byte(feedID)); fb != nil {
+				n = fb.Stats().KeyN
 			}
 		}
+		return nil
+	})
+	return n, err
+}
+
+// CountUnread returns how many of feedID's articles are unread, read from
+// the key count of its articles_unread_by_feed sub-bucket. Unknown feeds
+// count as 0.
+func (s *Store) CountUnread(feedID string) (int, error) {
+	if s == nil || s.db == nil {
+		return 0, ErrStoreClosed
+	}
+	var n int
+	err := s.db.View(func(tx *bolt.Tx) error {
 		if unreadRoot := tx.Bucket(articlesUnreadByFeedBucket); unreadRoot != nil {
-			return unreadRoot.ForEach(func(feedID, _ []byte) error {
-				if fb := unreadRoot.Bucket(feedID); fb != nil {
-					st := stats[string(feedID)]
-					st.Unread = fb.Stats().KeyN
-					stats[string(feedID)] = st
-				}
-				return nil
-			})
+			if fb := unreadRoot.Bucket([]byte(feedID)); fb != nil {
+				n = fb.Stats().KeyN
+			}
 		}
 		return nil
 	})
-	return stats, err
+	return n, err
 }
 
+func feedStatsTx(tx *bolt.Tx, stats map[string]FeedStat) error {
+	if idxRoot := tx.Bucket(articlesByFeedBucket); idxRoot != nil {
+		if err := idxRoot.ForEach(func(feedID, _ []byte) error {
+			if fb := idxRoot.Bucket(feedID); fb != nil {
+				st := stats[string(feedID)]
+				st.Total = fb.Stats().KeyN
+				stats[string(feedID)] = st
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	if unreadRoot := tx.Bucket(articlesUnreadByFeedBucket); unreadRoot != nil {
+		return unreadRoot.ForEach(func(feedID, _ []byte) error {
+			if fb := unreadRoot.Bucket(feedID); fb != nil {
+				st := stats[string(feedID)]
+				st.Unread = fb.Stats().KeyN
+				stats[string(feedID)] = st
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// Counts returns database-wide totals in one read transaction. Like
+// FeedStats it reads bucket key counts instead of decoding articles; the
+// date range comes from the two ends of the date index.
+func (s *Store) Counts() (Counts, error) {
+	counts := Counts{ByFeed: map[string]FeedStat{}}
+	if s == nil || s.db == nil {
+		return counts, ErrStoreClosed
+	}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		counts.Size = tx.Size()
+		if fb := tx.Bucket(feedsBucket); fb != nil {
+			counts.Feeds = fb.Stats().KeyN
+		}
+		if ab := tx.Bucket(articlesBucket); ab != nil {
+			counts.Articles = ab.Stats().KeyN
+		}
+		if err := feedStatsTx(tx, counts.ByFeed); err != nil {
+			return err
+		}
+		for _, st := range counts.ByFeed {
+			counts.Unread += st.Unread
+		}
+		if dateIdx := tx.Bucket(articlesByDateBucket); dateIdx != nil {
+			c := dateIdx.Cursor()
+			if k, _ := c.First(); len(k) >= 8 {
+				counts.Newest = dateIndexKeyTime(k)
+			}
+			if k, _ := c.Last(); len(k) >= 8 {
+				counts.Oldest = dateIndexKeyTime(k)
+			}
+		}
+		return nil
+	})
+	return counts, err
+}
+
+// SaveArticles stores articles; see SaveChangedArticles.
 func (s *Store) SaveArticles(articles []*Article) error {
+	_, err := s.SaveChangedArticles(articles)
+	return err
+}
+
+// SaveChangedArticles stores articles and returns the ones it actually
+// wrote. An article already stored with the same feed-provided content
+// (see sameFeedContent) is left alone, so refreshing an unchanged feed
+// writes nothing and gives search nothing to re-index. Read, Starred and
+// FetchedAt belong to the stored record, not the feed: they are copied
+// from it onto every re-saved article, so a refresh never marks read
+// items unread. Articles their feed's Include and Exclude rules filter
+// out are not stored; already stored ones are left as they were.
+func (s *Store) SaveChangedArticles(articles []*Article) ([]*Article, error) {
+	if s == nil || s.db == nil {
+		return nil, ErrStoreClosed
+	}
+	changed := make([]*Article, 0, len(articles))
 	err := s.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket(articlesBucket)
-		idxRoot := tx.Bucket(articlesByFeedBucket)
-		dateIdx := tx.Bucket(articlesByDateBucket)
+		now := time.Now()
+		filters := make(map[string]articleFilter)
 		for _, article := range articles {
-			data, err := json.Marshal(article)
-			if err != nil {
-				return err
+			af, ok := filters[article.FeedID]
+			if !ok {
+				af = feedFilterTx(tx, article.FeedID)
+				filters[article.FeedID] = af
+			}
+			if !af.admits(article) {
+				continue
 			}
 			// Capture the prior record before overwriting. The date index
-			// is keyed by timestamp, so if a re-saved article's Published
+			// is keyed by timestamp, so if a re-saved article's sort time
 			// changed (e.g. a feed adds a pubDate to a previously undated
 			// item) the old key is orphaned: the article then surfaces
-			// twice in newest-first pagination, and a stale zero-time key
-			// floats to the very top. Delete the old key below.
-			var prevPublished time.Time
-			hadPrev := false
-			if dateIdx != nil {
-				if existing := b.Get([]byte(article.ID)); existing != nil {
-					var old Article
-					if json.Unmarshal(existing, &old) == nil {
-						prevPublished, hadPrev = old.Published, true
-					}
+			// twice in newest-first pagination, and a stale key floats
+			// out of order. Delete the old key below.
+			var prev *Article
+			if existing := b.Get([]byte(article.ID)); existing != nil {
+				var old Article
+				if json.Unmarshal(existing, &old) == nil {
+					prev = &old
 				}
 			}
-			if err := b.Put([]byte(article.ID), data); err != nil {
-				return err
-			}
-
-			// Update feed index: ensure sub-bucket for this feed exists and record article ID
-			if idxRoot != nil {
-				fb, err := idxRoot.CreateBucketIfNotExists([]byte(article.FeedID))
-				if err != nil {
-					return err
-				}
-				if err := fb.Put([]byte(article.ID), []byte{1}); err != nil {
-					return err
+			if prev != nil {
+				article.adoptStoredState(prev)
+				if !prev.FetchedAt.IsZero() && sameFeedContent(prev, article) {
+					continue
 				}
 			}
-
-			// Maintain the unread index. Setting membership to !Read is
-			// idempotent and correct without knowing the prior state: a new
-			// unread article is added, and a re-saved now-read article is
-			// removed.
-			if err := setUnreadMembership(tx, article.FeedID, article.ID, !article.Read); err != nil {
-				return err
+			if article.FetchedAt.IsZero() {
+				article.FetchedAt = now
 			}
-
-			// Update date index: store article ID with reverse timestamp key for newest-first ordering
-			if dateIdx != nil {
-				if hadPrev && !prevPublished.Equal(article.Published) {
-					_ = dateIdx.Delete(makeDateIndexKey(prevPublished, article.ID))
-				}
-				dateKey := makeDateIndexKey(article.Published, article.ID)
-				if err := dateIdx.Put(dateKey, []byte(article.ID)); err != nil {
-					return err
-				}
+			if err := putArticleTx(tx, article, prev); err != nil {
+				return err
 			}
+			changed = append(changed, article)
 		}
 		return nil
 	})
-	if err == nil {
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) > 0 {
 		s.writeGen.Add(1)
 	}
-	return err
+	return changed, nil
+}
+
+// feedFilterTx returns the article filter of the stored feed id, which
+// admits everything when the feed is unknown or sets no rules.
+func feedFilterTx(tx *bolt.Tx, id string) articleFilter {
+	data := tx.Bucket(feedsBucket).Get([]byte(id))
+	if data == nil {
+		return articleFilter{}
+	}
+	var feed Feed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return articleFilter{}
+	}
+	return feed.filter()
+}
+
+// putArticleTx writes article and its feed, unread and date index entries.
+// prev is the record being replaced, if any; its date key is dropped when
+// the sort time moved.
+func putArticleTx(tx *bolt.Tx, article, prev *Article) error {
+	data, err := json.Marshal(article)
+	if err != nil {
+		return err
+	}
+	if err := tx.Bucket(articlesBucket).Put([]byte(article.ID), data); err != nil {
+		return err
+	}
+
+	// Update feed index: ensure sub-bucket for this feed exists and record article ID
+	if idxRoot := tx.Bucket(articlesByFeedBucket); idxRoot != nil {
+		fb, err := idxRoot.CreateBucketIfNotExists([]byte(article.FeedID))
+		if err != nil {
+			return err
+		}
+		if err := fb.Put([]byte(article.ID), []byte{1}); err != nil {
+			return err
+		}
+	}
+
+	// Maintain the unread index. Setting membership to !Read is
+	// idempotent and correct without knowing the prior state, since
+	// Read was carried over from any prior record.
+	if err := setUnreadMembership(tx, article.FeedID, article.ID, !article.Read); err != nil {
+		return err
+	}
+
+	// Update date index: store article ID with reverse timestamp key for newest-first ordering
+	if dateIdx := tx.Bucket(articlesByDateBucket); dateIdx != nil {
+		if prev != nil && !prev.SortTime().Equal(article.SortTime()) {
+			_ = dateIdx.Delete(makeDateIndexKey(prev.SortTime(), article.ID))
+		}
+		dateKey := makeDateIndexKey(article.SortTime(), article.ID)
+		if err := dateIdx.Put(dateKey, []byte(article.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameFeedContent reports whether two versions of an article carry the
+// same data from the feed. Reader state (Read, Starred) and FetchedAt are
+// ignored.
+func sameFeedContent(a, b *Article) bool {
+	return a.FeedID == b.FeedID &&
+		a.Title == b.Title &&
+		a.Description == b.Description &&
+		a.Content == b.Content &&
+		a.URL == b.URL &&
+		a.Published.Equal(b.Published) &&
+		a.Updated.Equal(b.Updated) &&
+		slices.Equal(a.Media, b.Media) &&
+		slices.Equal(a.Tags, b.Tags)
 }
 
 func (s *Store) GetArticles(feedID string, limit int) ([]*Article, error) {
@@ -417,17 +775,17 @@ func (s *Store) GetArticles(feedID string, limit int) ([]*Article, error) {
 // if no article with that ID exists.
 func (s *Store) GetArticle(id string) (*Article, error) {
 	if s == nil || s.db == nil {
-		return nil, fmt.Errorf("store not initialized")
+		return nil, ErrStoreClosed
 	}
 	var article Article
 	err := s.db.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket(articlesBucket)
 		if b == nil {
-			return fmt.Errorf("article not found")
+			return fmt.Errorf("%w: %s", ErrArticleNotFound, id)
 		}
 		data := b.Get([]byte(id))
 		if data == nil {
-			return fmt.Errorf("article not found")
+			return fmt.Errorf("%w: %s", ErrArticleNotFound, id)
 		}
 		return json.Unmarshal(data, &article)
 	})
@@ -440,6 +798,14 @@ func (s *Store) GetArticle(id string) (*Article, error) {
 // GetArticlesWithCursor provides cursor-based pagination for efficient large dataset traversal.
 // cursor should be the article ID of the last article from the previous page, or empty for the first page.
 func (s *Store) GetArticlesWithCursor(feedID string, limit int, cursor string) ([]*Article, error) {
+	return s.GetArticlesSorted(feedID, limit, cursor, false)
+}
+
+// GetArticlesSorted is GetArticlesWithCursor with a choice of order:
+// oldestFirst lists a feed's articles in publication order instead of
+// newest first. It only applies to a single feed; across all feeds
+// (feedID "") articles always come newest first.
+func (s *Store) GetArticlesSorted(feedID string, limit int, cursor string, oldestFirst bool) ([]*Article, error) {
 	if s == nil || s.db == nil {
 		return []*Article{}, nil
 	}
@@ -457,7 +823,7 @@ func (s *Store) GetArticlesWithCursor(feedID string, limit int, cursor string) (
 			// in the database to filter for one feed; the per-feed
 			// bucket is bounded by len(articles_in_feed) and is
 			// strictly cheaper for typical feed sizes.
-			return s.getArticlesForFeed(tx, ab, feedID, limit, cursor, &articles)
+			return s.getArticlesForFeed(tx, ab, feedID, limit, cursor, oldestFirst, &articles)
 		}
 
 		// No feed specified: use date index for efficient sorted retrieval
@@ -468,9 +834,10 @@ func (s *Store) GetArticlesWithCursor(feedID string, limit int, cursor string) (
 }
 
 // getArticlesForFeed collects all articles in feedID's per-feed bucket,
-// sorts them by Published descending, then applies cursor + limit. The
-// scan is O(len(feed)) regardless of how many other feeds exist.
-func (s *Store) getArticlesForFeed(tx *bolt.Tx, ab *bolt.Bucket, feedID string, limit int, cursor string, articles *[]*Article) error {
+// sorts them by SortTime, descending unless oldestFirst, then applies
+// cursor + limit. The scan is O(len(feed)) regardless of how many other
+// feeds exist.
+func (s *Store) getArticlesForFeed(tx *bolt.Tx, ab *bolt.Bucket, feedID string, limit int, cursor string, oldestFirst bool, articles *[]*Article) error {
 	idxRoot := tx.Bucket(articlesByFeedBucket)
 	if idxRoot == nil {
 		return nil
@@ -497,15 +864,15 @@ func (s *Store) getArticlesForFeed(tx *bolt.Tx, ab *bolt.Bucket, feedID string,
 		*articles = append(*articles, &article)
 	}
 
-	// Sort by date (newest first), with article ID as a deterministic
-	// tie-breaker. Many feeds publish multiple items with identical
-	// pubDate values; without a secondary key the ordering of ties is
-	// arbitrary, which causes pagination to drop or duplicate articles
-	// across pages.
+	// Sort by date (newest first unless oldestFirst), with article ID
+	// as a deterministic tie-breaker. Many feeds publish multiple items
+	// with identical pubDate values; without a secondary key the ordering
+	// of ties is arbitrary, which causes pagination to drop or duplicate
+	// articles across pages.
 	sort.SliceStable(*articles, func(i, j int) bool {
 		ai, aj := (*articles)[i], (*articles)[j]
-		if !ai.Published.Equal(aj.Published) {
-			return ai.Published.After(aj.Published)
+		if ti, tj := ai.SortTime(), aj.SortTime(); !ti.Equal(tj) {
+			return ti.After(tj) != oldestFirst
 		}
 		return ai.ID < aj.ID
 	})
@@ -574,17 +941,69 @@ func (s *Store) getArticlesGlobalOptimized(tx *bolt.Tx, ab *bolt.Bucket, limit i
 	return nil
 }
 
+// ScanArticlesByDate calls fn with each stored article, newest first, until
+// fn returns false or the articles run out. The walk follows the date
+// index, so stopping early skips decoding the rest. fn runs inside a read
+// transaction and must not call the store's write methods.
+func (s *Store) ScanArticlesByDate(fn func(*Article) bool) error {
+	if s == nil || s.db == nil {
+		return ErrStoreClosed
+	}
+	return s.db.View(func(tx *bolt.Tx) error {
+		ab := tx.Bucket(articlesBucket)
+		dateIdx := tx.Bucket(articlesByDateBucket)
+		if ab == nil || dateIdx == nil {
+			return nil
+		}
+		c := dateIdx.Cursor()
+		for k, articleID := c.First(); k != nil; k, articleID = c.Next() {
+			v := ab.Get(articleID)
+			if v == nil {
+				continue
+			}
+			var article Article
+			if err := json.Unmarshal(v, &article); err != nil {
+				continue
+			}
+			if !fn(&article) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// GetUnreadArticles returns up to limit unread articles across all feeds,
+// newest first; limit <= 0 means no limit. It walks the date index and
+// stops as soon as limit articles are collected.
+func (s *Store) GetUnreadArticles(limit int) ([]*Article, error) {
+	if s == nil || s.db == nil {
+		return []*Article{}, nil
+	}
+	articles := []*Article{}
+	err := s.ScanArticlesByDate(func(a *Article) bool {
+		if !a.Read {
+			articles = append(articles, a)
+		}
+		return limit <= 0 || len(articles) < limit
+	})
+	return articles, err
+}
+
 // mutateArticle loads the article by id, applies fn, and writes it back in a
 // single transaction. The secondary date index and search index are left
 // untouched, so callers that change an indexed field must update those
 // themselves. A change to the Read flag is reflected in the unread index here
 // so FeedStats stays correct (the star toggle keys no index).
 func (s *Store) mutateArticle(id string, fn func(*Article)) error {
+	if s == nil || s.db == nil {
+		return ErrStoreClosed
+	}
 	err := s.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket(articlesBucket)
 		data := b.Get([]byte(id))
 		if data == nil {
-			return fmt.Errorf("article not found")
+			return fmt.Errorf("%w: %s", ErrArticleNotFound, id)
 		}
 
 		var article Article
@@ -625,65 +1044,131 @@ func (s *Store) MarkArticleStarred(id string, starred bool) error {
 }
 
 func (s *Store) DeleteFeed(id string) error {
+	if s == nil || s.db == nil {
+		return ErrStoreClosed
+	}
 	err := s.db.Update(func(tx *bolt.Tx) error {
-		feedBucket := tx.Bucket(feedsBucket)
-		if err := feedBucket.Delete([]byte(id)); err != nil {
-			return err
-		}
+		return deleteFeedTx(tx, id)
+	})
+	if err == nil {
+		s.writeGen.Add(1)
+	}
+	return err
+}
 
-		// Drop the feed's unread sub-bucket if present. DeleteBucket errors
-		// when the bucket is absent, so guard with a lookup first.
-		if unreadRoot := tx.Bucket(articlesUnreadByFeedBucket); unreadRoot != nil {
-			if unreadRoot.Bucket([]byte(id)) != nil {
-				if err := unreadRoot.DeleteBucket([]byte(id)); err != nil {
-					return fmt.Errorf("deleting per-feed unread index bucket: %w", err)
-				}
+// deleteFeedTx removes a feed, its articles and every index entry for them.
+func deleteFeedTx(tx *bolt.Tx, id string) error {
+	feedBucket := tx.Bucket(feedsBucket)
+	if err := feedBucket.Delete([]byte(id)); err != nil {
+		return err
+	}
+
+	// Drop the feed's unread sub-bucket if present. DeleteBucket errors
+	// when the bucket is absent, so guard with a lookup first.
+	if unreadRoot := tx.Bucket(articlesUnreadByFeedBucket); unreadRoot != nil {
+		if unreadRoot.Bucket([]byte(id)) != nil {
+			if err := unreadRoot.DeleteBucket([]byte(id)); err != nil {
+				return fmt.Errorf("deleting per-feed unread index bucket: %w", err)
 			}
 		}
+	}
 
-		ab := tx.Bucket(articlesBucket)
-		dateIdx := tx.Bucket(articlesByDateBucket)
-		idxRoot := tx.Bucket(articlesByFeedBucket)
-		if idxRoot == nil {
-			return nil
-		}
+	ab := tx.Bucket(articlesBucket)
+	dateIdx := tx.Bucket(articlesByDateBucket)
+	idxRoot := tx.Bucket(articlesByFeedBucket)
+	if idxRoot == nil {
+		return nil
+	}
 
-		fb := idxRoot.Bucket([]byte(id))
-		if fb == nil {
-			return nil
-		}
+	fb := idxRoot.Bucket([]byte(id))
+	if fb == nil {
+		return nil
+	}
 
-		// Walk the per-feed sub-bucket once; for each article ID, look
-		// up its full record before deleting so we can reconstruct the
-		// composite date-index key and remove that entry by Seek/Delete
-		// instead of a linear scan.
-		c := fb.Cursor()
-		for k, _ := c.First(); k != nil; k, _ = c.Next() {
-			articleID := append([]byte(nil), k...) // Cursor keys are tx-scoped; copy.
-			if ab != nil && dateIdx != nil {
-				if data := ab.Get(articleID); data != nil {
-					var art Article
-					if err := json.Unmarshal(data, &art); err == nil {
-						dateKey := makeDateIndexKey(art.Published, art.ID)
-						if err := dateIdx.Delete(dateKey); err != nil {
-							return fmt.Errorf("deleting date-index entry: %w", err)
-						}
+	// Walk the per-feed sub-bucket once; for each article ID, look
+	// up its full record before deleting so we can reconstruct the
+	// composite date-index key and remove that entry by Seek/Delete
+	// instead of a linear scan.
+	c := fb.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		articleID := append([]byte(nil), k...) // Cursor keys are tx-scoped; copy.
+		if ab != nil && dateIdx != nil {
+			if data := ab.Get(articleID); data != nil {
+				var art Article
+				if err := json.Unmarshal(data, &art); err == nil {
+					dateKey := makeDateIndexKey(art.SortTime(), art.ID)
+					if err := dateIdx.Delete(dateKey); err != nil {
+						return fmt.Errorf("deleting date-index entry: %w", err)
 					}
 				}
 			}
-			if ab != nil {
-				if err := ab.Delete(articleID); err != nil {
-					return fmt.Errorf("deleting article %s: %w", articleID, err)
+		}
+		if ab != nil {
+			if err := ab.Delete(articleID); err != nil {
+				return fmt.Errorf("deleting article %s: %w", articleID, err)
+			}
+		}
+	}
+
+	// Drop the per-feed sub-bucket. Propagating the error here is
+	// load-bearing: the surrounding tx will roll back every prior
+	// delete, so the post-failure state is the original feed +
+	// articles + indexes, not a half-deleted carcass.
+	if err := idxRoot.DeleteBucket([]byte(id)); err != nil {
+		return fmt.Errorf("deleting per-feed index bucket: %w", err)
+	}
+	return nil
+}
+
+// MoveFeed re-keys the feed stored under oldID to feed.ID, saving feed in
+// its place. Articles move along with it: articleID maps each old article
+// ID to its new one, and read and starred state carry over. It runs in one
+// transaction, so a failure leaves the old feed intact. A feed already
+// stored under feed.ID is ErrFeedExists.
+func (s *Store) MoveFeed(oldID string, feed *Feed, articleID func(string) string) error {
+	if s == nil || s.db == nil {
+		return ErrStoreClosed
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		feeds := tx.Bucket(feedsBucket)
+		if feeds.Get([]byte(oldID)) == nil {
+			return fmt.Errorf("%w: %s", ErrFeedNotFound, oldID)
+		}
+		if feeds.Get([]byte(feed.ID)) != nil {
+			return fmt.Errorf("%w: %s", ErrFeedExists, feed.ID)
+		}
+
+		var articles []*Article
+		if fb := tx.Bucket(articlesByFeedBucket).Bucket([]byte(oldID)); fb != nil {
+			ab := tx.Bucket(articlesBucket)
+			err := fb.ForEach(func(k, _ []byte) error {
+				var a Article
+				if data := ab.Get(k); data != nil && json.Unmarshal(data, &a) == nil {
+					articles = append(articles, &a)
 				}
+				return nil
+			})
+			if err != nil {
+				return err
 			}
 		}
+		if err := deleteFeedTx(tx, oldID); err != nil {
+			return err
+		}
 
-		// Drop the per-feed sub-bucket. Propagating the error here is
-		// load-bearing: the surrounding tx will roll back every prior
-		// delete, so the post-failure state is the original feed +
-		// articles + indexes, not a half-deleted carcass.
-		if err := idxRoot.DeleteBucket([]byte(id)); err != nil {
-			return fmt.Errorf("deleting per-feed index bucket: %w", err)
+		data, err := json.Marshal(feed)
+		if err != nil {
+			return err
+		}
+		if err := feeds.Put([]byte(feed.ID), data); err != nil {
+			return err
+		}
+		for _, a := range articles {
+			a.ID = articleID(a.ID)
+			a.FeedID = feed.ID
+			if err := putArticleTx(tx, a, nil); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -692,3 +1177,101 @@ func (s *Store) DeleteFeed(id string) error {
 	}
 	return err
 }
+
+// GetMeta returns the value stored under key in the metadata bucket, or
+// "" when the key is unset. Metadata is for small bits of app state (e.g.
+// the TUI's last-viewed feed); it does not bump WriteGen.
+func (s *Store) GetMeta(key string) (string, error) {
+	if s == nil || s.db == nil {
+		return "", ErrStoreClosed
+	}
+	var value string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if b := tx.Bucket(metaBucket); b != nil {
+			value = string(b.Get([]byte(key)))
+		}
+		return nil
+	})
+	return value, err
+}
+
+// SetMeta stores value under key in the metadata bucket. An empty value
+// deletes the key.
+func (s *Store) SetMeta(key, value string) error {
+	if s == nil || s.db == nil {
+		return ErrStoreClosed
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(metaBucket)
+		if value == "" {
+			return b.Delete([]byte(key))
+		}
+		return b.Put([]byte(key), []byte(value))
+	})
+}
+
+// searchHistoryKey holds the recent search queries in metaBucket, as a
+// JSON array, most recent first.
+var searchHistoryKey = []byte("search_history")
+
+// SearchHistory returns the recorded search queries, most recent first.
+func (s *Store) SearchHistory() ([]string, error) {
+	if s == nil || s.db == nil {
+		return nil, ErrStoreClosed
+	}
+	var history []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		history = searchHistoryTx(tx)
+		return nil
+	})
+	return history, err
+}
+
+// AddSearchHistory records query as the most recent search, moving it up
+// if it was already recorded, and keeps at most limit queries. Blank
+// queries and a limit below 1 record nothing.
+func (s *Store) AddSearchHistory(query string, limit int) error {
+	if s == nil || s.db == nil {
+		return ErrStoreClosed
+	}
+	query = strings.TrimSpace(query)
+	if query == "" || limit < 1 {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		history := []string{query}
+		for _, q := range searchHistoryTx(tx) {
+			if q != query {
+				history = append(history, q)
+			}
+		}
+		if len(history) > limit {
+			history = history[:limit]
+		}
+		data, err := json.Marshal(history)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(metaBucket).Put(searchHistoryKey, data)
+	})
+}
+
+// ClearSearchHistory forgets every recorded search query.
+func (s *Store) ClearSearchHistory() error {
+	if s == nil || s.db == nil {
+		return ErrStoreClosed
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaBucket).Delete(searchHistoryKey)
+	})
+}
+
+// searchHistoryTx decodes the stored history. A record that does not
+// decode is treated as empty; it is only a convenience.
+func searchHistoryTx(tx *bolt.Tx) []string {
+	var history []string
+	if data := tx.Bucket(metaBucket).Get(searchHistoryKey); data != nil {
+		_ = json.Unmarshal(data, &history)
+	}
+	return history
+}
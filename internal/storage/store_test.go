@@ -1,9 +1,13 @@
 package storage
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -40,7 +44,7 @@ func TestStore_SaveAndGetFeed(t *testing.T) {
 		URL:          "http://example.com/feed.xml",
 		Title:        "Test Feed",
 		Description:  "A test feed",
-		LastFetched:  time.Now(),
+		LastSuccess:  time.Now(),
 		ETag:         "\"abc123\"",
 		LastModified: "Wed, 01 Jan 2025 00:00:00 GMT",
 		UpdatedAt:    time.Now(),
@@ -75,8 +79,8 @@ func TestStore_GetFeed_NotFound(t *testing.T) {
 	defer cleanup()
 
 	_, err := store.GetFeed("non-existent")
-	if err == nil {
-		t.Error("expected error for non-existent feed, got nil")
+	if !errors.Is(err, ErrFeedNotFound) {
+		t.Errorf("expected ErrFeedNotFound, got %v", err)
 	}
 }
 
@@ -119,6 +123,87 @@ func TestStore_GetAllFeeds(t *testing.T) {
 	}
 }
 
+func TestStore_SetFeedOrder(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	for _, f := range []*Feed{
+		{ID: "a", Title: "Alpha"},
+		{ID: "b", Title: "Bravo"},
+		{ID: "c", Title: "Charlie"},
+		{ID: "d", Title: "Delta"},
+	} {
+		if err := store.SaveFeed(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feedIDs := func() string {
+		feeds, err := store.GetAllFeeds()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]string, len(feeds))
+		for i, f := range feeds {
+			ids[i] = f.ID
+		}
+		return strings.Join(ids, ",")
+	}
+
+	if got := feedIDs(); got != "a,b,c,d" {
+		t.Errorf("without an order got %s, want title order a,b,c,d", got)
+	}
+
+	if err := store.SetFeedOrder([]string{"c", "a"}); err != nil {
+		t.Fatalf("SetFeedOrder() error = %v", err)
+	}
+	if got := feedIDs(); got != "c,a,b,d" {
+		t.Errorf("got %s, want ordered feeds first then the rest by title: c,a,b,d", got)
+	}
+
+	if err := store.SetFeedOrder([]string{"d", "missing"}); !errors.Is(err, ErrFeedNotFound) {
+		t.Errorf("expected ErrFeedNotFound for an unknown ID, got %v", err)
+	}
+	if got := feedIDs(); got != "c,a,b,d" {
+		t.Errorf("a failed SetFeedOrder changed the order to %s", got)
+	}
+
+	if err := store.SetFeedOrder(nil); err != nil {
+		t.Fatalf("clearing order error = %v", err)
+	}
+	if got := feedIDs(); got != "a,b,c,d" {
+		t.Errorf("after clearing got %s, want title order a,b,c,d", got)
+	}
+}
+
+func TestStore_GetAllFeeds_PinnedFirst(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	for _, f := range []*Feed{
+		{ID: "a", Title: "alpha"},
+		{ID: "b", Title: "Bravo", Pinned: true},
+		{ID: "c", Title: "Charlie"},
+		{ID: "d", Title: "Delta", Pinned: true},
+	} {
+		if err := store.SaveFeed(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	feeds, err := store.GetAllFeeds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, f := range feeds {
+		ids = append(ids, f.ID)
+	}
+	if got := strings.Join(ids, ","); got != "b,d,a,c" {
+		t.Errorf("got %s, want pinned feeds first, each group by title: b,d,a,c", got)
+	}
+}
+
 func TestStore_SaveAndGetArticles(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
@@ -133,7 +218,7 @@ func TestStore_SaveAndGetArticles(t *testing.T) {
 			URL:         "http://example.com/article1",
 			Published:   time.Now().Add(-2 * time.Hour),
 			Read:        false,
-			MediaURLs:   []string{"http://example.com/image1.jpg"},
+			Media:       []Media{{URL: "http://example.com/image1.jpg"}},
 		},
 		{
 			ID:          "article2",
@@ -203,8 +288,8 @@ func TestStore_GetArticle(t *testing.T) {
 		t.Errorf("got %+v, want title=%q content=%q", got, want.Title, want.Content)
 	}
 
-	if _, err := store.GetArticle("missing"); err == nil {
-		t.Error("expected error for missing article, got nil")
+	if _, err := store.GetArticle("missing"); !errors.Is(err, ErrArticleNotFound) {
+		t.Errorf("expected ErrArticleNotFound, got %v", err)
 	}
 }
 
@@ -255,6 +340,10 @@ func TestStore_MarkArticleRead(t *testing.T) {
 	if articles[0].Read {
 		t.Error("article should be marked as unread")
 	}
+
+	if err := store.MarkArticleRead("missing", true); !errors.Is(err, ErrArticleNotFound) {
+		t.Errorf("expected ErrArticleNotFound for missing article, got %v", err)
+	}
 }
 
 func TestStore_DeleteFeed(t *testing.T) {
@@ -336,6 +425,44 @@ func TestStore_GetArticles_Limit(t *testing.T) {
 	}
 }
 
+func TestStore_GetArticlesSorted_OldestFirst(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	base := time.Now()
+	articles := make([]*Article, 5)
+	for i := range 5 {
+		articles[i] = &Article{
+			ID:        fmt.Sprintf("article%d", i),
+			FeedID:    "feed1",
+			Published: base.Add(time.Duration(i) * time.Hour),
+		}
+	}
+	if err := store.SaveArticles(articles); err != nil {
+		t.Fatalf("failed to save articles: %v", err)
+	}
+
+	var got []string
+	cursor := ""
+	for {
+		page, err := store.GetArticlesSorted("feed1", 2, cursor, true)
+		if err != nil {
+			t.Fatalf("failed to get articles: %v", err)
+		}
+		for _, a := range page {
+			got = append(got, a.ID)
+		}
+		if len(page) < 2 {
+			break
+		}
+		cursor = page[len(page)-1].ID
+	}
+	want := []string{"article0", "article1", "article2", "article3", "article4"}
+	if !slices.Equal(got, want) {
+		t.Errorf("oldest first got %v, want %v", got, want)
+	}
+}
+
 // TestStore_CursorPagination_TraversesFullSet verifies that cursor-based
 // pagination walks the entire date index without revisits or gaps. The
 // previous implementation used a linear First/Next scan to locate the
@@ -425,6 +552,60 @@ func TestStore_SaveArticles_ReSaveWithChangedDateNoDuplicate(t *testing.T) {
 	}
 }
 
+// TestStore_UndatedArticlesSortByFetchedAt checks that articles without a
+// pubDate order by first-seen time instead of sinking (or floating) as
+// zero timestamps, in both the global and the per-feed listings, and that
+// a re-save keeps the original FetchedAt.
+func TestStore_UndatedArticlesSortByFetchedAt(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	dated := &Article{ID: "dated", FeedID: "feed1", Published: time.Now().Add(-time.Hour)}
+	if err := store.SaveArticles([]*Article{dated}); err != nil {
+		t.Fatalf("save dated: %v", err)
+	}
+	if err := store.SaveArticles([]*Article{{ID: "u1", FeedID: "feed1"}}); err != nil {
+		t.Fatalf("save u1: %v", err)
+	}
+	if err := store.SaveArticles([]*Article{{ID: "u2", FeedID: "feed1"}}); err != nil {
+		t.Fatalf("save u2: %v", err)
+	}
+
+	first, err := store.GetArticle("u1")
+	if err != nil {
+		t.Fatalf("get u1: %v", err)
+	}
+	if first.FetchedAt.IsZero() {
+		t.Fatal("FetchedAt not set on first save")
+	}
+	// A refresh rebuilds the article from the feed, without FetchedAt.
+	if err := store.SaveArticles([]*Article{{ID: "u1", FeedID: "feed1", Title: "again"}}); err != nil {
+		t.Fatalf("re-save u1: %v", err)
+	}
+	again, err := store.GetArticle("u1")
+	if err != nil {
+		t.Fatalf("get u1: %v", err)
+	}
+	if !again.FetchedAt.Equal(first.FetchedAt) {
+		t.Errorf("FetchedAt changed on re-save: %v -> %v", first.FetchedAt, again.FetchedAt)
+	}
+
+	want := []string{"u2", "u1", "dated"}
+	for _, feedID := range []string{"", "feed1"} {
+		got, err := store.GetArticles(feedID, 0)
+		if err != nil {
+			t.Fatalf("GetArticles(%q): %v", feedID, err)
+		}
+		ids := make([]string, len(got))
+		for i, a := range got {
+			ids[i] = a.ID
+		}
+		if strings.Join(ids, ",") != strings.Join(want, ",") {
+			t.Errorf("GetArticles(%q) order = %v, want %v", feedID, ids, want)
+		}
+	}
+}
+
 // TestStore_CursorPagination_OrderingMatchesNewestFirst verifies that
 // successive pages return articles in strictly descending Published order.
 func TestStore_CursorPagination_OrderingMatchesNewestFirst(t *testing.T) {
@@ -639,3 +820,477 @@ func TestStore_DeleteFeed_RemovesIndexAndDateEntries(t *testing.T) {
 		t.Errorf("f2 article missing or wrong: %+v", got)
 	}
 }
+
+func TestStore_ZeroValueReturnsErrStoreClosed(t *testing.T) {
+	for name, s := range map[string]*Store{"zero": {}, "nil": nil} {
+		t.Run(name, func(t *testing.T) {
+			if err := s.SaveFeed(&Feed{ID: "f"}); !errors.Is(err, ErrStoreClosed) {
+				t.Errorf("SaveFeed: expected ErrStoreClosed, got %v", err)
+			}
+			if _, err := s.GetFeed("f"); !errors.Is(err, ErrStoreClosed) {
+				t.Errorf("GetFeed: expected ErrStoreClosed, got %v", err)
+			}
+			if err := s.SaveArticles([]*Article{{ID: "a", FeedID: "f"}}); !errors.Is(err, ErrStoreClosed) {
+				t.Errorf("SaveArticles: expected ErrStoreClosed, got %v", err)
+			}
+			if _, err := s.GetArticle("a"); !errors.Is(err, ErrStoreClosed) {
+				t.Errorf("GetArticle: expected ErrStoreClosed, got %v", err)
+			}
+			if err := s.MarkArticleRead("a", true); !errors.Is(err, ErrStoreClosed) {
+				t.Errorf("MarkArticleRead: expected ErrStoreClosed, got %v", err)
+			}
+			if err := s.MarkArticleStarred("a", true); !errors.Is(err, ErrStoreClosed) {
+				t.Errorf("MarkArticleStarred: expected ErrStoreClosed, got %v", err)
+			}
+			if err := s.DeleteFeed("f"); !errors.Is(err, ErrStoreClosed) {
+				t.Errorf("DeleteFeed: expected ErrStoreClosed, got %v", err)
+			}
+			if err := s.Close(); err != nil {
+				t.Errorf("Close: expected nil, got %v", err)
+			}
+
+			// List-style readers degrade to empty results rather than erroring.
+			feeds, err := s.GetAllFeeds()
+			if err != nil || len(feeds) != 0 {
+				t.Errorf("GetAllFeeds: expected empty/nil, got %v/%v", feeds, err)
+			}
+			arts, err := s.GetArticles("f", 10)
+			if err != nil || len(arts) != 0 {
+				t.Errorf("GetArticles: expected empty/nil, got %v/%v", arts, err)
+			}
+			unread, err := s.GetUnreadArticles(10)
+			if err != nil || len(unread) != 0 {
+				t.Errorf("GetUnreadArticles: expected empty/nil, got %v/%v", unread, err)
+			}
+		})
+	}
+}
+
+func TestStore_Meta(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	if v, err := store.GetMeta("ui.last_feed"); err != nil || v != "" {
+		t.Fatalf("unset key: got %q/%v, want empty/nil", v, err)
+	}
+	gen := store.WriteGen()
+	if err := store.SetMeta("ui.last_feed", "feed-1"); err != nil {
+		t.Fatalf("SetMeta: %v", err)
+	}
+	if v, _ := store.GetMeta("ui.last_feed"); v != "feed-1" {
+		t.Errorf("GetMeta = %q, want feed-1", v)
+	}
+	if store.WriteGen() != gen {
+		t.Error("SetMeta must not bump WriteGen")
+	}
+	if err := store.SetMeta("ui.last_feed", ""); err != nil {
+		t.Fatalf("SetMeta(clear): %v", err)
+	}
+	if v, _ := store.GetMeta("ui.last_feed"); v != "" {
+		t.Errorf("cleared key: got %q, want empty", v)
+	}
+}
+
+func TestStore_ScanArticlesByDate(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	base := time.Now()
+	var articles []*Article
+	for i := range 5 {
+		articles = append(articles, &Article{
+			ID:        fmt.Sprintf("a%d", i),
+			FeedID:    "feed1",
+			Published: base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	if err := store.SaveArticles(articles); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	var seen []string
+	err := store.ScanArticlesByDate(func(a *Article) bool {
+		seen = append(seen, a.ID)
+		return len(seen) < 3
+	})
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if got := strings.Join(seen, ","); got != "a4,a3,a2" {
+		t.Errorf("scan order = %s, want a4,a3,a2 (newest first, stopping after 3)", got)
+	}
+}
+
+func TestStore_GetUnreadArticles(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	base := time.Now()
+	var articles []*Article
+	for i := range 6 {
+		articles = append(articles, &Article{
+			ID:        fmt.Sprintf("a%d", i),
+			FeedID:    fmt.Sprintf("feed%d", i%2),
+			Published: base.Add(time.Duration(i) * time.Minute),
+			Read:      i == 4,
+		})
+	}
+	if err := store.SaveArticles(articles); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := store.MarkArticleRead("a1", true); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+
+	ids := func(arts []*Article) string {
+		var out []string
+		for _, a := range arts {
+			out = append(out, a.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	all, err := store.GetUnreadArticles(0)
+	if err != nil {
+		t.Fatalf("GetUnreadArticles: %v", err)
+	}
+	if got := ids(all); got != "a5,a3,a2,a0" {
+		t.Errorf("unread = %s, want a5,a3,a2,a0 (newest first, across feeds)", got)
+	}
+
+	limited, err := store.GetUnreadArticles(2)
+	if err != nil {
+		t.Fatalf("GetUnreadArticles(2): %v", err)
+	}
+	if got := ids(limited); got != "a5,a3" {
+		t.Errorf("limited unread = %s, want a5,a3", got)
+	}
+}
+
+func TestStore_Counts(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	empty, err := store.Counts()
+	if err != nil {
+		t.Fatalf("Counts on empty store: %v", err)
+	}
+	if empty.Articles != 0 || !empty.Oldest.IsZero() || !empty.Newest.IsZero() {
+		t.Errorf("empty store counts = %+v, want zero articles and dates", empty)
+	}
+
+	for _, id := range []string{"feed1", "feed2"} {
+		if err := store.SaveFeed(&Feed{ID: id, URL: "https://example.com/" + id}); err != nil {
+			t.Fatalf("save feed: %v", err)
+		}
+	}
+	oldest := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	newest := time.Date(2025, 6, 1, 8, 30, 0, 0, time.UTC)
+	articles := []*Article{
+		{ID: "a1", FeedID: "feed1", Published: oldest},
+		{ID: "a2", FeedID: "feed1", Published: newest, Read: true},
+		{ID: "a3", FeedID: "feed1", Published: oldest.AddDate(1, 0, 0)},
+		{ID: "b1", FeedID: "feed2", Published: oldest.AddDate(0, 6, 0)},
+	}
+	if err := store.SaveArticles(articles); err != nil {
+		t.Fatalf("save articles: %v", err)
+	}
+
+	counts, err := store.Counts()
+	if err != nil {
+		t.Fatalf("Counts: %v", err)
+	}
+	if counts.Feeds != 2 || counts.Articles != 4 || counts.Unread != 3 {
+		t.Errorf("counts = feeds %d, articles %d, unread %d; want 2, 4, 3",
+			counts.Feeds, counts.Articles, counts.Unread)
+	}
+	if !counts.Oldest.Equal(oldest) || !counts.Newest.Equal(newest) {
+		t.Errorf("date range = %v..%v, want %v..%v", counts.Oldest, counts.Newest, oldest, newest)
+	}
+	if got := counts.ByFeed["feed1"]; got != (FeedStat{Unread: 2, Total: 3}) {
+		t.Errorf("feed1 stats = %+v, want {Unread:2 Total:3}", got)
+	}
+	if counts.Size <= 0 {
+		t.Errorf("Size = %d, want a positive byte count", counts.Size)
+	}
+}
+
+func TestStore_SaveChangedArticlesSkipsUnchanged(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	published := time.Now().Add(-time.Hour)
+	parsed := func() []*Article {
+		return []*Article{
+			{ID: "a1", FeedID: "feed1", Title: "One", URL: "https://example.com/1", Published: published},
+			{ID: "a2", FeedID: "feed1", Title: "Two", URL: "https://example.com/2", Published: published},
+		}
+	}
+
+	written, err := store.SaveChangedArticles(parsed())
+	if err != nil {
+		t.Fatalf("first save: %v", err)
+	}
+	if len(written) != 2 {
+		t.Fatalf("first save wrote %d articles, want 2", len(written))
+	}
+	if err := store.MarkArticleRead("a1", true); err != nil {
+		t.Fatalf("mark read: %v", err)
+	}
+	if err := store.MarkArticleStarred("a2", true); err != nil {
+		t.Fatalf("mark starred: %v", err)
+	}
+
+	gen := store.WriteGen()
+	again := parsed()
+	written, err = store.SaveChangedArticles(again)
+	if err != nil {
+		t.Fatalf("second save: %v", err)
+	}
+	if len(written) != 0 {
+		t.Errorf("unchanged re-save wrote %d articles, want 0", len(written))
+	}
+	if store.WriteGen() != gen {
+		t.Errorf("unchanged re-save bumped WriteGen")
+	}
+	if !again[0].Read || !again[1].Starred {
+		t.Errorf("skipped articles should carry the stored state, got read=%v starred=%v", again[0].Read, again[1].Starred)
+	}
+
+	retagged := parsed()
+	retagged[0].Tags = []string{"news"}
+	if written, err = store.SaveChangedArticles(retagged); err != nil || len(written) != 1 {
+		t.Fatalf("retagged re-save wrote %v (err %v), want only a1", written, err)
+	}
+
+	edited := parsed()
+	edited[0].Tags = []string{"news"}
+	edited[1].Content = "now with a body"
+	written, err = store.SaveChangedArticles(edited)
+	if err != nil {
+		t.Fatalf("third save: %v", err)
+	}
+	if len(written) != 1 || written[0].ID != "a2" {
+		t.Fatalf("edited re-save wrote %v, want only a2", written)
+	}
+	got, err := store.GetArticle("a2")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Content != "now with a body" || !got.Starred {
+		t.Errorf("a2 = content %q starred %v, want the new content and the star kept", got.Content, got.Starred)
+	}
+	if got, _ := store.GetArticle("a1"); got == nil || !got.Read {
+		t.Errorf("a1 lost its read state")
+	}
+}
+
+// TestLegacyMedia_UpgradedOnOpen writes an article the way builds before
+// Media did, with media_urls as plain strings, and checks it decodes and
+// is rewritten in the object form when the store is reopened.
+func TestLegacyMedia_UpgradedOnOpen(t *testing.T) {
+	legacy := `{"id":"a1","feed_id":"f1","title":"Episode","media_urls":["https://example.com/ep.mp3","https://example.com/cover.jpg"]}`
+
+	var decoded Article
+	if err := json.Unmarshal([]byte(legacy), &decoded); err != nil {
+		t.Fatalf("decoding legacy article: %v", err)
+	}
+	if got := strings.Join(decoded.MediaURLs(), " "); got != "https://example.com/ep.mp3 https://example.com/cover.jpg" {
+		t.Fatalf("legacy MediaURLs() = %q", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	store, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatalf("raw open: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(articlesBucket).Put([]byte("a1"), []byte(legacy)); err != nil {
+			return err
+		}
+		return tx.Bucket(metaBucket).Delete(mediaObjectsFlag)
+	})
+	db.Close()
+	if err != nil {
+		t.Fatalf("writing legacy article: %v", err)
+	}
+
+	store, err = NewStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer store.Close()
+
+	article, err := store.GetArticle("a1")
+	if err != nil {
+		t.Fatalf("GetArticle: %v", err)
+	}
+	if len(article.Media) != 2 || article.Media[0].URL != "https://example.com/ep.mp3" {
+		t.Errorf("Media = %+v, want both legacy URLs", article.Media)
+	}
+
+	err = store.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(articlesBucket).Get([]byte("a1"))
+		if !strings.Contains(string(raw), `{"url":"https://example.com/ep.mp3"}`) {
+			t.Errorf("article not rewritten in object form: %s", raw)
+		}
+		if tx.Bucket(metaBucket).Get(mediaObjectsFlag) == nil {
+			t.Error("upgrade flag not set")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMoveFeed(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	if err := store.SaveFeed(&Feed{ID: "old", URL: "https://old.example.com/feed"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveFeed(&Feed{ID: "taken", URL: "https://taken.example.com/feed"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveArticles([]*Article{art("old:1", "old", false), art("old:2", "old", true)}); err != nil {
+		t.Fatal(err)
+	}
+	rekey := func(id string) string { return "new:" + strings.TrimPrefix(id, "old:") }
+
+	err := store.MoveFeed("old", &Feed{ID: "taken", URL: "https://taken.example.com/feed"}, rekey)
+	if !errors.Is(err, ErrFeedExists) {
+		t.Fatalf("MoveFeed onto an existing feed error = %v, want ErrFeedExists", err)
+	}
+	if n, _ := store.CountArticles("old"); n != 2 {
+		t.Fatalf("failed move touched the old feed: %d articles left", n)
+	}
+
+	if err := store.MoveFeed("old", &Feed{ID: "new", URL: "https://new.example.com/feed"}, rekey); err != nil {
+		t.Fatalf("MoveFeed: %v", err)
+	}
+	if _, err := store.GetFeed("old"); !errors.Is(err, ErrFeedNotFound) {
+		t.Errorf("old feed still present: %v", err)
+	}
+	stats, err := store.FeedStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stats["new"]; got.Total != 2 || got.Unread != 1 {
+		t.Errorf("moved feed stats = %+v, want {Unread:1 Total:2}", got)
+	}
+	if _, ok := stats["old"]; ok {
+		t.Error("old feed still has index entries")
+	}
+	a, err := store.GetArticle("new:2")
+	if err != nil {
+		t.Fatalf("GetArticle: %v", err)
+	}
+	if a.FeedID != "new" || !a.Read {
+		t.Errorf("moved article = feed %q read %v, want feed \"new\" read", a.FeedID, a.Read)
+	}
+}
+
+func TestSaveChangedArticles_AppliesFeedFilter(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	feed := &Feed{ID: "f1", URL: "https://example.com/feed.xml", Include: []string{"golang", `rust\b`}, Exclude: []string{"crypto"}}
+	if err := store.SaveFeed(feed); err != nil {
+		t.Fatal(err)
+	}
+	articles := []*Article{
+		{ID: "keep", FeedID: "f1", Title: "Generics in GoLang"},
+		{ID: "tagged", FeedID: "f1", Title: "Ownership", Tags: []string{"rust"}},
+		{ID: "excluded", FeedID: "f1", Title: "Golang crypto wallets"},
+		{ID: "unmatched", FeedID: "f1", Title: "Python news"},
+		{ID: "other-feed", FeedID: "f2", Title: "Python news"},
+	}
+	saved, err := store.SaveChangedArticles(articles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, a := range saved {
+		ids = append(ids, a.ID)
+	}
+	if want := []string{"keep", "tagged", "other-feed"}; !slices.Equal(ids, want) {
+		t.Errorf("saved %v, want %v", ids, want)
+	}
+	for _, id := range []string{"excluded", "unmatched"} {
+		if a, _ := store.GetArticle(id); a != nil {
+			t.Errorf("filtered article %s was stored", id)
+		}
+	}
+	if a, err := store.GetArticle("keep"); err != nil || a == nil {
+		t.Errorf("matching article not stored: %v", err)
+	}
+}
+
+func TestSearchHistory(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	for _, q := range []string{"golang", "rust", " ", "zig", "golang"} {
+		if err := store.AddSearchHistory(q, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := store.SearchHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"golang", "zig", "rust"}; !slices.Equal(got, want) {
+		t.Errorf("SearchHistory() = %v, want %v", got, want)
+	}
+
+	if err := store.AddSearchHistory("ocaml", 2); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = store.SearchHistory(); !slices.Equal(got, []string{"ocaml", "golang"}) {
+		t.Errorf("SearchHistory() = %v after shrinking the limit, want [ocaml golang]", got)
+	}
+
+	if err := store.ClearSearchHistory(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = store.SearchHistory(); len(got) != 0 {
+		t.Errorf("SearchHistory() = %v after clearing, want none", got)
+	}
+}
+
+func TestMarkFeedVisited_TouchesOnlyLastVisited(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	if err := store.SaveFeed(&Feed{ID: "f", URL: "https://example.com/feed", Title: "New", ETag: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := store.MarkFeedVisited("f", at); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.GetFeed("f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.LastVisited.Equal(at) || got.Title != "New" || got.ETag != "v2" {
+		t.Errorf("GetFeed() = %+v, want LastVisited %v and the rest unchanged", got, at)
+	}
+
+	if err := store.MarkFeedVisited("gone", at); err != nil {
+		t.Fatalf("MarkFeedVisited() on a missing feed = %v, want nil", err)
+	}
+	if _, err := store.GetFeed("gone"); !errors.Is(err, ErrFeedNotFound) {
+		t.Errorf("GetFeed(gone) error = %v, want ErrFeedNotFound: the visit must not recreate it", err)
+	}
+}
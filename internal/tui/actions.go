@@ -0,0 +1,388 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyAction is one thing a key does in a view. Key dispatch, the status
+// bar hints and the ? overlay are all built from the same actions, so
+// adding an action here binds and documents it at once.
+type keyAction struct {
+	// keys trigger the action, spelled as tea.KeyMsg.String() spells
+	// them, so remapped bindings are picked up as configured.
+	keys []string
+	help string
+	// desc is the longer wording the ? overlay uses, where the action
+	// is read without its view around it. Empty means help.
+	desc string
+	// footer lists the action in the status bar hints right now. Every
+	// action appears in the ? overlay either way.
+	footer bool
+	// run performs the action and reports whether it took the key;
+	// false passes the key on to the view's list or viewport. A nil run
+	// marks a key the view's text input or list handles itself, listed
+	// for help only.
+	run func(key string) (tea.Cmd, bool)
+}
+
+func (act keyAction) description() string {
+	if act.desc != "" {
+		return act.desc
+	}
+	return act.help
+}
+
+func (act keyAction) hint() string {
+	return strings.Join(act.keys, "/") + ": " + act.help
+}
+
+// actions returns every action available in view, global ones first so
+// they win when a view binds the same key.
+func (kh *KeyHandler) actions(view View) []keyAction {
+	return append(kh.globalActions(view), kh.viewActions(view)...)
+}
+
+// dispatchAction runs the first action in the current view bound to key.
+func (kh *KeyHandler) dispatchAction(key string) (tea.Model, tea.Cmd, bool) {
+	for _, act := range kh.actions(kh.app.view) {
+		if act.run == nil || !slices.Contains(act.keys, key) {
+			continue
+		}
+		cmd, handled := act.run(key)
+		return kh.app, cmd, handled
+	}
+	return kh.app, nil, false
+}
+
+// footerHints lists the actions the status bar shows for the current
+// view, the view's own before the global ones.
+func (kh *KeyHandler) footerHints() []string {
+	view := kh.app.view
+	var hints []string
+	for _, act := range append(kh.viewActions(view), kh.globalActions(view)...) {
+		if act.footer {
+			hints = append(hints, act.hint())
+		}
+	}
+	return hints
+}
+
+// globalActions are available in every view that is not taking text.
+func (kh *KeyHandler) globalActions(view View) []keyAction {
+	a := kh.app
+	b := kh.config.Keys.Bindings
+	mod := kh.modifierKey
+
+	back, backFooter := "back", false
+	switch view {
+	case ViewAddFeed, ViewRenameFeed, ViewDeleteConfirm:
+		back, backFooter = "cancel", true
+	case ViewMedia, ViewTags:
+		backFooter = true
+	case ViewFeeds:
+		if a.feedTagFilter != "" {
+			back, backFooter = "clear tag", true
+		}
+	case ViewArticles, ViewAllUnread:
+		if a.tagFilter != "" {
+			back, backFooter = "clear tag", true
+		}
+	case ViewReader:
+		if len(a.find.lines) > 0 {
+			back, backFooter = "clear find", true
+		}
+	}
+	searchFooter := false
+	switch view {
+	case ViewFeeds, ViewArticles, ViewAllUnread, ViewReader, ViewSearch:
+		searchFooter = true
+	}
+
+	return []keyAction{
+		{keys: []string{kh.quitKey(), "ctrl+c"}, help: "quit", run: func(key string) (tea.Cmd, bool) {
+			// Quitting mid add/refresh can abandon a half-written import,
+			// so ask for a second press while the spinner is up.
+			if key == kh.quitKey() && a.spinnerActive && !a.quitPending {
+				a.quitPending = true
+				return nil, true
+			}
+			return a.quit(), true
+		}},
+		{keys: []string{"esc"}, help: back, footer: backFooter, run: func(string) (tea.Cmd, bool) {
+			_, cmd := kh.navigateBack()
+			return cmd, true
+		}},
+		{keys: []string{mod + b.Search}, help: "search", footer: searchFooter, run: func(string) (tea.Cmd, bool) {
+			_, cmd := kh.enterSearchMode()
+			return cmd, true
+		}},
+		{keys: []string{mod + b.ThemeToggle}, help: "theme", run: func(string) (tea.Cmd, bool) {
+			a.themePref = nextThemePref(a.themePref)
+			a.signalThemeChange()
+			return nil, true
+		}},
+		{keys: []string{helpOverlayKey}, help: "help", desc: "toggle help", run: func(string) (tea.Cmd, bool) {
+			// A bare "?" is text while a list filter is being typed.
+			if kh.isFilteringList() {
+				return nil, false
+			}
+			a.help.ShowAll = true
+			return nil, true
+		}},
+	}
+}
+
+// viewActions are the actions specific to view.
+func (kh *KeyHandler) viewActions(view View) []keyAction {
+	switch view {
+	case ViewFeeds:
+		return kh.feedsActions()
+	case ViewArticles, ViewAllUnread:
+		return kh.articlesActions(view)
+	case ViewReader:
+		return kh.readerActions()
+	case ViewSearch:
+		return []keyAction{{keys: []string{"tab"}, help: "results/input", desc: "search results"}}
+	case ViewMedia:
+		return kh.mediaActions()
+	case ViewTags:
+		return []keyAction{{keys: []string{"enter"}, help: "filter", footer: true}}
+	case ViewAddFeed:
+		return []keyAction{{keys: []string{"enter"}, help: "add", footer: true}}
+	case ViewRenameFeed:
+		return []keyAction{{keys: []string{"enter"}, help: "rename", footer: true}}
+	case ViewDeleteConfirm:
+		return []keyAction{{keys: []string{"enter"}, help: "confirm", footer: true, run: func(string) (tea.Cmd, bool) {
+			if kh.app.feedToDelete == nil {
+				return nil, false
+			}
+			kh.app.setStatus(MsgDeleting, 0)
+			return kh.app.deleteFeed(kh.app.feedToDelete.ID), true
+		}}}
+	default:
+		return nil
+	}
+}
+
+func (kh *KeyHandler) feedsActions() []keyAction {
+	a := kh.app
+	b := kh.config.Keys.Bindings
+	mod := kh.modifierKey
+	hasFeeds := len(a.feeds) > 0
+	selected := func() (feedItem, bool) {
+		i, ok := a.feedList.SelectedItem().(feedItem)
+		return i, ok
+	}
+
+	return []keyAction{
+		{keys: []string{mod + b.NewFeed}, help: "new", desc: "new feed", footer: true, run: func(string) (tea.Cmd, bool) {
+			a.view = ViewAddFeed
+			a.textInput.Reset()
+			a.textInput.Focus()
+			return nil, true
+		}},
+		{keys: []string{mod + b.Refresh}, help: "refresh", desc: "refresh all", footer: true, run: func(string) (tea.Cmd, bool) {
+			a.setStatus(MsgRefreshing, 0)
+			return tea.Batch(a.startSpinner(MsgRefreshing), a.refreshFeeds()), true
+		}},
+		{keys: []string{mod + b.UnreadInbox}, help: "unread", desc: "unread inbox", footer: true, run: func(string) (tea.Cmd, bool) {
+			a.view = ViewAllUnread
+			a.articleList.Select(0)
+			return a.loadUnreadArticles(), true
+		}},
+		{keys: []string{mod + b.OpenMedia}, help: "site", desc: "open feed site", footer: hasFeeds, run: func(string) (tea.Cmd, bool) {
+			// In the feed list "open" means the feed's homepage.
+			i, ok := selected()
+			if !ok {
+				return nil, true
+			}
+			if i.feed.SiteURL == "" {
+				a.setStatusWithKind(MsgNoSiteURL, StatusWarn, 0)
+				return nil, true
+			}
+			return kh.openURL(i.feed.SiteURL), true
+		}},
+		{keys: []string{mod + b.RenameFeed}, help: "rename", desc: "rename feed", footer: hasFeeds, run: func(string) (tea.Cmd, bool) {
+			i, ok := selected()
+			if !hasFeeds || !ok {
+				return nil, false
+			}
+			a.feedToRename = i.feed
+			a.view = ViewRenameFeed
+			a.textInput.SetValue(i.feed.Title)
+			a.textInput.Focus()
+			return nil, true
+		}},
+		{keys: []string{mod + b.DeleteFeed}, help: "delete", desc: "delete feed", footer: hasFeeds, run: func(string) (tea.Cmd, bool) {
+			i, ok := selected()
+			if !hasFeeds || !ok {
+				return nil, false
+			}
+			a.feedToDelete = i.feed
+			a.view = ViewDeleteConfirm
+			return nil, true
+		}},
+		{keys: []string{mod + b.TogglePin}, help: "pin feed", run: func(string) (tea.Cmd, bool) {
+			if i, ok := selected(); ok {
+				return a.togglePinned(i.feed), true
+			}
+			return nil, true
+		}},
+		{keys: []string{mod + b.FilterTag}, help: "tags", desc: "filter feeds by tag", footer: hasFeeds, run: func(string) (tea.Cmd, bool) {
+			return kh.openFeedTagPicker(), true
+		}},
+		{keys: []string{b.MoveFeedUp, b.MoveFeedDown}, help: "move feed up/down", run: func(key string) (tea.Cmd, bool) {
+			if key == b.MoveFeedUp {
+				return kh.moveSelectedFeed(-1), true
+			}
+			return kh.moveSelectedFeed(1), true
+		}},
+	}
+}
+
+func (kh *KeyHandler) articlesActions(view View) []keyAction {
+	a := kh.app
+	b := kh.config.Keys.Bindings
+	mod := kh.modifierKey
+	// A tag filter trims the hints to the ones that work on it.
+	unfiltered := a.tagFilter == ""
+	selected := func() (articleItem, bool) {
+		i, ok := a.articleList.SelectedItem().(articleItem)
+		return i, ok
+	}
+
+	return []keyAction{
+		{keys: []string{mod + b.OpenMedia}, help: "open", desc: "open link", footer: unfiltered, run: func(string) (tea.Cmd, bool) {
+			if i, ok := selected(); ok && i.article.URL != "" {
+				return kh.openURL(i.article.URL), true
+			}
+			return nil, true
+		}},
+		{keys: []string{mod + b.ToggleRead}, help: "toggle read", footer: unfiltered, run: func(string) (tea.Cmd, bool) {
+			if i, ok := selected(); ok {
+				return a.toggleRead(i.article), true
+			}
+			return nil, true
+		}},
+		{keys: []string{mod + b.ToggleStar}, help: "star", footer: unfiltered, run: func(string) (tea.Cmd, bool) {
+			if i, ok := selected(); ok {
+				return a.toggleStarred(i.article), true
+			}
+			return nil, true
+		}},
+		{keys: []string{b.NextUnread, b.PrevUnread}, help: "next/prev unread", footer: true, run: func(key string) (tea.Cmd, bool) {
+			// Bare keys belong to the filter input while the user is typing.
+			if a.articleList.FilterState() == list.Filtering {
+				return nil, false
+			}
+			if key == b.PrevUnread {
+				return kh.jumpToUnread(-1), true
+			}
+			return kh.jumpToUnread(1), true
+		}},
+		{keys: []string{mod + b.FilterTag}, help: "tags", desc: "filter by tag", footer: true, run: func(string) (tea.Cmd, bool) {
+			return kh.openTagPicker(), true
+		}},
+		// The unread inbox mixes feeds and always reads newest first.
+		{keys: []string{mod + b.ToggleOrder}, help: "order", desc: "oldest/newest first", footer: unfiltered && view == ViewArticles, run: func(string) (tea.Cmd, bool) {
+			if view == ViewArticles && a.currentFeed != nil {
+				return a.toggleReadingOrder(a.currentFeed), true
+			}
+			return nil, true
+		}},
+	}
+}
+
+func (kh *KeyHandler) readerActions() []keyAction {
+	a := kh.app
+	b := kh.config.Keys.Bindings
+	mod := kh.modifierKey
+	finding := len(a.find.lines) > 0
+
+	return []keyAction{
+		{keys: []string{mod + b.OpenMedia}, help: "open media", desc: "open link/media", footer: !finding, run: func(string) (tea.Cmd, bool) {
+			return kh.openArticleMedia(), true
+		}},
+		{keys: []string{mod + b.ToggleStar}, help: "star", footer: !finding, run: func(string) (tea.Cmd, bool) {
+			if a.currentArticle == nil {
+				return nil, true
+			}
+			return a.toggleStarred(a.currentArticle), true
+		}},
+		{keys: []string{mod + b.ToggleRaw}, help: "raw", desc: "raw view", footer: !finding, run: func(string) (tea.Cmd, bool) {
+			if a.currentArticle == nil {
+				return nil, true
+			}
+			a.readerRawMode = !a.readerRawMode
+			if a.readerRawMode {
+				a.setStatus(MsgRawView, 0)
+			} else {
+				a.setStatus(MsgRendered, 0)
+			}
+			return a.renderArticle(a.currentArticle), true
+		}},
+		{keys: []string{mod + b.SaveForLater}, help: "later", desc: "save for later", footer: !finding && a.readLater != nil, run: func(string) (tea.Cmd, bool) {
+			return a.saveForLater(a.currentArticle), true
+		}},
+		{keys: []string{findKey}, help: "find", desc: "find in article", footer: !finding, run: func(string) (tea.Cmd, bool) {
+			if a.currentArticle != nil && !a.loadingArticle {
+				return a.openFind(), true
+			}
+			return nil, true
+		}},
+		{keys: []string{findNextKey, findPrevKey}, help: "next/prev match", footer: finding, run: func(key string) (tea.Cmd, bool) {
+			if len(a.find.lines) == 0 {
+				return nil, false
+			}
+			if key == findNextKey {
+				a.stepFind(1)
+			} else {
+				a.stepFind(-1)
+			}
+			return nil, true
+		}},
+	}
+}
+
+func (kh *KeyHandler) mediaActions() []keyAction {
+	a := kh.app
+	b := kh.config.Keys.Bindings
+	mod := kh.modifierKey
+
+	return []keyAction{
+		{keys: []string{"enter", mod + b.OpenMedia}, help: "open", desc: "open media", footer: true, run: func(string) (tea.Cmd, bool) {
+			if item, ok := a.mediaList.SelectedItem().(mediaItem); ok {
+				return kh.openURL(item.url), true
+			}
+			return nil, true
+		}},
+		{keys: []string{mod + b.OpenAllMedia}, help: "open all", desc: "open all media", footer: true, run: func(string) (tea.Cmd, bool) {
+			return kh.openAllMedia(), true
+		}},
+	}
+}
+
+// openArticleMedia opens the reader's article: the media list when it
+// carries several media entries, otherwise its one media URL or its link.
+func (kh *KeyHandler) openArticleMedia() tea.Cmd {
+	article := kh.app.currentArticle
+	if article == nil {
+		return nil
+	}
+	if len(article.Media) > 1 {
+		_, cmd := kh.openMediaList()
+		return cmd
+	}
+	url := article.URL
+	if len(article.Media) == 1 {
+		url = article.Media[0].URL
+	}
+	if url == "" {
+		return nil
+	}
+	return kh.openURL(url)
+}
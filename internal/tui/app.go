@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/list"
@@ -18,13 +23,16 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/pders01/fwrd/internal/config"
 	"github.com/pders01/fwrd/internal/debuglog"
 	"github.com/pders01/fwrd/internal/feed"
+	"github.com/pders01/fwrd/internal/integrations"
 	"github.com/pders01/fwrd/internal/media"
 	pluginlua "github.com/pders01/fwrd/internal/plugins/lua"
 	"github.com/pders01/fwrd/internal/search"
 	"github.com/pders01/fwrd/internal/storage"
+	"github.com/pders01/fwrd/internal/validation"
 )
 
 // debugLogger adapts the package-level debuglog API to plugins/lua's
@@ -40,6 +48,7 @@ type App struct {
 	store            *storage.Store
 	manager          *feed.Manager
 	launcher         *media.Launcher
+	readLater        integrations.ReadLater
 	searchEngine     search.Searcher
 	searchEngineType string // "bleve" or "basic" - for UI display
 	icons            IconSet
@@ -48,6 +57,7 @@ type App struct {
 	articleList      list.Model
 	searchList       list.Model
 	mediaList        list.Model
+	tagList          list.Model
 	searchInput      textinput.Model
 	viewport         viewport.Model
 	textInput        textinput.Model
@@ -60,7 +70,10 @@ type App struct {
 	// from a search hit on a feed; otherwise ViewFeeds. navigateBack
 	// from ViewArticles uses this so search → feed-result → Esc returns
 	// the user to their search results rather than the feed list.
-	articlesOrigin  View
+	articlesOrigin View
+	// readerOrigin is the article list view (ViewArticles or
+	// ViewAllUnread) the reader was opened from; Esc returns there.
+	readerOrigin    View
 	feeds           []*storage.Feed
 	articles        []*storage.Article
 	currentFeed     *storage.Feed
@@ -77,6 +90,16 @@ type App struct {
 	themePref       string // user preference: "auto" / "light" / "dark"
 	glamourStyle    string // Resolved style passed to glamour ("dark"/"light"/NoTTY)
 	loadingArticle  bool   // Track if we're loading an article
+	renderCache     *renderCache
+
+	// reflowPending marks a reader re-render caused by a resize; the
+	// reader then returns to reflowScroll, its ScrollPercent before.
+	reflowPending bool
+	reflowScroll  float64
+
+	// noColor renders articles with glamour's ASCII profile (NO_COLOR,
+	// --no-color); the lipgloss styles follow the global profile.
+	noColor bool
 
 	// Article list pagination state. articlesCursor stores the last
 	// article ID returned by the most recent page so the next page can
@@ -88,6 +111,14 @@ type App struct {
 	articlesHasMore     bool
 	articlesLoadingMore bool
 
+	// visitCutoff is when the current feed was visited before this
+	// visit; articles since then list above the visit divider.
+	visitCutoff time.Time
+
+	// tempIndex is the throwaway search index a MemoryPath database
+	// gets; Close removes it along with the engine.
+	tempIndex string
+
 	// Theme change plumbing. themeEvents is signaled (without payload)
 	// whenever an external source — SIGUSR1 or the macOS plist watcher —
 	// asks the app to re-resolve. The reader-loop tea.Cmd installed in
@@ -100,11 +131,18 @@ type App struct {
 	searchSeq            int
 	pendingSearchQuery   string
 	searchDebounceMillis int
-
-	// Transient status bar message
-	statusText  string
-	statusKind  StatusKind
-	statusUntil time.Time
+	// Recent queries, newest first, and the one recalled into the
+	// search input (-1 for none).
+	searchHistory    []string
+	searchHistoryPos int
+
+	// Transient status bar message. statusSticky keeps it past
+	// statusUntil until the next key press.
+	statusText    string
+	statusKind    StatusKind
+	statusUntil   time.Time
+	statusSticky  bool
+	statusTimeout time.Duration
 
 	// Subtle spinner in status bar for long ops
 	statusSpinner spinner.Model
@@ -112,15 +150,57 @@ type App struct {
 	spinnerLabel  string
 	spinnerKind   StatusKind
 
+	// quitPending is set when quit was pressed while a spinner-backed
+	// operation was running; a second press confirms.
+	quitPending bool
+	// openAllPending is set when open-all was pressed on a long media
+	// list; a second press opens them.
+	openAllPending bool
+
+	// pendingRestore* hold the saved session selection until the
+	// matching article list loads (see restoreArticleSelection).
+	pendingRestoreFeedID    string
+	pendingRestoreArticleID string
+
+	// readMinutes is the estimated reading time of the article in the
+	// reader, shown next to the scroll position; 0 hides it.
+	readMinutes int
+	// markReadDelay is how long an article stays open before it is marked
+	// read; markReadSeq numbers article opens so a pending mark for an
+	// article since left is dropped.
+	markReadDelay time.Duration
+	markReadSeq   int
+	// tagFilter, when set, narrows the article list to articles carrying
+	// that tag; tagList is the picker it is chosen from.
+	tagFilter string
+	// feedTagFilter likewise narrows the feed list to feeds carrying
+	// that tag, matched case-insensitively.
+	feedTagFilter string
+	// readerRawMode shows the article body as delivered by the feed
+	// instead of the glamour rendering. Reset on each article open.
+	readerRawMode bool
+	// readerContent is the reader's rendered content without find
+	// highlighting; find is the find-in-page over it, typed into
+	// findInput.
+	readerContent string
+	find          readerFind
+	findInput     textinput.Model
+
 	// Lua plugin hot-reload watcher; nil when no plugin dir is
 	// available. shutdownOnce guards against double-Close.
 	pluginWatcherCancel context.CancelFunc
 	pluginWatcherWG     sync.WaitGroup
 	shutdownOnce        sync.Once
+
+	// opCtx bounds background feed operations (add, refresh). Close
+	// cancels it so quitting mid-operation aborts in-flight fetches
+	// instead of leaving them to race the store closing.
+	opCtx    context.Context
+	opCancel context.CancelFunc
 }
 
 func NewApp(store *storage.Store, cfg *config.Config) *App {
-	feedList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	feedList := list.New([]list.Item{}, newListDelegate(cfg.UI.CompactList), 0, 0)
 	feedList.Title = ""
 	feedList.SetShowStatusBar(false)
 	feedList.SetFilteringEnabled(true)
@@ -129,7 +209,7 @@ func NewApp(store *storage.Store, cfg *config.Config) *App {
 	feedList.Styles.Title = EmptyStyle
 	feedList.Styles.TitleBar = EmptyStyle
 
-	articleList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	articleList := list.New([]list.Item{}, newListDelegate(cfg.UI.CompactList), 0, 0)
 	articleList.Title = ""
 	articleList.SetShowStatusBar(false)
 	articleList.SetFilteringEnabled(true)
@@ -150,6 +230,20 @@ func NewApp(store *storage.Store, cfg *config.Config) *App {
 	mediaList.SetFilteringEnabled(false)
 	mediaList.SetShowHelp(true)
 
+	tagList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	tagList.Title = "› filter by tag"
+	tagList.SetShowStatusBar(false)
+	tagList.SetFilteringEnabled(false)
+	tagList.SetShowHelp(true)
+
+	if cfg.Keys.QuitRequiresModifier {
+		// The lists quit on a bare q of their own, which would undo the
+		// setting for any key our actions pass through to them.
+		for _, l := range []*list.Model{&feedList, &articleList, &searchList, &mediaList, &tagList} {
+			l.KeyMap.Quit.SetEnabled(false)
+		}
+	}
+
 	vp := viewport.New(0, 0)
 
 	ti := textinput.New()
@@ -159,67 +253,72 @@ func NewApp(store *storage.Store, cfg *config.Config) *App {
 	si := textinput.New()
 	si.Placeholder = "Search feeds and articles..."
 
+	fi := textinput.New()
+	fi.Prompt = findKey
+	fi.Placeholder = "find in article"
+
 	app := &App{
 		config:   cfg,
 		store:    store,
 		manager:  feed.NewManager(store, cfg),
 		launcher: media.NewLauncher(cfg),
+		// readLater set below; nil when no service is configured
 		// searchEngine set below (Bleve if available, otherwise fallback)
 		feedList:             feedList,
 		articleList:          articleList,
 		searchList:           searchList,
 		mediaList:            mediaList,
+		tagList:              tagList,
 		searchInput:          si,
 		viewport:             vp,
 		textInput:            ti,
+		findInput:            fi,
 		help:                 help.New(),
 		view:                 ViewFeeds,
 		previousView:         ViewFeeds,            // Initialize previous view
 		cameFromSearch:       false,                // Initialize navigation flag
 		searchResults:        []searchResultItem{}, // Initialize empty search results
 		searchDebounceMillis: pickPositive(cfg.UI.SearchDebounceMs, config.DefaultSearchDebounceMs),
+		searchHistoryPos:     -1,
+		statusTimeout:        time.Duration(pickPositive(cfg.UI.StatusTimeoutMs, config.DefaultStatusTimeoutMs)) * time.Millisecond,
 		themePref:            cfg.UI.Theme,
 		glamourStyle:         resolveGlamourStyle(cfg.UI.Theme),
 		themeEvents:          make(chan struct{}, 1),
+		markReadDelay:        time.Duration(max(cfg.UI.MarkReadDelayMs, 0)) * time.Millisecond,
+		renderCache:          newRenderCache(pickPositive(cfg.UI.RenderCacheSize, config.DefaultRenderCacheSize)),
 		icons:                NewIconSet(cfg.UI.Icons),
 	}
+	app.opCtx, app.opCancel = context.WithCancel(context.Background())
 
 	// Theme the lipgloss chrome to match the resolved (light/dark) style.
 	// The glamour reader already honors this; applyPalette extends it to the
 	// list/header/status UI. Re-applied on every live theme change below.
 	applyPalette(glamourStyleIsDark(app.glamourStyle))
 
-	// Prefer Bleve-backed engine if available (build with -tags=bleve)
-	// Use search index path from config, with fallback logic for special cases
-	idxPath := cfg.Database.SearchIndex
-	if idxPath == "" {
-		// Fallback: derive from DB path
-		dbPath := cfg.Database.Path
-		switch dbPath {
-		case "":
-			idxPath = "fwrd.bleve"
-		case storage.MemoryPath:
-			// Tests pass storage.MemoryPath; allocate a unique bleve
-			// index path so parallel test binaries don't collide.
-			idxPath = filepath.Join(os.TempDir(), fmt.Sprintf("fwrd-index-%d.bleve", time.Now().UnixNano()))
-		default:
-			base := strings.TrimSuffix(dbPath, filepath.Ext(dbPath))
-			idxPath = base + ".bleve"
+	// Prefer the Bleve-backed engine; fall back to the basic engine when
+	// the index path is rejected or the index can't be opened.
+	idxPath, err := searchIndexPath(cfg)
+	if err == nil && cfg.Database.SearchIndex == "" && cfg.Database.Path == storage.MemoryPath {
+		app.tempIndex = idxPath
+	}
+	if err == nil {
+		debuglog.Infof("Initializing search engine with index path: %s", idxPath)
+		var be search.Searcher
+		if be, err = search.NewBleveEngine(store, idxPath); err == nil && be != nil {
+			app.searchEngine = be
+			app.searchEngineType = "bleve"
+			debuglog.Infof("Successfully initialized Bleve search engine")
 		}
 	}
-	// Initialize search engine with fallback strategy
-	debuglog.Infof("Initializing search engine with index path: %s", idxPath)
-	if be, err := search.NewBleveEngine(store, idxPath); err == nil && be != nil {
-		app.searchEngine = be
-		app.searchEngineType = "bleve"
-		debuglog.Infof("Successfully initialized Bleve search engine")
-	} else {
+	if app.searchEngine == nil {
 		debuglog.Errorf("Bleve search engine initialization failed: %v", err)
 		debuglog.Infof("Falling back to basic search engine")
-		app.searchEngine = search.NewEngine(store)
+		app.searchEngine = search.NewEngine(store, cfg.Search.ScanLimit)
 		app.searchEngineType = "basic"
 	}
 
+	app.readLater = integrations.NewReadLater(cfg)
+
 	// Wire the search engine into the manager so it receives index updates
 	// after every successful add/refresh without the TUI re-implementing the
 	// dispatch.
@@ -271,6 +370,56 @@ func NewApp(store *storage.Store, cfg *config.Config) *App {
 	return app
 }
 
+// searchIndexPath returns where the Bleve index lives. A configured
+// Database.SearchIndex is used as-is once it passes the secure index path
+// checks; an invalid one is an error rather than a reason to quietly put
+// the index somewhere else. Only an empty setting derives the path from
+// the database location.
+func searchIndexPath(cfg *config.Config) (string, error) {
+	if idxPath := cfg.Database.SearchIndex; idxPath != "" {
+		validated, err := validation.NewSecurePathHandler().GetSecureIndexPath(idxPath)
+		if err != nil {
+			return "", fmt.Errorf("invalid search index path %q: %w", idxPath, err)
+		}
+		return validated, nil
+	}
+
+	dbPath := cfg.Database.Path
+	switch dbPath {
+	case "":
+		return "fwrd.bleve", nil
+	case storage.MemoryPath:
+		// Tests pass storage.MemoryPath; allocate a unique bleve
+		// index path so parallel test binaries don't collide.
+		return filepath.Join(os.TempDir(), fmt.Sprintf("fwrd-index-%d.bleve", time.Now().UnixNano())), nil
+	default:
+		return strings.TrimSuffix(dbPath, filepath.Ext(dbPath)) + ".bleve", nil
+	}
+}
+
+// SetPrintOpen makes media opens report the resolved command in the
+// status bar instead of launching it.
+func (a *App) SetPrintOpen(enabled bool) {
+	if a.launcher != nil {
+		a.launcher.DryRun = enabled
+	}
+}
+
+// SetNoColor makes the reader render articles without color, to match a
+// plain lipgloss profile set for NO_COLOR or --no-color.
+func (a *App) SetNoColor(enabled bool) {
+	a.noColor = enabled
+	a.glamourRenderer = nil
+}
+
+// SetPermissiveValidation lets feeds on localhost and private networks be
+// added, for local development.
+func (a *App) SetPermissiveValidation(permissive bool) {
+	if a.manager != nil {
+		a.manager.SetPermissiveValidation(permissive)
+	}
+}
+
 // SetForceRefresh configures the fetcher to ignore ETag/Last-Modified headers
 func (a *App) SetForceRefresh(force bool) {
 	if a.manager != nil {
@@ -279,10 +428,15 @@ func (a *App) SetForceRefresh(force bool) {
 }
 
 // Close releases App-owned resources that outlive the Bubble Tea
-// program loop — currently the plugin hot-reload watcher. Safe to call
-// multiple times.
+// program loop: background operations, the plugin hot-reload and theme
+// watchers, and the search engine. The Bleve index holds an on-disk lock
+// until closed, so skipping this blocks the next fwrd from opening it.
+// Safe to call multiple times.
 func (a *App) Close() {
 	a.shutdownOnce.Do(func() {
+		if a.opCancel != nil {
+			a.opCancel()
+		}
 		if a.pluginWatcherCancel != nil {
 			a.pluginWatcherCancel()
 		}
@@ -291,6 +445,16 @@ func (a *App) Close() {
 			a.themeWatchCancel()
 		}
 		a.themeWatchWG.Wait()
+		if c, ok := a.searchEngine.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				debuglog.Errorf("closing search engine: %v", err)
+			}
+		}
+		if a.tempIndex != "" {
+			if err := os.RemoveAll(a.tempIndex); err != nil {
+				debuglog.Errorf("removing search index: %v", err)
+			}
+		}
 	})
 }
 
@@ -340,7 +504,8 @@ func (a *App) waitThemeChange() tea.Cmd {
 // signal source (SIGUSR1, macOS plist watcher) has fired.
 type themeChangedMsg struct{}
 
-func (a *App) getRenderer() (*glamour.TermRenderer, error) {
+// readerWrapWidth is the column at which reader content wraps.
+func (a *App) readerWrapWidth() int {
 	wordWrapWidth := max(
 		// maximum for readability
 		min((a.width*9)/10,
@@ -351,22 +516,48 @@ func (a *App) getRenderer() (*glamour.TermRenderer, error) {
 	if a.width < NarrowScreenThreshold {
 		wordWrapWidth = max(getContentWidth(a.width), MinNarrowWidth)
 	}
+	if limit := a.config.UI.Article.MaxRenderWidth; limit > 0 {
+		wordWrapWidth = max(min(wordWrapWidth, limit), MinNarrowWidth)
+	}
+	return wordWrapWidth
+}
 
+func (a *App) getRenderer() (*glamour.TermRenderer, error) {
+	wordWrapWidth := a.readerWrapWidth()
 	if a.glamourRenderer == nil || abs(a.rendererWidth-wordWrapWidth) > RendererWidthTolerance {
-		r, err := glamour.NewTermRenderer(
+		opts := []glamour.TermRendererOption{
 			glamour.WithStandardStyle(a.glamourStyle),
 			glamour.WithWordWrap(wordWrapWidth),
-		)
+		}
+		if a.noColor {
+			opts = append(opts, glamour.WithColorProfile(termenv.Ascii))
+		}
+		r, err := glamour.NewTermRenderer(opts...)
 		if err != nil {
 			return nil, err
 		}
 		a.glamourRenderer = r
 		a.rendererWidth = wordWrapWidth
+		a.renderCache.clear()
 	}
 
 	return a.glamourRenderer, nil
 }
 
+// readerNeedsReflow reports whether the open article must be rendered
+// again for the current window: when the wrap width has moved past
+// RendererWidthTolerance, or when a max_render_width column has to be
+// centered anew.
+func (a *App) readerNeedsReflow() bool {
+	if a.view != ViewReader || a.currentArticle == nil || a.loadingArticle || a.glamourRenderer == nil {
+		return false
+	}
+	if a.config.UI.Article.MaxRenderWidth > 0 {
+		return true
+	}
+	return abs(a.rendererWidth-a.readerWrapWidth()) > RendererWidthTolerance
+}
+
 func abs(n int) int {
 	if n < 0 {
 		return -n
@@ -377,12 +568,24 @@ func abs(n int) int {
 func (a *App) Init() tea.Cmd {
 	a.startThemeWatchers()
 	return tea.Batch(
-		a.loadFeeds(),
+		a.loadFeedsWithSession(),
+		a.openStartView(),
 		tea.EnterAltScreen,
 		a.waitThemeChange(),
+		a.refreshOnStart(),
 	)
 }
 
+// refreshOnStart starts the same refresh as ctrl+r when
+// feed.refresh_on_start is set, and is nil otherwise.
+func (a *App) refreshOnStart() tea.Cmd {
+	if !a.config.Feed.RefreshOnStart {
+		return nil
+	}
+	a.setStatus(MsgRefreshing, 0)
+	return tea.Batch(a.startSpinner(MsgRefreshing), a.refreshFeeds())
+}
+
 // startThemeWatchers spawns SIGUSR1 and (on macOS) plist-based theme
 // observers. Both write to a.themeEvents. Cancelling the context shuts
 // them down via Close.
@@ -413,15 +616,19 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		// Captured before the viewport takes the new size.
+		scroll := a.viewport.ScrollPercent()
+		widthChanged := msg.Width != a.width
 		a.width = msg.Width
 		a.height = msg.Height
-		a.feedList.SetSize(msg.Width, msg.Height-listViewChrome)
-		a.articleList.SetSize(msg.Width, msg.Height-listViewChrome)
+		a.feedList.SetSize(msg.Width, max(msg.Height-listViewChrome, 0))
+		a.articleList.SetSize(msg.Width, max(msg.Height-listViewChrome, 0))
 		searchListHeight := max(msg.Height-searchViewChrome, minSearchListHeight)
 		a.searchList.SetSize(msg.Width, searchListHeight)
-		a.mediaList.SetSize(msg.Width, msg.Height-viewportChrome)
+		a.mediaList.SetSize(msg.Width, max(msg.Height-viewportChrome, 0))
+		a.tagList.SetSize(msg.Width, max(msg.Height-viewportChrome, 0))
 		a.viewport.Width = msg.Width
-		a.viewport.Height = msg.Height - viewportChrome
+		a.viewport.Height = max(msg.Height-viewportChrome, 0)
 
 		inputWidth := msg.Width - 4
 		if inputWidth < 20 {
@@ -429,33 +636,38 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		a.textInput.Width = inputWidth
 
+		if widthChanged && a.readerNeedsReflow() {
+			a.reflowPending = true
+			a.reflowScroll = scroll
+			cmds = append(cmds, a.renderArticle(a.currentArticle))
+		}
+
 	case tea.KeyMsg:
 		return a.keyHandler.HandleKey(msg)
 
 	case feedsLoadedMsg:
 		a.feeds = msg.feeds
-		items := make([]list.Item, len(msg.feeds))
-		for i, f := range msg.feeds {
-			items[i] = feedItem{feed: f}
+		a.feedList.SetItems(a.feedItems(msg.feeds))
+		if msg.session != nil {
+			a.restoreFeedSelection(msg.session)
+		}
+		if msg.selectID != "" {
+			a.selectFeed(msg.selectID)
 		}
-		a.feedList.SetItems(items)
 
 	case articlesLoadedMsg:
-		if a.view == ViewArticles {
+		if a.view == ViewArticles || a.view == ViewAllUnread {
 			if msg.appendPage {
+				// Rebuilt whole, since the visit divider may fall in
+				// the new page.
 				a.articles = append(a.articles, msg.articles...)
-				items := a.articleList.Items()
-				for _, art := range msg.articles {
-					items = append(items, articleItem{article: art, maxDescLen: a.config.UI.Article.MaxDescriptionLength})
-				}
-				a.articleList.SetItems(items)
+				a.articleList.SetItems(a.articleItems(a.articles))
 			} else {
 				a.articles = msg.articles
-				items := make([]list.Item, len(msg.articles))
-				for i, art := range msg.articles {
-					items[i] = articleItem{article: art, maxDescLen: a.config.UI.Article.MaxDescriptionLength}
+				a.articleList.SetItems(a.articleItems(msg.articles))
+				if a.view == ViewArticles {
+					a.restoreArticleSelection()
 				}
-				a.articleList.SetItems(items)
 			}
 			a.articlesCursor = msg.cursor
 			a.articlesHasMore = msg.hasMore
@@ -464,14 +676,14 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case articleReadToggledMsg:
 		if msg.err != nil {
-			a.err = msg.err
+			a.showErr(msg.err)
 		} else if msg.article != nil {
 			msg.article.Read = msg.read
 		}
 
 	case articleStarToggledMsg:
 		if msg.err != nil {
-			a.err = msg.err
+			a.showErr(msg.err)
 		} else if msg.article != nil {
 			msg.article.Starred = msg.starred
 		}
@@ -485,18 +697,43 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// reading.
 		isInitialLoad := a.loadingArticle
 		yOffset := a.viewport.YOffset
+		// New content invalidates any find over the old.
+		a.find = readerFind{}
+		a.findInput.Blur()
+		if msg.cacheKey.articleID != "" {
+			a.renderCache.put(renderEntry{key: msg.cacheKey, content: msg.content, readMinutes: msg.readMinutes})
+		}
+		// Centering is for this window width only, so the cache keeps
+		// the rendering without it.
+		if a.config.UI.Article.MaxRenderWidth > 0 {
+			msg.content = centerLines(msg.content, a.width)
+		}
+		a.readerContent = msg.content
 		a.viewport.SetContent(msg.content)
-		if isInitialLoad {
+		a.readMinutes = msg.readMinutes
+		switch {
+		case isInitialLoad:
 			a.viewport.GotoTop()
-		} else {
+		case a.reflowPending:
+			// Rewrapped lines move the old offset; keep the same place
+			// in the article instead.
+			span := max(a.viewport.TotalLineCount()-a.viewport.Height, 0)
+			a.viewport.SetYOffset(int(math.Round(a.reflowScroll * float64(span))))
+		default:
 			a.viewport.SetYOffset(yOffset)
 		}
+		a.reflowPending = false
 		a.loadingArticle = false
 		a.stopSpinner()
 
 	case feedAddedMsg:
 		if msg.err != nil {
-			a.err = msg.err
+			a.showErr(msg.err)
+		} else if msg.existing != nil {
+			// Already subscribed: show the feed rather than fetch it again.
+			a.view = ViewFeeds
+			a.selectFeed(msg.existing.ID)
+			a.setStatusWithKind(MsgAlreadySubscribed(msg.existing.Title), StatusWarn, 0)
 		} else {
 			a.view = ViewFeeds
 			a.setStatusWithKind(MsgAddedFeed(msg.title, msg.added), StatusSuccess, 0)
@@ -505,7 +742,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case feedRenamedMsg:
 		if msg.err != nil {
-			a.err = msg.err
+			a.showErr(msg.err)
 		} else {
 			a.view = ViewFeeds
 			a.feedToRename = nil
@@ -516,7 +753,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case feedDeletedMsg:
 		if msg.err != nil {
-			a.err = msg.err
+			a.showErr(msg.err)
 		} else {
 			a.view = ViewFeeds
 			a.setStatusWithKind(MsgFeedDeleted, StatusSuccess, 0)
@@ -525,6 +762,12 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, cmd
 		}
 
+	case refreshProgressMsg:
+		if a.spinnerActive {
+			a.spinnerLabel = MsgRefreshProgress(msg.done, msg.total)
+		}
+		cmds = append(cmds, msg.next)
+
 	case refreshDoneMsg:
 		// Show a concise summary in the status bar
 		a.setStatus(MsgRefreshSummary(msg.updatedFeeds, msg.addedArticles, msg.errors, msg.docCount), 0)
@@ -548,6 +791,17 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case searchHistoryLoadedMsg:
+		a.searchHistory = msg.queries
+
+	case savedForLaterMsg:
+		a.stopSpinner()
+		if msg.err != nil {
+			a.setStatusWithKind(MsgSaveForLaterFailed(msg.err), StatusError, 0)
+		} else {
+			a.setStatusWithKind(MsgSavedForLater, StatusSuccess, 0)
+		}
+
 	case searchDebounceFireMsg:
 		// Only fire if this is the latest scheduled search
 		if msg.seq == a.searchSeq {
@@ -562,8 +816,17 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case markReadDueMsg:
+		// Only the latest open counts, and only while it is still on screen.
+		if msg.seq == a.markReadSeq && a.view == ViewReader && a.currentArticle == msg.article {
+			cmds = append(cmds, a.markArticleRead(msg.article))
+		}
+
+	case openPreviewMsg:
+		a.setStatus(MsgWouldRun(msg.command), 0)
+
 	case errorMsg:
-		a.err = msg.err
+		a.showErr(msg.err)
 		// Clear loading flag if we were loading an article
 		if a.loadingArticle {
 			a.loadingArticle = false
@@ -589,7 +852,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		newListModel, cmd := a.feedList.Update(msg)
 		a.feedList = newListModel
 		cmds = append(cmds, cmd)
-	case ViewArticles:
+	case ViewArticles, ViewAllUnread:
 		newListModel, cmd := a.articleList.Update(msg)
 		a.articleList = newListModel
 		cmds = append(cmds, cmd)
@@ -617,12 +880,22 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		newListModel, cmd := a.mediaList.Update(msg)
 		a.mediaList = newListModel
 		cmds = append(cmds, cmd)
+	case ViewTags:
+		newListModel, cmd := a.tagList.Update(msg)
+		a.tagList = newListModel
+		cmds = append(cmds, cmd)
 	}
 
 	return a, tea.Batch(cmds...)
 }
 
 func (a *App) View() string {
+	if a.terminalTooSmall() {
+		// Place, unlike renderCentered, never wraps, so the notice stays
+		// readable even when it is wider than the window.
+		return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, renderMuted(MsgTerminalTooSmall(a.width, a.height)))
+	}
+
 	var content string
 
 	switch a.view {
@@ -645,6 +918,9 @@ func (a *App) View() string {
 		}
 		header := renderHeader("› articles", subtitle, a.width)
 		content = lipgloss.JoinVertical(lipgloss.Top, header, a.articleList.View())
+	case ViewAllUnread:
+		header := renderHeader("› unread", MsgUnreadInbox(len(a.articles)), a.width)
+		content = lipgloss.JoinVertical(lipgloss.Top, header, a.articleList.View())
 	case ViewReader:
 		if a.loadingArticle {
 			content = renderCentered(a.width, a.height-3, renderMuted(MsgLoadingArticle))
@@ -686,13 +962,7 @@ func (a *App) View() string {
 		)
 		content = renderCentered(a.width, a.height-3, body)
 	case ViewDeleteConfirm:
-		feedName := "Unknown Feed"
-		if a.feedToDelete != nil {
-			feedName = a.feedToDelete.Title
-			if feedName == "" {
-				feedName = a.feedToDelete.URL
-			}
-		}
+		name := feedName(a.feedToDelete)
 
 		modalWidth := (a.width * 4) / 5
 		if modalWidth < MinNarrowWidth {
@@ -702,7 +972,7 @@ func (a *App) View() string {
 			}
 		}
 
-		feedName = truncateForModal(feedName, modalWidth)
+		name = truncateForModal(name, modalWidth)
 
 		header := renderHeader("› delete feed", "This action cannot be undone", a.width)
 		body := lipgloss.JoinVertical(
@@ -711,7 +981,7 @@ func (a *App) View() string {
 			"",
 			renderModalQuestion("Delete this feed?", modalWidth),
 			"",
-			renderModalHighlight(feedName, modalWidth),
+			renderModalHighlight(name, modalWidth),
 			"",
 			renderModalInfo(renderMuted("This removes all articles."), modalWidth),
 			"",
@@ -762,6 +1032,11 @@ func (a *App) View() string {
 		content = ContentWrapper(a.width, a.height-3).Render(searchContent)
 	case ViewMedia:
 		content = a.mediaList.View()
+	case ViewTags:
+		content = a.tagList.View()
+	}
+	if a.help.ShowAll {
+		content = a.renderHelpOverlay()
 	}
 
 	customStatus := a.getCustomStatusBar()
@@ -771,6 +1046,16 @@ func (a *App) View() string {
 	return lipgloss.JoinVertical(lipgloss.Top, content, separator, customStatus)
 }
 
+// terminalTooSmall reports whether the last known window size is below
+// what the layouts can fit. Before the first WindowSizeMsg the size is
+// unknown and the normal views render.
+func (a *App) terminalTooSmall() bool {
+	if a.width <= 0 && a.height <= 0 {
+		return false
+	}
+	return a.width < MinTerminalWidth || a.height < MinTerminalHeight
+}
+
 func (a *App) getCustomStatusBar() string {
 	// Highest priority: any error
 	if a.err != nil {
@@ -781,6 +1066,13 @@ func (a *App) getCustomStatusBar() string {
 			Render(errorMsg)
 	}
 
+	// Next: the reader's find input, while it is being typed into
+	if a.view == ViewReader && a.findInput.Focused() {
+		return StatusBarStyleWithPadding().
+			Width(a.width).
+			Render(a.findInput.View())
+	}
+
 	// Next: spinner for ongoing operations (refresh, loading article)
 	if a.spinnerActive {
 		left := a.statusSpinner.View()
@@ -788,7 +1080,12 @@ func (a *App) getCustomStatusBar() string {
 		if label == "" {
 			label = "Working…"
 		}
-		st := a.statusStyle(a.spinnerKind)
+		kind := a.spinnerKind
+		if a.quitPending {
+			label = MsgQuitConfirm(a.keyHandler.quitKey())
+			kind = StatusWarn
+		}
+		st := a.statusStyle(kind)
 		msg := st.Render(left + " " + label)
 		return StatusBarStyleWithPadding().
 			Width(a.width).
@@ -796,7 +1093,7 @@ func (a *App) getCustomStatusBar() string {
 	}
 
 	// Next: transient status message
-	if a.statusText != "" && time.Now().Before(a.statusUntil) {
+	if a.statusText != "" && (a.statusSticky || time.Now().Before(a.statusUntil)) {
 		st := a.statusStyle(a.statusKind)
 		statusMsg := st.Render(a.statusText)
 		return StatusBarStyleWithPadding().
@@ -805,6 +1102,16 @@ func (a *App) getCustomStatusBar() string {
 	}
 
 	commands := a.keyHandler.GetHelpForCurrentView()
+	if a.view == ViewReader && !a.loadingArticle {
+		progress := MsgReaderProgress(int(a.viewport.ScrollPercent()*100), a.readMinutes)
+		commands = append([]string{progress}, commands...)
+	}
+	if (a.view == ViewArticles || a.view == ViewAllUnread) && a.tagFilter != "" {
+		commands = append([]string{MsgTagFilter(a.tagFilter)}, commands...)
+	}
+	if a.view == ViewFeeds && a.feedTagFilter != "" {
+		commands = append([]string{MsgTagFilter(a.feedTagFilter)}, commands...)
+	}
 	commandText := strings.Join(commands, " • ")
 	if commandText == "" {
 		commandText = " " // ensure status bar always renders a line
@@ -823,10 +1130,11 @@ func (a *App) setStatus(text string, d time.Duration) {
 func (a *App) setStatusWithKind(text string, kind StatusKind, d time.Duration) {
 	a.statusText = text
 	a.statusKind = kind
-	// Cap duration to DefaultStatusDuration by default and as a maximum
-	maxDuration := DefaultStatusDuration
-	if d <= 0 || d > maxDuration {
-		d = maxDuration
+	// Warnings and errors stay up until the next key press; everything
+	// else lasts d, or ui.status_timeout_ms when the caller passes 0.
+	a.statusSticky = kind == StatusWarn || kind == StatusError
+	if d <= 0 {
+		d = a.statusTimeout
 	}
 	a.statusUntil = time.Now().Add(d)
 }
@@ -840,6 +1148,7 @@ func (a *App) startSpinner(label string) tea.Cmd {
 func (a *App) stopSpinner() {
 	a.spinnerActive = false
 	a.spinnerLabel = ""
+	a.quitPending = false
 }
 
 // startSpinnerWithKind starts spinner with a severity kind.
@@ -885,13 +1194,45 @@ type feedItem struct {
 }
 
 func (i feedItem) Title() string {
+	title := i.feed.Title
+	if icon := feedIcon(i.feed); icon != "" {
+		title = icon + " " + title
+	}
+	if i.feed.Pinned {
+		title = "📌 " + title
+	}
 	if i.feed.LastError != "" {
-		return i.feed.Title + " " + StatusErrorStyle.Render("✗ fetch failed")
+		return title + " " + StatusErrorStyle.Render("✗ fetch failed")
+	}
+	return title
+}
+
+// feedIcon returns the feed's own icon, or failing that the muted first
+// letter of its domain so long lists stay scannable.
+func feedIcon(f *storage.Feed) string {
+	if f.Icon != "" {
+		return f.Icon
+	}
+	u, err := url.Parse(f.URL)
+	if err != nil {
+		return ""
 	}
-	return i.feed.Title
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	r, _ := utf8.DecodeRuneInString(host)
+	if r == utf8.RuneError || !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+		return ""
+	}
+	return renderMuted(string(unicode.ToUpper(r)))
 }
 
 func (i feedItem) Description() string {
+	if i.feed.LastError == "" && len(i.feed.Tags) > 0 {
+		tags := renderMuted("#" + strings.Join(i.feed.Tags, " #"))
+		if i.feed.Description == "" {
+			return tags
+		}
+		return tags + " " + i.feed.Description
+	}
 	if i.feed.LastError == "" {
 		return i.feed.Description
 	}
@@ -903,11 +1244,117 @@ func (i feedItem) Description() string {
 	return ErrorMessageStyle.Render(line)
 }
 
+// compactDetail keeps the feed's tags when the description line is not
+// shown. A failed fetch is already flagged in the title.
+func (i feedItem) compactDetail() string {
+	if len(i.feed.Tags) == 0 {
+		return ""
+	}
+	return renderMuted("#" + strings.Join(i.feed.Tags, " #"))
+}
+
 func (i feedItem) FilterValue() string { return i.feed.Title }
 
 type articleItem struct {
 	article    *storage.Article
 	maxDescLen int
+	// mediaBadge marks articles carrying video, audio, or images; empty
+	// when the article has no recognizable media.
+	mediaBadge string
+	// timeLayout formats the published time; empty means listTimeLayout.
+	timeLayout string
+	// feed is named before the description when set; only the unread
+	// view, which spans feeds, sets it.
+	feed *storage.Feed
+}
+
+// listTimeLayout is the article list's timestamp format unless
+// ui.time_format overrides it.
+const listTimeLayout = "Jan 2, 15:04"
+
+func (a *App) newArticleItem(art *storage.Article) articleItem {
+	item := articleItem{
+		article:    art,
+		maxDescLen: a.config.UI.Article.MaxDescriptionLength,
+		mediaBadge: mediaBadge(art.MediaURLs(), a.icons),
+		timeLayout: a.config.UI.TimeLayout(listTimeLayout),
+	}
+	// The unread view mixes feeds, so each item names its own.
+	if a.view == ViewAllUnread {
+		for _, f := range a.feeds {
+			if f.ID == art.FeedID {
+				item.feed = f
+				break
+			}
+		}
+	}
+	return item
+}
+
+// mediaDetector classifies media URLs for list badges. Building one parses
+// the embedded type tables, so it is shared rather than made per item.
+var mediaDetector = sync.OnceValue(func() *media.TypeDetector {
+	d, err := media.NewTypeDetector()
+	if err != nil {
+		debuglog.Warnf("media type detector unavailable: %v", err)
+		return nil
+	}
+	return d
+})
+
+// mediaBadge picks one glyph for the most prominent kind of media in urls:
+// video, then audio, then images. The icon set's glyph is used when it
+// has one, an emoji otherwise.
+func mediaBadge(urls []string, icons IconSet) string {
+	d := mediaDetector()
+	if d == nil || len(urls) == 0 {
+		return ""
+	}
+	found := map[media.Type]bool{}
+	for _, u := range urls {
+		found[d.DetectType(u)] = true
+	}
+	glyph := func(icon, fallback string) string {
+		if icon != "" {
+			return icon
+		}
+		return fallback
+	}
+	switch {
+	case found[media.TypeVideo]:
+		return glyph(icons.Video, "🎬")
+	case found[media.TypeAudio]:
+		return glyph(icons.Audio, "🎵")
+	case found[media.TypeImage]:
+		return glyph(icons.Image, "🖼")
+	}
+	return ""
+}
+
+// feedName is how lists and dialogs name a feed: its title, or its URL
+// when it has none.
+func feedName(feed *storage.Feed) string {
+	switch {
+	case feed == nil:
+		return "Unknown Feed"
+	case feed.Title != "":
+		return feed.Title
+	default:
+		return feed.URL
+	}
+}
+
+// articleRowDescription is the text of an article row's second line: the
+// description cut to limit runes, led by the name of the article's feed
+// when showFeed is set. Lists that span feeds, the unread inbox and
+// search results, set it so every row says where it came from the same
+// way.
+func articleRowDescription(article *storage.Article, feed *storage.Feed, showFeed bool, limit int) string {
+	desc := truncateEnd(article.Description, limit)
+	if !showFeed {
+		return desc
+	}
+	return feedName(feed) + " • " + desc
 }
 
 func (i articleItem) Title() string {
@@ -922,21 +1369,50 @@ func (i articleItem) Title() string {
 }
 
 func (i articleItem) Description() string {
-	desc := i.article.Description
 	limit := i.maxDescLen
 	if limit <= 0 {
 		limit = defaultMaxDescriptionLength
 	}
-	if len(desc) > limit {
-		desc = desc[:limit] + "…"
-	}
+	desc := articleRowDescription(i.article, i.feed, i.feed != nil, limit)
 
 	timeStr := ""
-	if !i.article.Published.IsZero() {
-		timeStr = TimeStyle.Render(" • " + i.article.Published.Format("Jan 2, 15:04"))
+	if published := i.published(); published != "" {
+		timeStr = TimeStyle.Render(" • " + published)
 	}
 
-	return renderMuted(desc) + timeStr
+	return withIcon(i.mediaBadge, renderMuted(desc)) + timeStr
+}
+
+// published is the article's publish time in the list's layout, or ""
+// when it has none.
+func (i articleItem) published() string {
+	if i.article.Published.IsZero() {
+		return ""
+	}
+	layout := i.timeLayout
+	if layout == "" {
+		layout = listTimeLayout
+	}
+	return i.article.Published.Format(layout)
+}
+
+// compactDetail keeps the media badge, the feed name in the unread view
+// and the publish time when the description line is not shown.
+func (i articleItem) compactDetail() string {
+	detail := ""
+	if i.feed != nil {
+		detail = renderMuted(feedName(i.feed))
+	}
+	if published := i.published(); published != "" {
+		if detail != "" {
+			detail += TimeStyle.Render(" • ")
+		}
+		detail += TimeStyle.Render(published)
+	}
+	if i.mediaBadge == "" {
+		return detail
+	}
+	return strings.TrimSpace(i.mediaBadge + " " + detail)
 }
 
 func (i articleItem) FilterValue() string { return i.article.Title }
@@ -946,19 +1422,26 @@ type searchResultItem struct {
 	article   *storage.Article
 	icons     *IconSet
 	isArticle bool
+	// grouped rows sit under their feed's row, so articles are indented
+	// and leave the feed name out of their description.
+	grouped bool
 }
 
 func (i searchResultItem) Title() string {
 	icons := i.iconSet()
 	if i.isArticle {
+		indent := ""
+		if i.grouped {
+			indent = "  "
+		}
 		if i.article.Read {
-			return ReadItemStyle.Render(withIcon(icons.Article, i.article.Title))
+			return indent + ReadItemStyle.Render(withIcon(icons.Article, i.article.Title))
 		}
 		marker := icons.Article
 		if marker == "" {
 			marker = icons.Unread
 		}
-		return UnreadItemStyle.Render(withIcon(marker, i.article.Title))
+		return indent + UnreadItemStyle.Render(withIcon(marker, i.article.Title))
 	}
 
 	return FeedTitleStyle.Render(withIcon(icons.Feed, i.feed.Title))
@@ -973,26 +1456,14 @@ func (i searchResultItem) iconSet() IconSet {
 
 func (i searchResultItem) Description() string {
 	if i.isArticle {
-		desc := i.article.Description
-		if len(desc) > searchResultDescLength {
-			desc = desc[:searchResultDescLength] + "…"
-		}
-
-		// Show which feed this article belongs to
-		feedName := "Unknown Feed"
-		if i.feed != nil {
-			feedName = i.feed.Title
-			if feedName == "" {
-				feedName = i.feed.URL
-			}
-		}
-
-		timeStr := ""
+		desc := articleRowDescription(i.article, i.feed, !i.grouped, searchResultDescLength)
 		if !i.article.Published.IsZero() {
-			timeStr = i.article.Published.Format("Jan 2")
+			desc += " • " + i.article.Published.Format("Jan 2")
 		}
-
-		return renderMuted(desc + " • from " + feedName + " • " + timeStr)
+		if i.grouped {
+			return "  " + renderMuted(desc)
+		}
+		return renderMuted(desc)
 	}
 
 	url := truncateMiddle(i.feed.URL, 80)
@@ -1010,12 +1481,16 @@ type mediaItem struct {
 	url       string
 	icons     *IconSet
 	mediaType media.Type
-	index     int
-	total     int
+	// mime and length are the enclosure's declared type and size in
+	// bytes, empty when the feed didn't give them.
+	mime   string
+	length int64
+	index  int
+	total  int
 	// isArticle marks the synthetic "open article" entry that
 	// openMediaList prepends so users can still reach the parent
 	// article's canonical URL even when the article carries multiple
-	// media URLs. The entry is not part of currentArticle.MediaURLs.
+	// media URLs. The entry is not part of currentArticle.Media.
 	isArticle bool
 }
 
@@ -1044,9 +1519,29 @@ func (i mediaItem) Title() string {
 }
 
 func (i mediaItem) Description() string {
-	// Show truncated URL
-	url := truncateMiddle(i.url, 80)
-	return url
+	// Show truncated URL, followed by the declared type and size if known
+	parts := []string{truncateMiddle(i.url, 80)}
+	if i.mime != "" {
+		parts = append(parts, i.mime)
+	}
+	if i.length > 0 {
+		parts = append(parts, formatBytes(i.length))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "23.4 MiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
 }
 
 func (i mediaItem) FilterValue() string {
@@ -1055,6 +1550,11 @@ func (i mediaItem) FilterValue() string {
 
 type feedsLoadedMsg struct {
 	feeds []*storage.Feed
+	// session is the persisted selection, set only on the startup load.
+	session *sessionState
+	// selectID, when set, keeps the cursor on that feed after a reload
+	// that may have moved it.
+	selectID string
 }
 
 type articlesLoadedMsg struct {
@@ -1086,13 +1586,26 @@ type articleStarToggledMsg struct {
 }
 
 type articleRenderedMsg struct {
-	content string
+	content     string
+	readMinutes int
+	// cacheKey is set on successful glamour renders, which Update then
+	// keeps in the render cache.
+	cacheKey renderKey
 }
 
 type feedAddedMsg struct {
 	err   error
 	added int
 	title string
+	// existing is set instead of err when the feed was already
+	// subscribed.
+	existing *storage.Feed
+}
+
+// openPreviewMsg carries the command line a --print-open launch would
+// have run.
+type openPreviewMsg struct {
+	command string
 }
 
 type errorMsg struct {
@@ -1111,6 +1624,14 @@ type feedRenamedMsg struct {
 	err error
 }
 
+// refreshProgressMsg reports how many feeds have finished refreshing.
+// next reads the following refresh event and must be re-issued.
+type refreshProgressMsg struct {
+	done  int
+	total int
+	next  tea.Cmd
+}
+
 // refreshDoneMsg summarizes a refresh operation outcome
 type refreshDoneMsg struct {
 	updatedFeeds  int
@@ -1119,7 +1640,26 @@ type refreshDoneMsg struct {
 	docCount      int
 }
 
+// markReadDueMsg fires once an opened article has been in the reader for
+// the mark-read delay.
+type markReadDueMsg struct {
+	article *storage.Article
+	seq     int
+}
+
 // searchDebounceFireMsg is emitted after a short delay to trigger a debounced search.
 type searchDebounceFireMsg struct {
 	seq int
 }
+
+// savedForLaterMsg reports how sending an article to the read-it-later
+// service went.
+type savedForLaterMsg struct {
+	err error
+}
+
+// searchHistoryLoadedMsg carries the recorded search queries, newest
+// first.
+type searchHistoryLoadedMsg struct {
+	queries []string
+}
@@ -1,21 +1,92 @@
 package tui
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/feed"
+	"github.com/pders01/fwrd/internal/search"
 	"github.com/pders01/fwrd/internal/storage"
 )
 
+// newTestStore returns an empty store of the test's own, closed with it.
+func newTestStore(t *testing.T) *storage.Store {
+	t.Helper()
+	store, err := storage.NewTempStore()
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestClose_RemovesMemoryStoreIndex(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	require.NotEmpty(t, app.tempIndex)
+	_, err := os.Stat(app.tempIndex)
+	require.NoError(t, err, "the index should exist while the app runs")
+
+	app.Close()
+	_, err = os.Stat(app.tempIndex)
+	assert.True(t, os.IsNotExist(err), "the index should go with the app")
+}
+
+func TestOpenFeed_DividesArticlesNewSinceLastVisit(t *testing.T) {
+	store := newTestStore(t)
+	visited := time.Now().Add(-time.Hour)
+	f := &storage.Feed{ID: "f", URL: "https://example.com/feed", LastVisited: visited}
+	require.NoError(t, store.SaveFeed(f))
+	var arts []*storage.Article
+	for i, fetched := range []time.Duration{-time.Minute, -2 * time.Minute, -2 * time.Hour, -3 * time.Hour} {
+		arts = append(arts, &storage.Article{
+			ID:        fmt.Sprintf("f:%d", i),
+			FeedID:    "f",
+			Title:     fmt.Sprintf("a%d", i),
+			Published: time.Now().Add(fetched),
+			FetchedAt: time.Now().Add(fetched),
+		})
+	}
+	require.NoError(t, store.SaveArticles(arts))
+
+	app := NewApp(store, config.TestConfig())
+	for _, cmd := range app.openFeed(f)().(tea.BatchMsg) {
+		if cmd == nil {
+			continue
+		}
+		if msg := cmd(); msg != nil {
+			app.Update(msg)
+		}
+	}
+
+	items := app.articleList.Items()
+	require.Len(t, items, 5)
+	assert.Equal(t, visitDividerItem{newAbove: true}, items[2], "the divider goes between the two new articles and the old ones")
+	assert.Equal(t, visited.UnixNano(), app.visitCutoff.UnixNano())
+	saved, err := store.GetFeed("f")
+	require.NoError(t, err)
+	assert.True(t, saved.LastVisited.After(visited), "opening the feed records the visit")
+
+	// Oldest first, the new articles come last, below the divider.
+	assert.Equal(t, visitDividerItem{newAbove: false},
+		withVisitDivider([]list.Item{items[4], items[3], items[1], items[0]}, visited)[2])
+	// A first visit has nothing to divide.
+	assert.Len(t, withVisitDivider(items[:2], time.Time{}), 2)
+}
+
 func TestViewStateTransitions(t *testing.T) {
 	cfg := config.TestConfig()
-	store := &storage.Store{}
+	store := newTestStore(t)
 
 	tests := []struct {
 		name         string
@@ -121,10 +192,10 @@ func TestViewStateTransitions(t *testing.T) {
 			expectedView: ViewReader,
 			setupFunc: func(a *App) {
 				a.articles = []*storage.Article{{
-					ID:        "test-article",
-					Title:     "Test Article",
-					Content:   "Test content",
-					MediaURLs: []string{"http://example.com/video1.mp4", "http://example.com/video2.mp4"},
+					ID:      "test-article",
+					Title:   "Test Article",
+					Content: "Test content",
+					Media:   []storage.Media{{URL: "http://example.com/video1.mp4"}, {URL: "http://example.com/video2.mp4"}},
 				}}
 				a.articleList.SetItems([]list.Item{articleItem{article: a.articles[0]}})
 			},
@@ -145,9 +216,9 @@ func TestViewStateTransitions(t *testing.T) {
 			expectedView: ViewMedia,
 			setupFunc: func(a *App) {
 				a.currentArticle = &storage.Article{
-					ID:        "test-article",
-					Title:     "Test Article",
-					MediaURLs: []string{"http://example.com/video1.mp4", "http://example.com/video2.mp4"},
+					ID:    "test-article",
+					Title: "Test Article",
+					Media: []storage.Media{{URL: "http://example.com/video1.mp4"}, {URL: "http://example.com/video2.mp4"}},
 				}
 			},
 		},
@@ -178,7 +249,7 @@ func TestViewStateTransitions(t *testing.T) {
 
 func TestNavigationBoundaries(t *testing.T) {
 	cfg := config.TestConfig()
-	store := &storage.Store{}
+	store := newTestStore(t)
 	app := NewApp(store, cfg)
 
 	t.Run("Feed navigation wrapping", func(t *testing.T) {
@@ -230,7 +301,7 @@ func TestNavigationBoundaries(t *testing.T) {
 
 func TestArticleStateManagement(t *testing.T) {
 	cfg := config.TestConfig()
-	store := &storage.Store{}
+	store := newTestStore(t)
 	app := NewApp(store, cfg)
 
 	t.Run("Mark article as read on reader view", func(t *testing.T) {
@@ -274,7 +345,7 @@ func TestArticleStateManagement(t *testing.T) {
 
 func TestSearchFunctionality(t *testing.T) {
 	cfg := config.TestConfig()
-	store := &storage.Store{}
+	store := newTestStore(t)
 	app := NewApp(store, cfg)
 
 	t.Run("Enter search mode", func(t *testing.T) {
@@ -323,9 +394,161 @@ func TestSearchFunctionality(t *testing.T) {
 	})
 }
 
+func TestSearchHistory_UpAndDownRecallQueries(t *testing.T) {
+	store := newTestStore(t)
+	require.NoError(t, store.AddSearchHistory("older", 5))
+	require.NoError(t, store.AddSearchHistory("newest", 5))
+	app := NewApp(store, config.TestConfig())
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	require.NotNil(t, cmd, "entering search should load the history")
+	app.Update(cmd())
+	require.Equal(t, []string{"newest", "older"}, app.searchHistory)
+
+	press := func(k tea.KeyType) {
+		app.Update(tea.KeyMsg{Type: k})
+	}
+	press(tea.KeyUp)
+	assert.Equal(t, "newest", app.searchInput.Value())
+	press(tea.KeyUp)
+	assert.Equal(t, "older", app.searchInput.Value())
+	press(tea.KeyUp)
+	assert.Equal(t, "older", app.searchInput.Value(), "up stops at the oldest query")
+	assert.Equal(t, "older", app.pendingSearchQuery, "a recalled query is searched for")
+	press(tea.KeyDown)
+	assert.Equal(t, "newest", app.searchInput.Value())
+	press(tea.KeyDown)
+	assert.Equal(t, "", app.searchInput.Value(), "down past the newest query empties the input")
+
+	app.searchInput.SetValue("go")
+	press(tea.KeyUp)
+	assert.Equal(t, "go", app.searchInput.Value(), "up in a typed query is not history")
+}
+
+func TestSearchHistory_SelectingAResultRecordsTheQuery(t *testing.T) {
+	store := newTestStore(t)
+	app := NewApp(store, config.TestConfig())
+	app.view = ViewSearch
+	app.searchInput.SetValue("  golang ")
+	_, cmd := app.keyHandler.selectSearchResult(searchResultItem{
+		article:   &storage.Article{ID: "a1", Title: "Result"},
+		isArticle: true,
+		feed:      &storage.Feed{ID: "f1"},
+	})
+	for _, c := range cmd().(tea.BatchMsg) {
+		if c != nil {
+			c()
+		}
+	}
+
+	history, err := store.SearchHistory()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"golang"}, history)
+}
+
+// fixedSearcher answers every global search with the same results.
+type fixedSearcher struct {
+	search.Searcher
+	results []*search.Result
+}
+
+func (s fixedSearcher) Search(string, int) ([]*search.Result, error) {
+	return s.results, nil
+}
+
+func TestPerformSearch_GroupByFeed(t *testing.T) {
+	feedA := &storage.Feed{ID: "fa", Title: "Go Blog"}
+	feedB := &storage.Feed{ID: "fb", Title: "Rust Blog"}
+	results := []*search.Result{
+		{Feed: feedA, Article: &storage.Article{ID: "a1", FeedID: "fa", Title: "Go 1.24"}, IsArticle: true, Score: 9},
+		{Feed: feedB, Article: &storage.Article{ID: "b1", FeedID: "fb", Title: "Rust 1.80"}, IsArticle: true, Score: 8},
+		{Feed: feedA, Article: &storage.Article{ID: "a2", FeedID: "fa", Title: "go 1.24!"}, IsArticle: true, Score: 7},
+		{Feed: feedA, Article: &storage.Article{ID: "a3", FeedID: "fa", Title: "Generics"}, IsArticle: true, Score: 6},
+	}
+	ids := func(msg tea.Msg) []string {
+		var out []string
+		for _, r := range msg.(searchResultsMsg).results {
+			if r.isArticle {
+				out = append(out, r.article.ID)
+			} else {
+				out = append(out, r.feed.ID)
+			}
+		}
+		return out
+	}
+
+	cfg := config.TestConfig()
+	app := NewApp(newTestStore(t), cfg)
+	app.searchEngine = fixedSearcher{results: results}
+	assert.Equal(t, []string{"a1", "b1", "a2", "a3"}, ids(app.performSearch("go")()))
+
+	cfg.Search.GroupByFeed = true
+	msg := app.performSearch("go")()
+	assert.Equal(t, []string{"fa", "a1", "a3", "fb", "b1"}, ids(msg))
+	first := msg.(searchResultsMsg).results[1]
+	assert.True(t, first.grouped)
+	assert.NotContains(t, first.Description(), "Go Blog")
+}
+
+func TestRefreshOnStart(t *testing.T) {
+	cfg := config.TestConfig()
+	app := NewApp(newTestStore(t), cfg)
+	assert.Nil(t, app.refreshOnStart())
+	assert.False(t, app.spinnerActive)
+
+	cfg.Feed.RefreshOnStart = true
+	assert.NotNil(t, app.refreshOnStart())
+	assert.True(t, app.spinnerActive)
+	assert.Equal(t, MsgRefreshing, app.spinnerLabel)
+}
+
+// recordingReadLater remembers saved URLs and fails with err.
+type recordingReadLater struct {
+	saved []string
+	err   error
+}
+
+func (r *recordingReadLater) SaveForLater(url string) error {
+	r.saved = append(r.saved, url)
+	return r.err
+}
+
+func TestKeyHandler_SaveForLater(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.view = ViewReader
+	app.currentArticle = &storage.Article{ID: "a1", URL: "https://example.com/a"}
+	press := func() tea.Cmd {
+		_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+		return cmd
+	}
+
+	assert.Nil(t, press())
+	assert.Equal(t, MsgNoReadLater, app.statusText)
+
+	rl := &recordingReadLater{}
+	app.readLater = rl
+	cmd := press()
+	require.NotNil(t, cmd)
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(savedForLaterMsg); ok {
+			app.Update(msg)
+		}
+	}
+	assert.Equal(t, []string{"https://example.com/a"}, rl.saved)
+	assert.Equal(t, MsgSavedForLater, app.statusText)
+
+	rl.err = errors.New("server said no")
+	for _, c := range press()().(tea.BatchMsg) {
+		if msg, ok := c().(savedForLaterMsg); ok {
+			app.Update(msg)
+		}
+	}
+	assert.Equal(t, MsgSaveForLaterFailed(rl.err), app.statusText)
+}
+
 func TestKeyboardShortcuts(t *testing.T) {
 	cfg := config.TestConfig()
-	store := &storage.Store{}
+	store := newTestStore(t)
 
 	tests := []struct {
 		name     string
@@ -385,7 +608,7 @@ func TestKeyboardShortcuts(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Skip tests for features that no longer exist
-			if tt.name == "Help toggle on '?'" || tt.name == "Mark all as read on 'X'" {
+			if tt.name == "Mark all as read on 'X'" {
 				t.Skip("Feature not implemented in current version")
 			}
 			app := NewApp(store, cfg)
@@ -402,6 +625,16 @@ func TestFeedItem_FetchErrorBadge(t *testing.T) {
 		assert.Equal(t, "desc", i.Description())
 	})
 
+	t.Run("icon prefixes the title", func(t *testing.T) {
+		i := feedItem{feed: &storage.Feed{Title: "Example", Icon: "🦀", URL: "https://example.com/feed"}}
+		assert.Equal(t, "🦀 Example", i.Title())
+	})
+
+	t.Run("domain letter stands in for a missing icon", func(t *testing.T) {
+		i := feedItem{feed: &storage.Feed{Title: "Example", URL: "https://www.example.com/feed"}}
+		assert.Equal(t, renderMuted("E")+" Example", i.Title())
+	})
+
 	t.Run("error marks the title", func(t *testing.T) {
 		i := feedItem{feed: &storage.Feed{Title: "Example", LastError: "dial tcp: timeout"}}
 		assert.Contains(t, i.Title(), "Example")
@@ -420,3 +653,516 @@ func TestFeedItem_FetchErrorBadge(t *testing.T) {
 		assert.NotContains(t, desc, "desc")
 	})
 }
+
+func TestArticleRowDescription(t *testing.T) {
+	article := &storage.Article{Description: "A long description of the post"}
+	feed := &storage.Feed{Title: "Blog", URL: "https://blog.example/feed"}
+
+	assert.Equal(t, "A long description of the post", articleRowDescription(article, feed, false, 100))
+	assert.Equal(t, "A long…", articleRowDescription(article, feed, false, 7))
+	assert.Equal(t, "Blog • A long…", articleRowDescription(article, feed, true, 7), "the feed name is not counted against the limit")
+	assert.Equal(t, "https://blog.example/feed • A long…", articleRowDescription(article, &storage.Feed{URL: feed.URL}, true, 7), "untitled feeds go by URL")
+	assert.Equal(t, "Unknown Feed • A long…", articleRowDescription(article, nil, true, 7))
+
+	// The unread inbox and search results attribute articles the same way.
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.feeds = []*storage.Feed{feed}
+	article.FeedID = feed.ID
+	app.view = ViewAllUnread
+	inbox := app.newArticleItem(article).Description()
+	result := searchResultItem{article: article, feed: feed, isArticle: true}.Description()
+	assert.Contains(t, inbox, "Blog • ")
+	assert.Contains(t, result, "Blog • ")
+
+	app.view = ViewArticles
+	assert.NotContains(t, app.newArticleItem(article).Description(), "Blog")
+}
+
+func TestRefreshProgressMsg_UpdatesSpinnerAndResubscribes(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.startSpinner(MsgRefreshing)
+
+	next := func() tea.Msg { return nil }
+	_, cmd := app.Update(refreshProgressMsg{done: 3, total: 8, next: next})
+
+	assert.Equal(t, "Refreshing 3/8…", app.spinnerLabel)
+	assert.NotNil(t, cmd, "progress must re-issue the next-event command")
+
+	app.Update(refreshDoneMsg{updatedFeeds: 8, docCount: -1})
+	assert.False(t, app.spinnerActive)
+}
+
+func TestSessionRestore_SelectsSavedFeedAndArticle(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	feeds := []*storage.Feed{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+
+	app.Update(feedsLoadedMsg{feeds: feeds, session: &sessionState{feedID: "c", articleID: "c-2"}})
+	require.Equal(t, 2, app.feedList.Index())
+
+	app.view = ViewArticles
+	app.currentFeed = feeds[2]
+	arts := []*storage.Article{{ID: "c-1"}, {ID: "c-2"}, {ID: "c-3"}}
+	app.Update(articlesLoadedMsg{articles: arts})
+	assert.Equal(t, 1, app.articleList.Index())
+
+	// The restore is one-shot: a reload lands back at the top.
+	app.articleList.Select(0)
+	app.Update(articlesLoadedMsg{articles: arts})
+	assert.Equal(t, 0, app.articleList.Index())
+}
+
+func TestSessionRestore_DisabledSkipsPersistence(t *testing.T) {
+	cfg := config.TestConfig()
+	cfg.UI.RestoreSession = false
+	app := NewApp(newTestStore(t), cfg)
+
+	assert.Nil(t, app.saveSession("feed", "article"))
+}
+
+func TestFeedAdded_AlreadySubscribedSelectsFeed(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	feeds := []*storage.Feed{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}}
+	app.Update(feedsLoadedMsg{feeds: feeds})
+	app.view = ViewAddFeed
+
+	app.Update(feedAddedMsg{existing: feeds[1]})
+	assert.Equal(t, ViewFeeds, app.view)
+	assert.Equal(t, 1, app.feedList.Index())
+	assert.Equal(t, MsgAlreadySubscribed("B"), app.statusText)
+	assert.Nil(t, app.err)
+}
+
+func TestFeedAdded_EmptyFeedIsAWarning(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.view = ViewAddFeed
+
+	app.Update(feedAddedMsg{err: wrapErr("add feed", feed.ErrEmptyFeed)})
+	assert.Equal(t, MsgEmptyFeed, app.statusText)
+	assert.Equal(t, StatusWarn, app.statusKind)
+	assert.Nil(t, app.err)
+}
+
+func TestMaxRenderWidth_NarrowsAndCentersReader(t *testing.T) {
+	cfg := config.TestConfig()
+	app := NewApp(newTestStore(t), cfg)
+	app.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+	assert.Equal(t, MaxReadableWidth, app.readerWrapWidth())
+
+	cfg.UI.Article.MaxRenderWidth = 60
+	assert.Equal(t, 60, app.readerWrapWidth())
+
+	key := renderKey{articleID: "a1", width: 60}
+	app.Update(articleRenderedMsg{content: "first line\n\nsecond", cacheKey: key})
+	lines := strings.Split(app.readerContent, "\n")
+	indent := strings.Repeat(" ", (200-len("first line"))/2)
+	assert.Equal(t, indent+"first line", lines[0])
+	assert.Empty(t, lines[1], "blank lines stay blank")
+	assert.Equal(t, indent+"second", lines[2])
+
+	cached, ok := app.renderCache.get(key)
+	require.True(t, ok)
+	assert.Equal(t, "first line\n\nsecond", cached.content, "the cache keeps the uncentered rendering")
+}
+
+func TestStartView(t *testing.T) {
+	cfg := config.TestConfig()
+	cfg.UI.StartView = "unread"
+	app := NewApp(newTestStore(t), cfg)
+	assert.NotNil(t, app.openStartView())
+	assert.Equal(t, ViewAllUnread, app.view)
+
+	cfg.UI.StartView = "feeds"
+	app = NewApp(newTestStore(t), cfg)
+	assert.Nil(t, app.openStartView())
+	assert.Equal(t, ViewFeeds, app.view)
+}
+
+func TestStartView_LastReopensViewAtQuit(t *testing.T) {
+	store, err := storage.NewStore(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	cfg := config.TestConfig()
+	cfg.UI.StartView = "last"
+
+	// Nothing saved yet: the feed list.
+	app := NewApp(store, cfg)
+	assert.Nil(t, app.openStartView())
+	assert.Equal(t, ViewFeeds, app.view)
+
+	// Quitting from an article opened in the unread inbox counts as the inbox.
+	app.view = ViewReader
+	app.readerOrigin = ViewAllUnread
+	app.quit()
+	app = NewApp(store, cfg)
+	assert.NotNil(t, app.openStartView())
+	assert.Equal(t, ViewAllUnread, app.view)
+
+	app.view = ViewFeeds
+	app.quit()
+	app = NewApp(store, cfg)
+	assert.Nil(t, app.openStartView())
+	assert.Equal(t, ViewFeeds, app.view)
+}
+
+func TestReaderStatusBar_ShowsProgressAndReadingTime(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.width, app.height = 80, 24
+	app.viewport.Width, app.viewport.Height = 80, 10
+	app.view = ViewReader
+
+	lines := make([]string, 40)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	app.Update(articleRenderedMsg{content: strings.Join(lines, "\n"), readMinutes: 3})
+
+	assert.Contains(t, app.getCustomStatusBar(), MsgReaderProgress(0, 3))
+
+	app.viewport.GotoBottom()
+	assert.Contains(t, app.getCustomStatusBar(), MsgReaderProgress(100, 3))
+}
+
+func TestSetStatus_UsesConfiguredTimeoutAndKeepsWarnings(t *testing.T) {
+	cfg := config.TestConfig()
+	cfg.UI.StatusTimeoutMs = 3000
+	app := NewApp(newTestStore(t), cfg)
+	app.width = 80
+
+	app.setStatus("Marked 42 as read", 0)
+	assert.WithinDuration(t, time.Now().Add(3*time.Second), app.statusUntil, time.Second)
+
+	// Callers may ask for longer than the default.
+	app.setStatusWithKind("Added", StatusSuccess, 10*time.Second)
+	assert.WithinDuration(t, time.Now().Add(10*time.Second), app.statusUntil, time.Second)
+
+	app.setStatusWithKind("Feed failed", StatusWarn, 0)
+	app.statusUntil = time.Now().Add(-time.Minute)
+	assert.Contains(t, app.getCustomStatusBar(), "Feed failed", "warnings outlive the timeout")
+
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.NotContains(t, app.getCustomStatusBar(), "Feed failed", "a key press clears the warning")
+}
+
+func TestView_TinyTerminalShowsFallback(t *testing.T) {
+	views := []View{ViewFeeds, ViewArticles, ViewReader, ViewAddFeed, ViewDeleteConfirm, ViewRenameFeed, ViewSearch, ViewMedia, ViewAllUnread}
+
+	for _, size := range []tea.WindowSizeMsg{{Width: 12, Height: 4}, {Width: 1, Height: 1}, {Width: 80, Height: 3}} {
+		app := NewApp(newTestStore(t), config.TestConfig())
+		app.feeds = []*storage.Feed{{ID: "f", Title: "A feed with a fairly long title"}}
+		app.feedList.SetItems([]list.Item{feedItem{feed: app.feeds[0]}})
+		app.Update(size)
+		for _, v := range views {
+			app.view = v
+			var out string
+			assert.NotPanics(t, func() { out = app.View() }, "view %d at %dx%d", v, size.Width, size.Height)
+			assert.Contains(t, out, "Terminal too small")
+		}
+	}
+
+	// At the minimum size every view renders normally.
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.Update(tea.WindowSizeMsg{Width: MinTerminalWidth, Height: MinTerminalHeight})
+	for _, v := range views {
+		app.view = v
+		var out string
+		assert.NotPanics(t, func() { out = app.View() })
+		assert.NotContains(t, out, "Terminal too small")
+	}
+}
+
+func TestCompactList_OneLinePerItem(t *testing.T) {
+	articles := []*storage.Article{
+		{ID: "a1", FeedID: "f1", Title: "First", Description: "first description",
+			Published: time.Date(2025, 3, 4, 5, 6, 0, 0, time.UTC)},
+		{ID: "a2", FeedID: "f1", Title: "Second", Description: "second description", Read: true},
+	}
+	render := func(compact bool, view View) string {
+		cfg := config.TestConfig()
+		cfg.UI.CompactList = compact
+		app := NewApp(newTestStore(t), cfg)
+		app.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+		app.feeds = []*storage.Feed{{ID: "f1", Title: "Some Blog"}}
+		app.view = view
+		app.Update(articlesLoadedMsg{articles: articles})
+		return app.articleList.View()
+	}
+
+	normal := render(false, ViewArticles)
+	assert.Contains(t, normal, "first description")
+
+	compact := render(true, ViewArticles)
+	assert.NotContains(t, compact, "first description")
+	assert.Contains(t, compact, "● First", "compact rows keep the unread marker")
+	lines := strings.Split(compact, "\n")
+	var first, second int
+	for i, l := range lines {
+		switch {
+		case strings.Contains(l, "First"):
+			first = i
+		case strings.Contains(l, "Second"):
+			second = i
+		}
+	}
+	assert.Equal(t, 1, second-first, "items sit on consecutive lines")
+	assert.Contains(t, ansi.Strip(lines[first]), "Mar 4, 05:06", "compact rows keep the time")
+
+	for _, l := range strings.Split(render(true, ViewAllUnread), "\n") {
+		if strings.Contains(l, "First") {
+			assert.Contains(t, ansi.Strip(l), "Some Blog", "unread rows name their feed")
+		}
+	}
+}
+
+func TestReadingMinutes(t *testing.T) {
+	assert.Equal(t, 0, readingMinutes(""))
+	assert.Equal(t, 1, readingMinutes("just a few words"))
+	assert.Equal(t, 1, readingMinutes(strings.Repeat("word ", wordsPerMinute)))
+	assert.Equal(t, 2, readingMinutes(strings.Repeat("word ", wordsPerMinute+1)))
+}
+
+func TestReaderRawToggle(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.width, app.height = 80, 24
+	app.view = ViewReader
+	app.currentArticle = &storage.Article{
+		ID:      "a1",
+		Title:   "Tables",
+		Content: "<table><tr><td>cell</td></tr></table>\x1b[31m",
+	}
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	require.NotNil(t, cmd)
+	assert.True(t, app.readerRawMode)
+	assert.Equal(t, MsgRawView, app.statusText)
+
+	msg, ok := cmd().(articleRenderedMsg)
+	require.True(t, ok)
+	assert.Contains(t, msg.content, "<table><tr><td>cell</td></tr></table>")
+	assert.NotContains(t, msg.content, "\x1b[31m", "control sequences must be stripped")
+
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	assert.False(t, app.readerRawMode)
+	assert.Equal(t, MsgRendered, app.statusText)
+}
+
+func TestMaybeLoadMoreArticles_PagesWhileTagFilterListIsShort(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.view = ViewArticles
+	app.currentFeed = &storage.Feed{ID: "f1"}
+	app.articleList.SetSize(80, 20)
+	for i := range 30 {
+		art := &storage.Article{ID: fmt.Sprintf("a%d", i), FeedID: "f1"}
+		if i%2 == 0 {
+			art.Tags = []string{"go"}
+		}
+		app.articles = append(app.articles, art)
+	}
+	app.articlesHasMore, app.articlesCursor = true, "a29"
+
+	app.articleList.SetItems(app.articleItems(app.articles))
+	assert.Nil(t, app.maybeLoadMoreArticles(), "unfiltered, the top of the list is far from the end")
+
+	app.setTagFilter("go")
+	require.Len(t, app.articleList.Items(), 15)
+	assert.NotNil(t, app.maybeLoadMoreArticles(), "15 tagged articles do not fill the screen")
+
+	app.articlesLoadingMore = false
+	app.setTagFilter("rust")
+	require.Empty(t, app.articleList.Items())
+	assert.NotNil(t, app.maybeLoadMoreArticles(), "no loaded article has the tag yet")
+}
+
+func TestReaderRawMode_ResetWhenOpeningFromSearch(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.view = ViewSearch
+	app.readerRawMode = true // left on from the previous article
+
+	app.keyHandler.selectSearchResult(searchResultItem{
+		article:   &storage.Article{ID: "a1", Title: "Result"},
+		isArticle: true,
+		feed:      &storage.Feed{ID: "f1"},
+	})
+	assert.Equal(t, ViewReader, app.view)
+	assert.False(t, app.readerRawMode)
+}
+
+func TestRenderArticle_ReusesCachedRendering(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.width, app.height = 100, 24
+	article := &storage.Article{ID: "a1", Title: "Cached", Content: "Some body text."}
+
+	msg, ok := app.renderArticle(article)().(articleRenderedMsg)
+	require.True(t, ok)
+	app.Update(msg)
+
+	// A stand-in rendering proves the next open is served from the cache.
+	app.renderCache.put(renderEntry{key: msg.cacheKey, content: "from cache"})
+	cached, ok := app.renderArticle(article)().(articleRenderedMsg)
+	require.True(t, ok)
+	assert.Equal(t, "from cache", cached.content)
+
+	// Resizing within the tolerance keeps the renderer, and the cache.
+	app.width = 105
+	cached = app.renderArticle(article)().(articleRenderedMsg)
+	assert.Equal(t, "from cache", cached.content)
+
+	// A bigger resize rebuilds the renderer and renders afresh.
+	app.width = 60
+	fresh := app.renderArticle(article)().(articleRenderedMsg)
+	assert.Contains(t, fresh.content, "Cached")
+}
+
+func TestResize_KeepsReaderScrollPosition(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	var body strings.Builder
+	for i := range 80 {
+		fmt.Fprintf(&body, "Paragraph %d has enough words in it to wrap once the window gets narrow enough.\n\n", i)
+	}
+	article := &storage.Article{ID: "a1", Title: "Long", Content: body.String()}
+	app.currentArticle = article
+	app.view = ViewReader
+	app.loadingArticle = true
+	app.Update(app.renderArticle(article)())
+
+	span := app.viewport.TotalLineCount() - app.viewport.Height
+	app.viewport.SetYOffset(span / 2)
+	before := app.viewport.ScrollPercent()
+
+	_, cmd := app.Update(tea.WindowSizeMsg{Width: 60, Height: 30})
+	require.NotNil(t, cmd, "a width change past the tolerance renders again")
+	msg, ok := cmd().(articleRenderedMsg)
+	require.True(t, ok)
+	app.Update(msg)
+
+	assert.Greater(t, app.viewport.TotalLineCount()-app.viewport.Height, span, "the narrower reader wraps to more lines")
+	assert.InDelta(t, before, app.viewport.ScrollPercent(), 0.02)
+
+	// Within the tolerance the rendering, and the offset, stay as they are.
+	offset := app.viewport.YOffset
+	_, cmd = app.Update(tea.WindowSizeMsg{Width: 62, Height: 30})
+	assert.Nil(t, cmd)
+	assert.Equal(t, offset, app.viewport.YOffset)
+}
+
+func TestMarkReadDelay(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+	article := &storage.Article{ID: "a1", FeedID: "f1", Title: "Slow read"}
+	require.NoError(t, store.SaveArticles([]*storage.Article{article}))
+
+	cfg := config.TestConfig()
+	cfg.UI.MarkReadDelayMs = 1
+	app := NewApp(store, cfg)
+	isRead := func() bool {
+		stored, err := store.GetArticle("a1")
+		require.NoError(t, err)
+		return stored.Read
+	}
+
+	// Left before the delay ran out: stays unread.
+	app.view, app.currentArticle = ViewReader, article
+	due := app.markReadOnOpen(article)()
+	app.view = ViewArticles
+	_, cmd := app.Update(due)
+	assert.Nil(t, cmd)
+	assert.False(t, isRead())
+
+	// Reopened: the first open's mark is stale, the new one lands.
+	app.view = ViewReader
+	stale := due
+	due = app.markReadOnOpen(article)()
+	_, cmd = app.Update(stale)
+	assert.Nil(t, cmd)
+	_, cmd = app.Update(due)
+	require.NotNil(t, cmd)
+	cmd()
+	assert.True(t, isRead())
+}
+
+func TestArticleItem_MediaBadgeAndDescriptionLimit(t *testing.T) {
+	icons := NewIconSet("unicode")
+	assert.Equal(t, "🎬", mediaBadge([]string{"https://example.com/a.jpg", "https://example.com/b.mp4"}, icons))
+	assert.Equal(t, "🎵", mediaBadge([]string{"https://example.com/ep.mp3"}, icons))
+	assert.Equal(t, "🖼", mediaBadge([]string{"https://example.com/a.png"}, icons))
+	assert.Empty(t, mediaBadge([]string{"https://example.com/page"}, icons))
+	assert.Empty(t, mediaBadge(nil, icons))
+
+	cfg := config.TestConfig()
+	cfg.UI.Article.MaxDescriptionLength = 10
+	app := NewApp(newTestStore(t), cfg)
+	item := app.newArticleItem(&storage.Article{
+		Title:       "Episode",
+		Description: "äöü long description that goes on",
+		Media:       []storage.Media{{URL: "https://example.com/ep.mp3"}},
+	})
+	desc := item.Description()
+	assert.Contains(t, desc, "🎵")
+	assert.Contains(t, desc, "äöü long …")
+	assert.NotContains(t, desc, "description")
+}
+
+func TestMediaItemDescription_ShowsTypeAndSize(t *testing.T) {
+	item := mediaItem{url: "https://example.com/ep.mp3", mime: "audio/mpeg", length: 24 << 20}
+	assert.Equal(t, "https://example.com/ep.mp3 · audio/mpeg · 24.0 MiB", item.Description())
+
+	bare := mediaItem{url: "https://example.com/cover.jpg"}
+	assert.Equal(t, "https://example.com/cover.jpg", bare.Description())
+}
+
+func TestSearchIndexPath_PrefersConfiguredIndex(t *testing.T) {
+	cfg := config.TestConfig()
+	cfg.Database.Path = filepath.Join(os.TempDir(), "fwrd-test.db")
+
+	cfg.Database.SearchIndex = filepath.Join(os.TempDir(), "custom", "search.bleve")
+	got, err := searchIndexPath(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Database.SearchIndex, got)
+
+	// A rejected path is an error, not a silent switch to a derived one.
+	cfg.Database.SearchIndex = "/etc/fwrd/../search.bleve"
+	_, err = searchIndexPath(cfg)
+	assert.Error(t, err)
+
+	cfg.Database.SearchIndex = ""
+	got, err = searchIndexPath(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(os.TempDir(), "fwrd-test.bleve"), got)
+}
+
+type closingSearcher struct {
+	search.Searcher
+	closed int
+}
+
+func (s *closingSearcher) Close() error {
+	s.closed++
+	if c, ok := s.Searcher.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func TestAppClose_ClosesSearchEngine(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	engine := &closingSearcher{Searcher: app.searchEngine}
+	app.searchEngine = engine
+
+	app.Close()
+	app.Close()
+	assert.Equal(t, 1, engine.closed, "search engine should be closed exactly once")
+}
+
+func TestArticleItem_TimeFormat(t *testing.T) {
+	published := time.Date(2025, 3, 7, 18, 5, 0, 0, time.Local)
+	art := &storage.Article{Title: "Dated", Published: published}
+
+	app := NewApp(newTestStore(t), config.TestConfig())
+	assert.Contains(t, app.newArticleItem(art).Description(), "Mar 7, 18:05")
+
+	cfg := config.TestConfig()
+	cfg.UI.TimeFormat = "2006-01-02 15:04"
+	app = NewApp(newTestStore(t), cfg)
+	assert.Contains(t, app.newArticleItem(art).Description(), "2025-03-07 18:05")
+}
@@ -89,6 +89,7 @@ var (
 	StatusWarnStyle     lipgloss.Style
 	StatusErrorStyle    lipgloss.Style
 	FeedTitleStyle      lipgloss.Style
+	FindMatchStyle      lipgloss.Style
 	EmptyStyle          lipgloss.Style
 )
 
@@ -133,6 +134,7 @@ func applyPalette(dark bool) {
 	StatusWarnStyle = lipgloss.NewStyle().Foreground(UnreadColor)
 	StatusErrorStyle = lipgloss.NewStyle().Foreground(ErrorColor).Bold(true)
 	FeedTitleStyle = lipgloss.NewStyle().Foreground(SecondaryColor).Bold(true)
+	FindMatchStyle = lipgloss.NewStyle().Foreground(BackgroundColor).Background(UnreadColor).Bold(true)
 	EmptyStyle = lipgloss.NewStyle()
 }
 
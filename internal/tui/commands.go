@@ -1,12 +1,17 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pders01/fwrd/internal/config"
 	"github.com/pders01/fwrd/internal/debuglog"
+	"github.com/pders01/fwrd/internal/feed"
 	"github.com/pders01/fwrd/internal/search"
 	"github.com/pders01/fwrd/internal/storage"
 )
@@ -21,6 +26,37 @@ func (a *App) loadFeeds() tea.Cmd {
 	}
 }
 
+// openFeed shows feed's article list and records the visit. The visit it
+// replaces becomes the cutoff for the list's new-articles divider.
+func (a *App) openFeed(feed *storage.Feed) tea.Cmd {
+	a.currentFeed = feed
+	a.view = ViewArticles
+	a.visitCutoff = feed.LastVisited
+	feed.LastVisited = time.Now()
+	id, at := feed.ID, feed.LastVisited
+	return tea.Batch(a.loadArticles(feed.ID), a.saveSession(feed.ID, ""), func() tea.Msg {
+		if err := a.store.MarkFeedVisited(id, at); err != nil {
+			debuglog.Warnf("saving feed visit: %v", err)
+		}
+		return nil
+	})
+}
+
+// openArticle shows article in the reader, rendered rather than raw, marks
+// it read and records it as the session's place.
+func (a *App) openArticle(article *storage.Article) tea.Cmd {
+	a.currentArticle = article
+	a.loadingArticle = true
+	a.readerRawMode = false
+	a.view = ViewReader
+	return tea.Batch(
+		a.startSpinner(MsgLoadingArticle),
+		a.markReadOnOpen(article),
+		a.renderArticle(article),
+		a.saveSession(article.FeedID, article.ID),
+	)
+}
+
 func (a *App) loadArticles(feedID string) tea.Cmd {
 	return a.loadArticlesPage(feedID, "", false)
 }
@@ -37,9 +73,10 @@ func (a *App) loadMoreArticles(feedID, cursor string) tea.Cmd {
 // articlesLoadedMsg whose fields tell the Update handler whether to
 // replace or append, and where the next cursor sits.
 func (a *App) loadArticlesPage(feedID, cursor string, appendPage bool) tea.Cmd {
+	oldestFirst := a.currentFeed != nil && a.currentFeed.ID == feedID && a.currentFeed.OldestFirst()
 	return func() tea.Msg {
-		limit := pickPositive(a.config.UI.Article.ListLimit, DefaultArticleLimit)
-		articles, err := a.store.GetArticlesWithCursor(feedID, limit, cursor)
+		limit := pickPositive(a.config.UI.Article.ListLimit, config.DefaultArticleListLimit)
+		articles, err := a.store.GetArticlesSorted(feedID, limit, cursor, oldestFirst)
 		if err != nil {
 			return errorMsg{err: wrapErr("load articles", err)}
 		}
@@ -58,6 +95,23 @@ func (a *App) loadArticlesPage(feedID, cursor string, appendPage bool) tea.Cmd {
 	}
 }
 
+// unreadInboxLimit caps how many articles the unread view loads. Finding
+// unread articles means decoding them off the date index, so the view
+// loads one bounded batch instead of paging.
+const unreadInboxLimit = 500
+
+// loadUnreadArticles fills the article list with unread articles from
+// every feed, newest first.
+func (a *App) loadUnreadArticles() tea.Cmd {
+	return func() tea.Msg {
+		articles, err := a.store.GetUnreadArticles(unreadInboxLimit)
+		if err != nil {
+			return errorMsg{err: wrapErr("load unread articles", err)}
+		}
+		return articlesLoadedMsg{articles: articles}
+	}
+}
+
 // articleListPrefetchMargin is how many items from the bottom of the
 // article list will trigger a prefetch of the next page. A small margin
 // keeps memory bounded; a non-zero value avoids the user noticing the
@@ -68,15 +122,20 @@ const articleListPrefetchMargin = 10
 // user has scrolled near the end of the loaded article list. Returns
 // nil when no fetch is needed (no current feed, no remaining pages, an
 // in-flight request, or the selection is far from the end).
+//
+// A tag filter may list only a few of the loaded articles, leaving the
+// user nothing to scroll through, so while one is set pages keep coming
+// until the filtered list fills the screen.
 func (a *App) maybeLoadMoreArticles() tea.Cmd {
 	if !a.articlesHasMore || a.articlesLoadingMore || a.currentFeed == nil || a.articlesCursor == "" {
 		return nil
 	}
 	items := a.articleList.Items()
-	if len(items) == 0 {
+	short := a.tagFilter != "" && len(items) < a.articleList.Height()
+	if !short && len(items) == 0 {
 		return nil
 	}
-	if a.articleList.Index() < len(items)-articleListPrefetchMargin {
+	if !short && a.articleList.Index() < len(items)-articleListPrefetchMargin {
 		return nil
 	}
 	a.articlesLoadingMore = true
@@ -103,28 +162,45 @@ func sanitizeAndLimitContent(content string, maxSize int) string {
 }
 
 func (a *App) renderArticle(article *storage.Article) tea.Cmd {
+	if a.readerRawMode {
+		return a.renderRawArticle(article)
+	}
 	// Resolve the renderer on the calling goroutine (Bubble Tea's
 	// main goroutine, since renderArticle runs from Update). The
 	// returned closure runs in a tea.Cmd goroutine and must not touch
 	// App fields concurrently with Update — capturing r and rerr by
 	// value avoids a race against tea.WindowSizeMsg handling.
 	r, rerr := a.getRenderer()
+	key := renderKey{articleID: article.ID, updated: article.Updated, width: a.rendererWidth, style: a.glamourStyle}
+	if rerr == nil {
+		if e, ok := a.renderCache.get(key); ok {
+			return func() tea.Msg {
+				return articleRenderedMsg{content: e.content, readMinutes: e.readMinutes}
+			}
+		}
+	}
+	timeLayout := a.config.UI.TimeLayout(time.RFC1123)
+	imageMode := a.config.UI.Article.ImageMode
+	imageGlyph := a.icons.Image
 	return func() tea.Msg {
 		var content strings.Builder
 
 		// Apply size limits for security and performance
 		safeTitle := sanitizeAndLimitContent(article.Title, maxTitleSize)
 		content.WriteString(fmt.Sprintf("# %s\n\n", safeTitle))
-		content.WriteString(fmt.Sprintf("*Published: %s*\n\n", article.Published.Format(time.RFC1123)))
+		content.WriteString(fmt.Sprintf("*Published: %s*\n\n", article.Published.Format(timeLayout)))
+		if len(article.Tags) > 0 {
+			content.WriteString(fmt.Sprintf("*Tags: %s*\n\n", strings.Join(article.Tags, ", ")))
+		}
 
 		if article.URL != "" {
 			safeURL := sanitizeAndLimitContent(article.URL, maxURLSize)
 			content.WriteString(fmt.Sprintf("[Read Online](%s)\n\n", safeURL))
 		}
 
-		if len(article.MediaURLs) > 0 {
+		if len(article.Media) > 0 {
 			content.WriteString("**Media:**\n")
-			for _, url := range article.MediaURLs {
+			for _, url := range article.MediaURLs() {
 				safeMediaURL := sanitizeAndLimitContent(url, maxURLSize)
 				content.WriteString(fmt.Sprintf("- %s\n", safeMediaURL))
 			}
@@ -134,13 +210,17 @@ func (a *App) renderArticle(article *storage.Article) tea.Cmd {
 		content.WriteString("---\n\n")
 
 		// Apply content size limits with appropriate maximums
+		var body string
 		if article.Content != "" {
 			safeContent := sanitizeAndLimitContent(article.Content, maxContentSize)
-			content.WriteString(htmlToMarkdown(safeContent))
+			body = htmlToMarkdown(safeContent)
 		} else {
 			safeDescription := sanitizeAndLimitContent(article.Description, maxDescriptionSize)
-			content.WriteString(htmlToMarkdown(safeDescription))
+			body = htmlToMarkdown(safeDescription)
 		}
+		body = rewriteImages(body, imageMode, imageGlyph)
+		content.WriteString(body)
+		minutes := readingMinutes(body)
 
 		if rerr != nil {
 			return articleRenderedMsg{content: "Error initializing renderer: " + rerr.Error()}
@@ -157,10 +237,51 @@ func (a *App) renderArticle(article *storage.Article) tea.Cmd {
 		// dispatched alongside this command from the article-open path.
 		// Duplicating the write here was a relic from before that split.
 
-		return articleRenderedMsg{content: rendered}
+		return articleRenderedMsg{content: rendered, readMinutes: minutes, cacheKey: key}
 	}
 }
 
+// renderRawArticle shows the article body exactly as the feed delivered
+// it, wrapped to the reader width, for when glamour mangles tables or
+// code. Control characters are dropped so markup cannot drive the
+// terminal.
+func (a *App) renderRawArticle(article *storage.Article) tea.Cmd {
+	width := a.readerWrapWidth()
+	return func() tea.Msg {
+		body, limit := article.Content, maxContentSize
+		if body == "" {
+			body, limit = article.Description, maxDescriptionSize
+		}
+		body = stripControlChars(sanitizeAndLimitContent(body, limit))
+		title := stripControlChars(sanitizeAndLimitContent(article.Title, maxTitleSize))
+		text := title + "\n\n" + body
+		return articleRenderedMsg{
+			content:     lipgloss.NewStyle().Width(width).Render(text),
+			readMinutes: readingMinutes(htmlToMarkdown(body)),
+		}
+	}
+}
+
+// stripControlChars removes control characters other than newline and tab.
+func stripControlChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' || !unicode.IsControl(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// wordsPerMinute is the reading speed behind the reader's time estimate.
+const wordsPerMinute = 200
+
+// readingMinutes estimates how long text takes to read, rounding up so
+// any non-empty article reports at least a minute.
+func readingMinutes(text string) int {
+	words := len(strings.Fields(text))
+	return (words + wordsPerMinute - 1) / wordsPerMinute
+}
+
 func (a *App) addFeed(url string) tea.Cmd {
 	return func() tea.Msg {
 		url = strings.TrimSpace(url)
@@ -168,7 +289,11 @@ func (a *App) addFeed(url string) tea.Cmd {
 			url = "https://" + url
 		}
 
-		newFeed, err := a.manager.AddFeed(url)
+		newFeed, err := a.manager.AddFeedContext(a.opCtx, url)
+		var subscribed *feed.AlreadySubscribedError
+		if errors.As(err, &subscribed) {
+			return feedAddedMsg{existing: subscribed.Feed}
+		}
 		if err != nil {
 			return feedAddedMsg{err: wrapErr("add feed", err)}
 		}
@@ -202,24 +327,102 @@ func (a *App) renameFeed(newTitle string) tea.Cmd {
 	}
 }
 
+// refreshFeeds runs the refresh in the background and streams
+// refreshProgressMsg values as feeds complete, ending with a single
+// refreshDoneMsg. Each progress message carries the Cmd that reads the
+// next event, so Update keeps the subscription alive by re-issuing it.
 func (a *App) refreshFeeds() tea.Cmd {
 	return func() tea.Msg {
-		summary, _ := a.manager.RefreshAllFeeds()
+		events := make(chan tea.Msg, 1)
+		next := waitRefreshEvent(events)
+		go a.runRefresh(events, next)
+		return <-events
+	}
+}
 
-		docCount := -1
-		if ds, ok := a.searchEngine.(search.DebugStatser); ok {
-			if n, err := ds.DocCount(); err == nil {
-				docCount = n
-			}
+// runRefresh performs the refresh and publishes its events on events.
+func (a *App) runRefresh(events chan<- tea.Msg, next tea.Cmd) {
+	summary, _ := a.manager.RefreshAllFeedsWithProgress(a.opCtx, func(done, total int) {
+		// Drop intermediate updates the UI hasn't consumed yet; the
+		// next one (or the final summary) supersedes them.
+		select {
+		case events <- refreshProgressMsg{done: done, total: total, next: next}:
+		default:
 		}
+	})
 
-		return refreshDoneMsg{
-			updatedFeeds:  summary.UpdatedFeeds,
-			addedArticles: summary.AddedArticles,
-			errors:        len(summary.Errors),
-			docCount:      docCount,
+	docCount := -1
+	if ds, ok := a.searchEngine.(search.DebugStatser); ok {
+		if n, err := ds.DocCount(); err == nil {
+			docCount = n
 		}
 	}
+
+	events <- refreshDoneMsg{
+		updatedFeeds:  summary.UpdatedFeeds,
+		addedArticles: summary.AddedArticles,
+		errors:        len(summary.Errors),
+		docCount:      docCount,
+	}
+}
+
+// waitRefreshEvent blocks on the next refresh event.
+func waitRefreshEvent(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// togglePinned flips feed's pin and reloads the list, which re-sorts it,
+// keeping the cursor on feed.
+func (a *App) togglePinned(feed *storage.Feed) tea.Cmd {
+	updated := *feed
+	updated.Pinned = !feed.Pinned
+	return func() tea.Msg {
+		if err := a.store.SaveFeed(&updated); err != nil {
+			return errorMsg{err: wrapErr("pin feed", err)}
+		}
+		feeds, err := a.store.GetAllFeeds()
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		return feedsLoadedMsg{feeds: feeds, selectID: feed.ID}
+	}
+}
+
+// toggleReadingOrder flips feed between newest-first and oldest-first,
+// saves the choice and reloads its articles in the new order.
+func (a *App) toggleReadingOrder(feed *storage.Feed) tea.Cmd {
+	if feed.OldestFirst() {
+		feed.ReadingOrder = ""
+		a.setStatus(MsgNewestFirst, 0)
+	} else {
+		feed.ReadingOrder = storage.ReadingOrderOldest
+		a.setStatus(MsgOldestFirst, 0)
+	}
+	updated := *feed
+	reload := a.loadArticles(feed.ID)
+	return func() tea.Msg {
+		if err := a.store.SaveFeed(&updated); err != nil {
+			return errorMsg{err: wrapErr("save reading order", err)}
+		}
+		return reload()
+	}
+}
+
+// saveFeedOrder stores the feed list's current order as the manual sort.
+func (a *App) saveFeedOrder() tea.Cmd {
+	ids := make([]string, len(a.feeds))
+	for i, f := range a.feeds {
+		f.Order = i + 1
+		ids[i] = f.ID
+	}
+	return func() tea.Msg {
+		if err := a.store.SetFeedOrder(ids); err != nil {
+			return errorMsg{err: wrapErr("save feed order", err)}
+		}
+		return nil
+	}
 }
 
 func (a *App) toggleRead(article *storage.Article) tea.Cmd {
@@ -254,6 +457,38 @@ func (a *App) markArticleRead(article *storage.Article) tea.Cmd {
 	}
 }
 
+// markReadOnOpen marks a just-opened article read, right away or, with a
+// mark-read delay configured, once it has stayed open that long.
+func (a *App) markReadOnOpen(article *storage.Article) tea.Cmd {
+	a.markReadSeq++
+	if a.markReadDelay <= 0 {
+		return a.markArticleRead(article)
+	}
+	seq := a.markReadSeq
+	return tea.Tick(a.markReadDelay, func(time.Time) tea.Msg {
+		return markReadDueMsg{article: article, seq: seq}
+	})
+}
+
+// saveForLater sends article's link to the read-it-later service; the
+// outcome comes back as a savedForLaterMsg.
+func (a *App) saveForLater(article *storage.Article) tea.Cmd {
+	switch {
+	case article == nil:
+		return nil
+	case a.readLater == nil:
+		a.setStatusWithKind(MsgNoReadLater, StatusWarn, 0)
+		return nil
+	case article.URL == "":
+		a.setStatusWithKind(MsgNoArticleLink, StatusWarn, 0)
+		return nil
+	}
+	service, link := a.readLater, article.URL
+	return tea.Batch(a.startSpinner(MsgSavingForLater), func() tea.Msg {
+		return savedForLaterMsg{err: service.SaveForLater(link)}
+	})
+}
+
 func (a *App) deleteFeed(feedID string) tea.Cmd {
 	return func() tea.Msg {
 		if err := a.store.DeleteFeed(feedID); err != nil {
@@ -271,6 +506,7 @@ func (a *App) performSearch(query string) tea.Cmd {
 }
 
 func (a *App) performSearchWithContext(query, context string) tea.Cmd {
+	limit := pickPositive(a.config.Search.ResultLimit, config.DefaultSearchResultLimit)
 	return func() tea.Msg {
 		// Use the new intelligent search engine
 		var searchResults []*search.Result
@@ -281,16 +517,21 @@ func (a *App) performSearchWithContext(query, context string) tea.Cmd {
 			searchResults, err = a.searchEngine.SearchInArticle(a.currentArticle, query)
 			// If no results in-article, fall back to global to avoid empty UX
 			if err == nil && len(searchResults) == 0 {
-				searchResults, err = a.searchEngine.Search(query, defaultSearchResultLimit)
+				searchResults, err = a.searchEngine.Search(query, limit)
 			}
 		} else {
-			searchResults, err = a.searchEngine.Search(query, defaultSearchResultLimit)
+			searchResults, err = a.searchEngine.Search(query, limit)
 		}
 
 		if err != nil {
 			return errorMsg{err: err}
 		}
 
+		grouped := a.config.Search.GroupByFeed
+		if grouped {
+			searchResults = search.GroupByFeed(searchResults)
+		}
+
 		// Convert search engine results to UI results
 		var results []searchResultItem
 		for _, sr := range searchResults {
@@ -298,6 +539,7 @@ func (a *App) performSearchWithContext(query, context string) tea.Cmd {
 				feed:      sr.Feed,
 				article:   sr.Article,
 				isArticle: sr.IsArticle,
+				grouped:   grouped,
 				icons:     &a.icons,
 			})
 		}
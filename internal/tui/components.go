@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -39,8 +40,8 @@ func renderInputFrame(inputView string, focused bool, contentWidth int) string {
 //revive:disable-next-line:unused
 func renderCentered(width, height int, content string) string {
 	return lipgloss.NewStyle().
-		Width(width).
-		Height(height).
+		Width(max(width, 0)).
+		Height(max(height, 0)).
 		Align(lipgloss.Center, lipgloss.Center).
 		Render(content)
 }
@@ -75,11 +76,21 @@ func renderModalInfo(text string, width int) string {
 	return renderModalText(&EmptyStyle, text, width)
 }
 
+// newListDelegate returns the item delegate for the feed and article
+// lists: the two-line default, or with compact a compactDelegate that
+// fits each item on one line.
+func newListDelegate(compact bool) list.ItemDelegate {
+	if compact {
+		return newCompactDelegate()
+	}
+	return list.NewDefaultDelegate()
+}
+
 // Width calculation helpers to reduce magic numbers and promote consistency
 
 // getContentWidth calculates width for content, accounting for typical margins/padding.
 func getContentWidth(totalWidth int) int {
-	return totalWidth - 4 // Standard content margin
+	return max(totalWidth-4, 0) // Standard content margin
 }
 
 // getInputWidth calculates width for input fields, accounting for borders and padding.
@@ -88,17 +99,17 @@ func getInputWidth(totalWidth int) int {
 	if inputWidth < MinInputWidth {
 		inputWidth = totalWidth - 4 // Fallback for narrow screens
 	}
-	return inputWidth
+	return max(inputWidth, 0)
 }
 
 // getModalWidth calculates appropriate width for modal dialogs.
 func getModalWidth(totalWidth int) int {
-	return totalWidth - 4 // Standard modal margin
+	return max(totalWidth-4, 0) // Standard modal margin
 }
 
 // getSeparatorWidth calculates width for separator lines.
 func getSeparatorWidth(totalWidth int) int {
-	return totalWidth - 2 // Account for minimal padding
+	return max(totalWidth-2, 0) // Account for minimal padding
 }
 
 // Truncation helpers for consistent text handling
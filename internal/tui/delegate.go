@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"io"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// compactItem is a list item that can say in a few words what its
+// description line would: the parts worth keeping on a one-line row.
+type compactItem interface {
+	list.DefaultItem
+	compactDetail() string
+}
+
+// compactDelegate draws each item on a single line, its title followed
+// by the item's compactDetail, so a screen holds about twice as many.
+// It is the default delegate without descriptions or spacing, with the
+// detail joined onto the title before rendering.
+type compactDelegate struct {
+	list.DefaultDelegate
+}
+
+func newCompactDelegate() compactDelegate {
+	d := list.NewDefaultDelegate()
+	d.ShowDescription = false
+	d.SetSpacing(0)
+	return compactDelegate{DefaultDelegate: d}
+}
+
+// Render shortens a long title rather than the detail after it, so the
+// feed name and time stay readable on narrow screens.
+func (d compactDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if c, ok := item.(compactItem); ok {
+		if detail := c.compactDetail(); detail != "" {
+			s := d.Styles.NormalTitle
+			width := m.Width() - s.GetPaddingLeft() - s.GetPaddingRight() - ansi.StringWidth(detail) - 2
+			title := ansi.Truncate(c.Title(), max(width, 1), "…")
+			item = compactRow{compactItem: c, title: title + "  " + detail}
+		}
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+// compactRow is an item as compactDelegate shows it, detail included.
+type compactRow struct {
+	compactItem
+	title string
+}
+
+func (r compactRow) Title() string { return r.title }
@@ -1,6 +1,12 @@
 package tui
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pders01/fwrd/internal/feed"
+	"github.com/pders01/fwrd/internal/storage"
+)
 
 // wrapErr formats an error with a contextual prefix.
 func wrapErr(context string, err error) error {
@@ -9,3 +15,30 @@ func wrapErr(context string, err error) error {
 	}
 	return fmt.Errorf("%s: %w", context, err)
 }
+
+// notFoundStatus maps storage not-found errors, and a feed that answered
+// with nothing, to a short status line. It reports false for anything
+// else so real DB and fetch failures still surface through the error
+// banner.
+func notFoundStatus(err error) (string, bool) {
+	switch {
+	case errors.Is(err, feed.ErrEmptyFeed):
+		return MsgEmptyFeed, true
+	case errors.Is(err, storage.ErrArticleNotFound):
+		return MsgArticleGone, true
+	case errors.Is(err, storage.ErrFeedNotFound):
+		return MsgFeedGone, true
+	}
+	return "", false
+}
+
+// showErr surfaces err to the user: records that vanished underneath us
+// (e.g. deleted by a concurrent `fwrd feed delete`) and empty feeds get a
+// warning status, everything else lands in the error banner.
+func (a *App) showErr(err error) {
+	if msg, ok := notFoundStatus(err); ok {
+		a.setStatusWithKind(msg, StatusWarn, 0)
+		return
+	}
+	a.err = err
+}
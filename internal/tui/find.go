@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Reader find-in-page keys. Like helpOverlayKey they are bare keys, the
+// ones less and vim use for the same job.
+const (
+	findKey     = "/"
+	findNextKey = "n"
+	findPrevKey = "N"
+)
+
+// readerFind is a find-in-page over the reader's rendered content. Matches
+// are whole lines, so a line holding the term twice is one stop.
+type readerFind struct {
+	query string
+	// lines are the content lines that contain query, top to bottom;
+	// current indexes the highlighted one.
+	lines   []int
+	current int
+}
+
+// openFind focuses the find input in the reader's status bar, seeded
+// with the last query so Enter repeats it.
+func (a *App) openFind() tea.Cmd {
+	a.findInput.SetValue(a.find.query)
+	a.findInput.CursorEnd()
+	return a.findInput.Focus()
+}
+
+// runFind searches the rendered article for query, case-insensitively,
+// and shows the first match at or below the top of the viewport. An
+// empty query clears the find.
+func (a *App) runFind(query string) {
+	a.findInput.Blur()
+	a.clearFind()
+	if query == "" {
+		return
+	}
+	a.find.query = query
+	needle := strings.ToLower(query)
+	for i, line := range strings.Split(a.readerContent, "\n") {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), needle) {
+			a.find.lines = append(a.find.lines, i)
+		}
+	}
+	if len(a.find.lines) == 0 {
+		a.setStatusWithKind(MsgFindNoMatch(query), StatusWarn, 0)
+		return
+	}
+	for i, line := range a.find.lines {
+		if line >= a.viewport.YOffset {
+			a.find.current = i
+			break
+		}
+	}
+	a.showFindMatch()
+}
+
+// stepFind moves delta matches on from the current one, wrapping around
+// either end.
+func (a *App) stepFind(delta int) {
+	n := len(a.find.lines)
+	if n == 0 {
+		return
+	}
+	a.find.current = ((a.find.current+delta)%n + n) % n
+	a.showFindMatch()
+}
+
+// showFindMatch highlights the current match and scrolls it into view,
+// a third of the way down so some context above it stays visible.
+func (a *App) showFindMatch() {
+	lines := strings.Split(a.readerContent, "\n")
+	i := a.find.lines[a.find.current]
+	lines[i] = highlightMatches(ansi.Strip(lines[i]), a.find.query)
+	a.viewport.SetContent(strings.Join(lines, "\n"))
+	if i < a.viewport.YOffset || i >= a.viewport.YOffset+a.viewport.Height {
+		a.viewport.SetYOffset(i - a.viewport.Height/3)
+	}
+	a.setStatus(MsgFindMatch(a.find.current+1, len(a.find.lines), a.find.query), 0)
+}
+
+// clearFind drops the find and its highlight, keeping the scroll offset.
+func (a *App) clearFind() {
+	hadMatch := len(a.find.lines) > 0
+	a.find = readerFind{}
+	if hadMatch {
+		a.viewport.SetContent(a.readerContent)
+	}
+}
+
+// highlightMatches styles every case-insensitive occurrence of query in
+// the plain-text line.
+func highlightMatches(line, query string) string {
+	lower, needle := strings.ToLower(line), strings.ToLower(query)
+	// Lowercasing can change byte lengths outside ASCII; fall back to
+	// highlighting the whole line rather than slicing mid-rune.
+	if len(lower) != len(line) || len(needle) != len(query) {
+		return FindMatchStyle.Render(line)
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, needle)
+		if i < 0 {
+			b.WriteString(line)
+			return b.String()
+		}
+		b.WriteString(line[:i])
+		b.WriteString(FindMatchStyle.Render(line[i : i+len(needle)]))
+		line, lower = line[i+len(needle):], lower[i+len(needle):]
+	}
+}
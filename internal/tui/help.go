@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpOverlayKey opens and closes the key binding overlay. It is a bare
+// key like esc rather than modifier+key, matching the list's own "?".
+const helpOverlayKey = "?"
+
+// helpKeyMap feeds help.Model. Each group becomes one column of the
+// overlay; the keys are read from the config so remapped bindings show
+// up as the user set them.
+type helpKeyMap struct {
+	groups [][]key.Binding
+}
+
+func (m helpKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{key.NewBinding(key.WithKeys(helpOverlayKey), key.WithHelp(helpOverlayKey, "help"))}
+}
+
+func (m helpKeyMap) FullHelp() [][]key.Binding { return m.groups }
+
+func helpBinding(k, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(k), key.WithHelp(k, desc))
+}
+
+// helpKeys lists every action across all views with its current key:
+// the list navigation Charm provides, then the actions of each view as
+// dispatched, so the overlay cannot drift from what the keys do.
+func (kh *KeyHandler) helpKeys() helpKeyMap {
+	nav := []key.Binding{
+		helpBinding("↑/k", "up"),
+		helpBinding("↓/j", "down"),
+		helpBinding("enter", "open"),
+		helpBinding("/", "filter list"),
+	}
+	groups := [][]key.Binding{
+		append(nav, actionBindings(kh.globalActions(ViewFeeds))...),
+		actionBindings(kh.viewActions(ViewFeeds)),
+		actionBindings(kh.viewActions(ViewArticles)),
+	}
+	var rest []key.Binding
+	for _, v := range []View{ViewReader, ViewSearch, ViewMedia} {
+		rest = append(rest, actionBindings(kh.viewActions(v))...)
+	}
+	return helpKeyMap{groups: append(groups, rest)}
+}
+
+func actionBindings(actions []keyAction) []key.Binding {
+	bindings := make([]key.Binding, len(actions))
+	for i, act := range actions {
+		bindings[i] = key.NewBinding(key.WithKeys(act.keys...), key.WithHelp(strings.Join(act.keys, "/"), act.description()))
+	}
+	return bindings
+}
+
+// renderHelpOverlay draws the full key binding list in place of the
+// current view.
+func (a *App) renderHelpOverlay() string {
+	a.help.Width = a.width
+	header := renderHeader("› keys", "Press ? or Esc to close", a.width)
+	body := lipgloss.JoinVertical(
+		lipgloss.Center,
+		header,
+		"",
+		a.help.FullHelpView(a.keyHandler.helpKeys().FullHelp()),
+	)
+	return renderCentered(a.width, a.height-3, body)
+}
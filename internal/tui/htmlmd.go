@@ -2,6 +2,7 @@ package tui
 
 import (
 	"regexp"
+	"strings"
 	"sync"
 
 	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
@@ -54,3 +55,30 @@ func htmlToMarkdown(s string) string {
 	}
 	return md
 }
+
+// markdownImageRe matches a markdown image, ![alt](url) with an optional
+// "title", as htmlToMarkdown emits for <img> and as markdown feeds write.
+var markdownImageRe = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?([^)\s>]*)>?(?:\s+"[^"]*")?\s*\)`)
+
+// rewriteImages replaces the markdown images in md according to mode, the
+// ui.article.image_mode setting: "hide" drops them, "placeholder" keeps the
+// alt text, and "link" (the default, including unknown modes) keeps the alt
+// text and the URL. glamour has no way to draw an image, so without the
+// rewrite they vanish from the reader. glyph, the icon set's Image, leads
+// each rewritten image when set.
+func rewriteImages(md, mode, glyph string) string {
+	return markdownImageRe.ReplaceAllStringFunc(md, func(m string) string {
+		if mode == "hide" {
+			return ""
+		}
+		sub := markdownImageRe.FindStringSubmatch(m)
+		alt := strings.TrimSpace(sub[1])
+		if alt == "" {
+			alt = "image"
+		}
+		if mode == "placeholder" || sub[2] == "" {
+			return withIcon(glyph, alt)
+		}
+		return withIcon(glyph, alt) + " → " + sub[2]
+	})
+}
@@ -111,3 +111,34 @@ func TestHTMLToMarkdown_StripsDangerousMarkup(t *testing.T) {
 		})
 	}
 }
+
+func TestRewriteImages(t *testing.T) {
+	md := "Intro\n\n![A chart](https://example.com/chart.png \"Q3\")\n\n![](https://example.com/x.png) end"
+	cases := []struct {
+		mode string
+		want string
+	}{
+		{"link", "Intro\n\nA chart → https://example.com/chart.png\n\nimage → https://example.com/x.png end"},
+		{"", "Intro\n\nA chart → https://example.com/chart.png\n\nimage → https://example.com/x.png end"},
+		{"placeholder", "Intro\n\nA chart\n\nimage end"},
+		{"hide", "Intro\n\n\n\n end"},
+	}
+	for _, c := range cases {
+		if got := rewriteImages(md, c.mode, unicodeIcons.Image); got != c.want {
+			t.Errorf("rewriteImages(%q) = %q, want %q", c.mode, got, c.want)
+		}
+	}
+	if got := rewriteImages(md, "placeholder", "▣"); got != "Intro\n\n▣ A chart\n\n▣ image end" {
+		t.Errorf("with an Image icon: got %q, want the icon before each alt text", got)
+	}
+	if got := rewriteImages("[a link](https://example.com)", "hide", ""); got != "[a link](https://example.com)" {
+		t.Errorf("plain links must be left alone, got %q", got)
+	}
+}
+
+func TestRewriteImages_FromHTML(t *testing.T) {
+	got := rewriteImages(htmlToMarkdown(`<p>See <img src="https://example.com/a.png" alt="diagram"></p>`), "link", "")
+	if !strings.Contains(got, "diagram → https://example.com/a.png") {
+		t.Errorf("converted <img> not rewritten: %q", got)
+	}
+}
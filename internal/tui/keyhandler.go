@@ -3,11 +3,11 @@ package tui
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/debuglog"
 	"github.com/pders01/fwrd/internal/media"
 	"github.com/pders01/fwrd/internal/search"
 	"github.com/pders01/fwrd/internal/validation"
@@ -34,6 +34,11 @@ func NewKeyHandler(app *App, cfg *config.Config) *KeyHandler {
 
 func (kh *KeyHandler) HandleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
+	kh.app.statusSticky = false
+
+	if kh.app.help.ShowAll {
+		return kh.handleHelpOverlayKey(key)
+	}
 
 	if kh.isInTextInputMode() {
 		return kh.handleTextInputMode(msg)
@@ -54,6 +59,8 @@ func (kh *KeyHandler) isInTextInputMode() bool {
 		return kh.app.textInput.Focused()
 	case ViewSearch:
 		return kh.app.searchInput.Focused()
+	case ViewReader:
+		return kh.app.findInput.Focused()
 	default:
 		return false
 	}
@@ -64,14 +71,22 @@ func (kh *KeyHandler) handleTextInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	switch key {
 	case "esc":
+		if kh.app.view == ViewReader {
+			// Cancel the find input, staying in the article.
+			kh.app.findInput.Blur()
+			return kh.app, nil
+		}
 		return kh.navigateBack()
 	case "ctrl+c":
-		return kh.app, tea.Quit
+		return kh.app, kh.app.quit()
 	case "enter":
 		return kh.handleTextInputEnter()
 	case "tab", "down":
 
 		if kh.app.view == ViewSearch {
+			if key == "down" && kh.app.searchHistoryPos >= 0 {
+				return kh.recallSearch(-1)
+			}
 			if len(kh.app.searchList.Items()) > 0 {
 				kh.app.searchInput.Blur()
 
@@ -83,9 +98,8 @@ func (kh *KeyHandler) handleTextInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return kh.delegateToTextInput(msg)
 	case "up", "shift+tab":
 
-		if kh.app.view == ViewSearch {
-
-			return kh.delegateToTextInput(msg)
+		if kh.app.view == ViewSearch && key == "up" && kh.app.browsingSearchHistory() {
+			return kh.recallSearch(1)
 		}
 
 		return kh.delegateToTextInput(msg)
@@ -117,6 +131,10 @@ func (kh *KeyHandler) handleTextInputEnter() (tea.Model, tea.Cmd) {
 		kh.app.setStatus(MsgRenaming, 0)
 		return kh.app, kh.app.renameFeed(input)
 
+	case ViewReader:
+		kh.app.runFind(strings.TrimSpace(kh.app.findInput.Value()))
+		return kh.app, nil
+
 	case ViewSearch:
 		// Select first search result if available
 		if items := kh.app.searchList.Items(); len(items) > 0 {
@@ -144,6 +162,11 @@ func (kh *KeyHandler) delegateToTextInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		kh.app.textInput = newTextInput
 		return kh.app, cmd
 
+	case ViewReader:
+		newFindInput, cmd := kh.app.findInput.Update(msg)
+		kh.app.findInput = newFindInput
+		return kh.app, cmd
+
 	case ViewSearch:
 		// Handle search input with debounce scheduling
 		prev := kh.app.searchInput.Value()
@@ -152,11 +175,9 @@ func (kh *KeyHandler) delegateToTextInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 		newVal := kh.sanitizeSearchInput(kh.app.searchInput.Value())
 		if newVal != prev {
-			kh.app.pendingSearchQuery = newVal
-			kh.app.searchSeq++
-			seq := kh.app.searchSeq
-			wait := time.Duration(kh.app.searchDebounceMillis) * time.Millisecond
-			return kh.app, tea.Batch(cmd, tea.Tick(wait, func(time.Time) tea.Msg { return searchDebounceFireMsg{seq: seq} }))
+			// Editing a recalled query makes it a new one.
+			kh.app.searchHistoryPos = -1
+			return kh.app, tea.Batch(cmd, kh.scheduleSearch(newVal))
 		}
 		return kh.app, cmd
 
@@ -165,133 +186,108 @@ func (kh *KeyHandler) delegateToTextInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
-// handleCustomKeys handles only our custom action keys
+// quitKey is the key the quit action is bound to: the bare quit binding,
+// or modifier+binding with keys.quit_requires_modifier.
+func (kh *KeyHandler) quitKey() string {
+	if kh.config.Keys.QuitRequiresModifier {
+		return kh.modifierKey + kh.config.Keys.Bindings.Quit
+	}
+	return kh.config.Keys.Bindings.Quit
+}
+
+// handleCustomKeys runs the current view's action bound to key, if any.
 func (kh *KeyHandler) handleCustomKeys(key string) (tea.Model, tea.Cmd, bool) {
 	b := kh.config.Keys.Bindings
 
-	// Global custom keys
+	// Any key other than quit cancels a pending quit confirmation.
+	if key != kh.quitKey() {
+		kh.app.quitPending = false
+	}
+	if key != kh.modifierKey+b.OpenAllMedia {
+		kh.app.openAllPending = false
+	}
+
+	return kh.dispatchAction(key)
+}
+
+// handleHelpOverlayKey closes the help overlay on ? or esc and swallows
+// everything else so keys do not act on the view hidden behind it.
+func (kh *KeyHandler) handleHelpOverlayKey(key string) (tea.Model, tea.Cmd) {
 	switch key {
-	case "ctrl+c", b.Quit:
-		return kh.app, tea.Quit, true
-	case "esc":
-		model, cmd := kh.navigateBack()
-		return model, cmd, true
-	case kh.modifierKey + b.Search:
-		model, cmd := kh.enterSearchMode()
-		return model, cmd, true
-	case kh.modifierKey + b.ThemeToggle:
-		kh.app.themePref = nextThemePref(kh.app.themePref)
-		kh.app.signalThemeChange()
-		return kh.app, nil, true
-	}
-
-	// View-specific custom keys
+	case "ctrl+c":
+		return kh.app, kh.app.quit()
+	case helpOverlayKey, "esc":
+		kh.app.help.ShowAll = false
+	}
+	return kh.app, nil
+}
+
+// isFilteringList reports whether the current view's list has its
+// filter input open.
+func (kh *KeyHandler) isFilteringList() bool {
 	switch kh.app.view {
 	case ViewFeeds:
-		return kh.handleFeedsCustomKeys(key)
-	case ViewArticles:
-		return kh.handleArticlesCustomKeys(key)
-	case ViewReader:
-		return kh.handleReaderCustomKeys(key)
-	case ViewDeleteConfirm:
-		return kh.handleDeleteConfirmKeys(key)
+		return kh.app.feedList.FilterState() == list.Filtering
+	case ViewArticles, ViewAllUnread:
+		return kh.app.articleList.FilterState() == list.Filtering
 	case ViewMedia:
-		return kh.handleMediaCustomKeys(key)
+		return kh.app.mediaList.FilterState() == list.Filtering
 	default:
-		return kh.app, nil, false
+		return false
 	}
 }
 
-// handleFeedsCustomKeys handles only custom action keys in feeds view
-func (kh *KeyHandler) handleFeedsCustomKeys(key string) (tea.Model, tea.Cmd, bool) {
-	b := kh.config.Keys.Bindings
-	switch key {
-	case kh.modifierKey + b.NewFeed:
-		kh.app.view = ViewAddFeed
-		kh.app.textInput.Reset()
-		kh.app.textInput.Focus()
-		return kh.app, nil, true
-	case kh.modifierKey + b.RenameFeed:
-		if len(kh.app.feeds) > 0 {
-			if i, ok := kh.app.feedList.SelectedItem().(feedItem); ok {
-				kh.app.feedToRename = i.feed
-				kh.app.view = ViewRenameFeed
-				kh.app.textInput.SetValue(i.feed.Title)
-				kh.app.textInput.Focus()
-				return kh.app, nil, true
-			}
-		}
-	case kh.modifierKey + b.DeleteFeed:
-		if len(kh.app.feeds) > 0 {
-			if i, ok := kh.app.feedList.SelectedItem().(feedItem); ok {
-				kh.app.feedToDelete = i.feed
-				kh.app.view = ViewDeleteConfirm
-				return kh.app, nil, true
-			}
-		}
-	case kh.modifierKey + b.Refresh:
-		kh.app.setStatus(MsgRefreshing, 0)
-		return kh.app, tea.Batch(kh.app.startSpinner(MsgRefreshing), kh.app.refreshFeeds()), true
+// moveSelectedFeed shifts the selected feed delta places and saves the
+// list as the manual order. It does nothing while a filter narrows the
+// list or a tag, since the visible positions are not the real ones then,
+// nor across the line between pinned and unpinned feeds, which always
+// sort apart.
+func (kh *KeyHandler) moveSelectedFeed(delta int) tea.Cmd {
+	a := kh.app
+	i := a.feedList.Index()
+	j := i + delta
+	if a.feedList.FilterState() != list.Unfiltered || a.feedTagFilter != "" || i < 0 || j < 0 || j >= len(a.feeds) {
+		return nil
 	}
-	return kh.app, nil, false
-}
-
-// handleArticlesCustomKeys handles only custom action keys in articles view
-func (kh *KeyHandler) handleArticlesCustomKeys(key string) (tea.Model, tea.Cmd, bool) {
-	b := kh.config.Keys.Bindings
-	switch key {
-	case kh.modifierKey + b.OpenMedia:
-		if i, ok := kh.app.articleList.SelectedItem().(articleItem); ok {
-			if i.article.URL != "" {
-				return kh.app, kh.openURL(i.article.URL), true
-			}
-		}
-		return kh.app, nil, true
-	case kh.modifierKey + b.ToggleRead:
-		if i, ok := kh.app.articleList.SelectedItem().(articleItem); ok {
-			return kh.app, kh.app.toggleRead(i.article), true
-		}
-		return kh.app, nil, true
-	case kh.modifierKey + b.ToggleStar:
-		if i, ok := kh.app.articleList.SelectedItem().(articleItem); ok {
-			return kh.app, kh.app.toggleStarred(i.article), true
-		}
-		return kh.app, nil, true
+	if a.feeds[i].Pinned != a.feeds[j].Pinned {
+		return nil
 	}
-	return kh.app, nil, false
+	a.feeds[i], a.feeds[j] = a.feeds[j], a.feeds[i]
+	items := a.feedList.Items()
+	items[i], items[j] = items[j], items[i]
+	a.feedList.SetItems(items)
+	a.feedList.Select(j)
+	return a.saveFeedOrder()
 }
 
-// handleReaderCustomKeys handles only custom action keys in reader view
-func (kh *KeyHandler) handleReaderCustomKeys(key string) (tea.Model, tea.Cmd, bool) {
-	if key == kh.modifierKey+kh.config.Keys.Bindings.ToggleStar {
-		if kh.app.currentArticle != nil {
-			return kh.app, kh.app.toggleStarred(kh.app.currentArticle), true
+// jumpToUnread moves the article list selection to the next unread item
+// in direction step (+1 down, -1 up), wrapping around the ends. Only the
+// visible items are scanned, so an active filter is respected.
+func (kh *KeyHandler) jumpToUnread(step int) tea.Cmd {
+	items := kh.app.articleList.VisibleItems()
+	n := len(items)
+	if n == 0 {
+		kh.app.setStatus(MsgNoUnread, 0)
+		return nil
+	}
+	cur := kh.app.articleList.Index()
+	for off := 1; off <= n; off++ {
+		i := ((cur+step*off)%n + n) % n
+		item, ok := items[i].(articleItem)
+		if !ok || item.article.Read {
+			continue
 		}
-		return kh.app, nil, true
-	}
-	if key == kh.modifierKey+kh.config.Keys.Bindings.OpenMedia {
-		if kh.app.currentArticle != nil {
-			// If there are multiple media URLs, show media list
-			if len(kh.app.currentArticle.MediaURLs) > 1 {
-				model, cmd := kh.openMediaList()
-				return model, cmd, true
-			}
-
-			// If there's only one media URL or just the article URL, open it directly
-			var url string
-			if len(kh.app.currentArticle.MediaURLs) == 1 {
-				url = kh.app.currentArticle.MediaURLs[0]
-			} else if kh.app.currentArticle.URL != "" {
-				url = kh.app.currentArticle.URL
-			}
-
-			if url != "" {
-				return kh.app, kh.openURL(url), true
-			}
+		kh.app.articleList.Select(i)
+		switch {
+		case step > 0 && i <= cur:
+			kh.app.setStatus(MsgWrappedToTop, 0)
+		case step < 0 && i >= cur:
+			kh.app.setStatus(MsgWrappedToEnd, 0)
 		}
-		return kh.app, nil, true
+		return kh.app.maybeLoadMoreArticles()
 	}
-	return kh.app, nil, false
+	kh.app.setStatus(MsgNoUnread, 0)
+	return nil
 }
 
 // delegateToCharm lets Charm handle all keys we don't intercept
@@ -305,27 +301,20 @@ func (kh *KeyHandler) delegateToCharm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Handle enter key for feed selection
 		if msg.String() == "enter" {
 			if i, ok := kh.app.feedList.SelectedItem().(feedItem); ok {
-				kh.app.currentFeed = i.feed
 				kh.app.articlesOrigin = ViewFeeds
-				kh.app.view = ViewArticles
-				return kh.app, kh.app.loadArticles(i.feed.ID)
+				return kh.app, kh.app.openFeed(i.feed)
 			}
 		}
 		return kh.app, cmd
 
-	case ViewArticles:
+	case ViewArticles, ViewAllUnread:
 		kh.app.articleList, cmd = kh.app.articleList.Update(msg)
 		// Handle enter key for article selection
 		if msg.String() == "enter" {
 			if i, ok := kh.app.articleList.SelectedItem().(articleItem); ok {
-				kh.app.currentArticle = i.article
 				kh.app.cameFromSearch = false
-				kh.app.loadingArticle = true // Set loading flag
-				kh.app.view = ViewReader
-				// Mark article as read when opened
-				markReadCmd := kh.app.markArticleRead(i.article)
-				renderCmd := kh.app.renderArticle(i.article)
-				return kh.app, tea.Batch(kh.app.startSpinner(MsgLoadingArticle), markReadCmd, renderCmd)
+				kh.app.readerOrigin = kh.app.view
+				return kh.app, kh.app.openArticle(i.article)
 			}
 		}
 		if more := kh.app.maybeLoadMoreArticles(); more != nil {
@@ -369,6 +358,21 @@ func (kh *KeyHandler) delegateToCharm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		kh.app.viewport, cmd = kh.app.viewport.Update(msg)
 		return kh.app, cmd
 
+	case ViewTags:
+		kh.app.tagList, cmd = kh.app.tagList.Update(msg)
+		if msg.String() == "enter" {
+			if i, ok := kh.app.tagList.SelectedItem().(tagItem); ok {
+				kh.app.view = kh.app.previousView
+				if i.feeds {
+					kh.app.setFeedTagFilter(i.tag)
+					return kh.app, nil
+				}
+				kh.app.setTagFilter(i.tag)
+				return kh.app, kh.app.maybeLoadMoreArticles()
+			}
+		}
+		return kh.app, cmd
+
 	case ViewMedia:
 		// Let the media list handle navigation
 		kh.app.mediaList, cmd = kh.app.mediaList.Update(msg)
@@ -385,67 +389,58 @@ func (kh *KeyHandler) delegateToCharm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
-// handleMediaCustomKeys handles keys in the media list view
-func (kh *KeyHandler) handleMediaCustomKeys(key string) (tea.Model, tea.Cmd, bool) {
-	switch key {
-	case "enter":
-		// Open the selected media item
-		if item, ok := kh.app.mediaList.SelectedItem().(mediaItem); ok {
-			return kh.app, kh.openURL(item.url), true
-		}
-		return kh.app, nil, true
-	case kh.modifierKey + kh.config.Keys.Bindings.OpenMedia:
-		// Also handle the configured open key
-		if item, ok := kh.app.mediaList.SelectedItem().(mediaItem); ok {
-			return kh.app, kh.openURL(item.url), true
-		}
-		return kh.app, nil, true
-	}
-	return kh.app, nil, false
-}
+// openAllMediaConfirmAbove is the media count past which opening them all
+// takes a second press, so a gallery post does not spray dozens of
+// viewer windows on one keystroke.
+const openAllMediaConfirmAbove = 5
 
-func (kh *KeyHandler) handleDeleteConfirmKeys(key string) (tea.Model, tea.Cmd, bool) {
-	if key == "enter" {
-		if kh.app.feedToDelete != nil {
-			kh.app.setStatus(MsgDeleting, 0)
-			return kh.app, kh.app.deleteFeed(kh.app.feedToDelete.ID), true
-		}
+// openAllMedia opens every URL in the media list.
+func (kh *KeyHandler) openAllMedia() tea.Cmd {
+	urls := kh.app.mediaURLs
+	if len(urls) == 0 {
+		return nil
+	}
+	if len(urls) > openAllMediaConfirmAbove && !kh.app.openAllPending {
+		kh.app.openAllPending = true
+		kh.app.setStatusWithKind(MsgOpenAllConfirm(len(urls), kh.modifierKey+kh.config.Keys.Bindings.OpenAllMedia), StatusWarn, 0)
+		return nil
+	}
+	kh.app.openAllPending = false
+	kh.app.setStatus(MsgOpeningAll(len(urls)), 0)
+	cmds := make([]tea.Cmd, len(urls))
+	for i, u := range urls {
+		cmds[i] = kh.openURL(u)
 	}
-	return kh.app, nil, false
+	return tea.Batch(cmds...)
 }
 
 // selectSearchResult handles selection of search results
 func (kh *KeyHandler) selectSearchResult(result searchResultItem) (tea.Model, tea.Cmd) {
+	// A query worth opening a result of is worth recalling.
+	recordCmd := kh.app.recordSearch(kh.sanitizeSearchInput(kh.app.searchInput.Value()))
 	if result.isArticle {
 		// Validate article data
 		if result.article == nil {
 			return kh.app, nil
 		}
-		kh.app.currentArticle = result.article
 		kh.app.currentFeed = result.feed
 		kh.app.cameFromSearch = true
-		kh.app.loadingArticle = true // Set loading flag
-		kh.app.view = ViewReader
-		// Mark article as read when opened
-		markReadCmd := kh.app.markArticleRead(result.article)
-		renderCmd := kh.app.renderArticle(result.article)
-		return kh.app, tea.Batch(kh.app.startSpinner(MsgLoadingArticle), markReadCmd, renderCmd)
+		return kh.app, tea.Batch(kh.app.openArticle(result.article), recordCmd)
 	}
 
 	// Validate feed data
 	if result.feed == nil {
 		return kh.app, nil
 	}
-	kh.app.currentFeed = result.feed
 	kh.app.cameFromSearch = false
+	kh.app.tagFilter = ""
 	// Mark the article list as having been opened from a search result
 	// so navigateBack returns the user to their search hits instead of
 	// the feed list. Also clear previousView so a later in-articles
 	// Ctrl+S does not pick up a stale ViewReader context.
 	kh.app.articlesOrigin = ViewSearch
 	kh.app.previousView = ViewArticles
-	kh.app.view = ViewArticles
-	return kh.app, kh.app.loadArticles(result.feed.ID)
+	return kh.app, tea.Batch(kh.app.openFeed(result.feed), recordCmd)
 }
 
 // navigateBack implements smart back navigation
@@ -457,6 +452,18 @@ func (kh *KeyHandler) navigateBack() (tea.Model, tea.Cmd) {
 		kh.app.feedToRename = nil
 		return kh.app, nil
 
+	case ViewFeeds:
+		// The first Esc after a tag filter only clears it.
+		if kh.app.feedTagFilter != "" {
+			kh.app.setFeedTagFilter("")
+			return kh.app, nil
+		}
+		// Esc is as easy to hit by accident as a bare q.
+		if kh.config.Keys.QuitRequiresModifier {
+			return kh.app, nil
+		}
+		return kh.app, kh.app.quit()
+
 	case ViewSearch:
 		kh.app.view = kh.app.previousView
 		kh.app.searchInput.Reset()
@@ -470,7 +477,17 @@ func (kh *KeyHandler) navigateBack() (tea.Model, tea.Cmd) {
 		kh.app.mediaList.SetItems([]list.Item{})
 		return kh.app, nil
 
+	case ViewTags:
+		kh.app.view = kh.app.previousView
+		kh.app.tagList.SetItems([]list.Item{})
+		return kh.app, nil
+
 	case ViewArticles:
+		// The first Esc after a tag filter only clears it.
+		if kh.app.tagFilter != "" {
+			kh.app.setTagFilter("")
+			return kh.app, nil
+		}
 		// Drop any active list filter so the next entry into ViewArticles
 		// (or back-navigation overlays) does not show stale Charm filter
 		// state from a previous browse.
@@ -484,7 +501,21 @@ func (kh *KeyHandler) navigateBack() (tea.Model, tea.Cmd) {
 		kh.app.view = ViewFeeds
 		return kh.app, nil
 
+	case ViewAllUnread:
+		if kh.app.tagFilter != "" {
+			kh.app.setTagFilter("")
+			return kh.app, nil
+		}
+		kh.app.articleList.ResetFilter()
+		kh.app.view = ViewFeeds
+		return kh.app, nil
+
 	case ViewReader:
+		// The first Esc after a find only clears its highlight.
+		if kh.app.find.query != "" {
+			kh.app.clearFind()
+			return kh.app, nil
+		}
 		// Clear any in-flight loading state so a delayed articleRenderedMsg
 		// arriving after navigation doesn't leave the spinner running.
 		kh.app.loadingArticle = false
@@ -496,11 +527,15 @@ func (kh *KeyHandler) navigateBack() (tea.Model, tea.Cmd) {
 			kh.app.searchInput.Blur()
 			return kh.app, nil
 		}
+		if kh.app.readerOrigin == ViewAllUnread {
+			kh.app.view = ViewAllUnread
+			return kh.app, nil
+		}
 		kh.app.view = ViewArticles
 		return kh.app, nil
 
 	default:
-		return kh.app, tea.Quit
+		return kh.app, kh.app.quit()
 	}
 }
 
@@ -512,15 +547,16 @@ func (kh *KeyHandler) enterSearchMode() (tea.Model, tea.Cmd) {
 	kh.app.searchInput.Focus()
 	kh.app.searchResults = []searchResultItem{}
 	kh.app.searchList.SetItems([]list.Item{})
+	kh.app.searchHistoryPos = -1
 	engineName := kh.app.searchEngineType
 	if ds, ok := kh.app.searchEngine.(search.DebugStatser); ok {
 		if n, err := ds.DocCount(); err == nil {
 			kh.app.setStatus(fmt.Sprintf("Search: %s • idx: %d", engineName, n), 0)
-			return kh.app, nil
+			return kh.app, kh.app.loadSearchHistory()
 		}
 	}
 	kh.app.setStatus(fmt.Sprintf("Search: %s", engineName), 0)
-	return kh.app, nil
+	return kh.app, kh.app.loadSearchHistory()
 }
 
 // sanitizeSearchInput sanitizes and limits search input length
@@ -546,18 +582,18 @@ func (kh *KeyHandler) sanitizeSearchInput(input string) string {
 }
 
 // openMediaList enters the media chooser populated from the current
-// article's MediaURLs. The article's own URL is prepended as a synthetic
+// article's Media. The article's own URL is prepended as a synthetic
 // entry so the chooser is never a dead-end: users can still open the
 // underlying article even when the article carries multiple media items.
 func (kh *KeyHandler) openMediaList() (tea.Model, tea.Cmd) {
-	if kh.app.currentArticle == nil || len(kh.app.currentArticle.MediaURLs) == 0 {
+	if kh.app.currentArticle == nil || len(kh.app.currentArticle.Media) == 0 {
 		return kh.app, nil
 	}
 
 	detector, _ := media.NewTypeDetector()
-	mediaURLs := kh.app.currentArticle.MediaURLs
+	entries := kh.app.currentArticle.Media
 
-	items := make([]list.Item, 0, len(mediaURLs)+1)
+	items := make([]list.Item, 0, len(entries)+1)
 	if kh.app.currentArticle.URL != "" {
 		items = append(items, mediaItem{
 			url:       kh.app.currentArticle.URL,
@@ -565,22 +601,24 @@ func (kh *KeyHandler) openMediaList() (tea.Model, tea.Cmd) {
 			icons:     &kh.app.icons,
 		})
 	}
-	for i, url := range mediaURLs {
+	for i, m := range entries {
 		mediaType := media.TypeUnknown
 		if detector != nil {
-			mediaType = detector.DetectType(url)
+			mediaType = detector.DetectType(m.URL)
 		}
 		items = append(items, mediaItem{
-			url:       url,
+			url:       m.URL,
 			mediaType: mediaType,
+			mime:      m.MIME,
+			length:    m.Length,
 			index:     i,
-			total:     len(mediaURLs),
+			total:     len(entries),
 			icons:     &kh.app.icons,
 		})
 	}
 
 	kh.app.mediaList.SetItems(items)
-	kh.app.mediaURLs = mediaURLs
+	kh.app.mediaURLs = kh.app.currentArticle.MediaURLs()
 	kh.app.previousView = kh.app.view
 	kh.app.view = ViewMedia
 
@@ -601,6 +639,17 @@ func (kh *KeyHandler) openMediaList() (tea.Model, tea.Cmd) {
 
 func (kh *KeyHandler) openURL(url string) tea.Cmd {
 	return func() tea.Msg {
+		if kh.app.launcher.DryRun {
+			// stderr is hidden behind the alt screen; show the command
+			// in the status bar instead.
+			cmd, err := kh.app.launcher.Command(url)
+			if err != nil {
+				return errorMsg{err: fmt.Errorf("failed to open %s: %w", url, err)}
+			}
+			line := media.FormatArgv(cmd.Args)
+			debuglog.Infof("print-open: %s", line)
+			return openPreviewMsg{command: line}
+		}
 		if err := kh.app.launcher.Open(url); err != nil {
 			return errorMsg{err: fmt.Errorf("failed to open %s: %w", url, err)}
 		}
@@ -608,41 +657,12 @@ func (kh *KeyHandler) openURL(url string) tea.Cmd {
 	}
 }
 
-// GetHelpForCurrentView returns only our custom help text (Charm handles the rest)
+// GetHelpForCurrentView returns the status bar hints for the current
+// view's own actions (Charm's list help covers the rest).
 func (kh *KeyHandler) GetHelpForCurrentView() []string {
-	b := kh.config.Keys.Bindings
-	switch kh.app.view {
-	case ViewFeeds:
-		help := []string{kh.modifierKey + b.NewFeed + ": new", kh.modifierKey + b.Refresh + ": refresh", kh.modifierKey + b.Search + ": search"}
-		if len(kh.app.feeds) > 0 {
-			help = append(help, kh.modifierKey+b.RenameFeed+": rename", kh.modifierKey+b.DeleteFeed+": delete")
-		}
-		return help
-
-	case ViewArticles:
-		return []string{kh.modifierKey + b.OpenMedia + ": open", kh.modifierKey + b.ToggleRead + ": toggle read", kh.modifierKey + b.ToggleStar + ": star", kh.modifierKey + b.Search + ": search"}
-
-	case ViewReader:
-		return []string{kh.modifierKey + b.OpenMedia + ": open media", kh.modifierKey + b.ToggleStar + ": star", kh.modifierKey + b.Search + ": search"}
-
-	case ViewSearch:
-		// Include search engine status in search view
-		searchStatus := kh.app.getSearchEngineStatus()
-		return []string{kh.modifierKey + b.Search + ": search", searchStatus}
-
-	case ViewMedia:
-		return []string{"enter: open", kh.modifierKey + b.OpenMedia + ": open", "esc: back"}
-
-	case ViewAddFeed:
-		return []string{"enter: add", "esc: cancel"}
-
-	case ViewRenameFeed:
-		return []string{"enter: rename", "esc: cancel"}
-
-	case ViewDeleteConfirm:
-		return []string{"enter: confirm", "esc: cancel"}
-
-	default:
-		return []string{}
+	hints := kh.footerHints()
+	if kh.app.view == ViewSearch {
+		hints = append(hints, kh.app.getSearchEngineStatus())
 	}
+	return hints
 }
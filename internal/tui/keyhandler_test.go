@@ -1,11 +1,17 @@
 package tui
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/pders01/fwrd/internal/config"
 	"github.com/pders01/fwrd/internal/storage"
@@ -13,7 +19,7 @@ import (
 
 func TestKeyHandler_ModifierKey(t *testing.T) {
 	cfg := config.TestConfig()
-	store := &storage.Store{}
+	store := newTestStore(t)
 	app := NewApp(store, cfg)
 
 	// Test that keyHandler is initialized with correct modifier
@@ -23,7 +29,7 @@ func TestKeyHandler_ModifierKey(t *testing.T) {
 
 func TestKeyHandler_HandleKey_CtrlN(t *testing.T) {
 	cfg := config.TestConfig()
-	store := &storage.Store{}
+	store := newTestStore(t)
 	app := NewApp(store, cfg)
 
 	// Start with ViewFeeds
@@ -40,7 +46,7 @@ func TestKeyHandler_HandleKey_CtrlN(t *testing.T) {
 
 func TestKeyHandler_HandleKey_CtrlS(t *testing.T) {
 	cfg := config.TestConfig()
-	store := &storage.Store{}
+	store := newTestStore(t)
 	app := NewApp(store, cfg)
 
 	// Start with ViewFeeds
@@ -57,7 +63,7 @@ func TestKeyHandler_HandleKey_CtrlS(t *testing.T) {
 
 func TestKeyHandler_HandleKey_CtrlX(t *testing.T) {
 	cfg := config.TestConfig()
-	store := &storage.Store{}
+	store := newTestStore(t)
 	app := NewApp(store, cfg)
 
 	// Start with ViewFeeds
@@ -75,3 +81,527 @@ func TestKeyHandler_HandleKey_CtrlX(t *testing.T) {
 	// Should switch to ViewDeleteConfirm
 	assert.Equal(t, ViewDeleteConfirm, updatedApp.view, "Ctrl+X should switch to ViewDeleteConfirm")
 }
+
+func TestKeyHandler_QuitWhileBusyNeedsConfirmation(t *testing.T) {
+	cfg := config.TestConfig()
+	app := NewApp(newTestStore(t), cfg)
+	app.view = ViewFeeds
+	quit := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(cfg.Keys.Bindings.Quit)}
+
+	app.startSpinner(MsgRefreshing)
+
+	_, cmd := app.keyHandler.HandleKey(quit)
+	assert.Nil(t, cmd, "first quit during a refresh should only prompt")
+	assert.True(t, app.quitPending)
+
+	// Any other key cancels the pending confirmation.
+	app.keyHandler.HandleKey(tea.KeyMsg{Type: tea.KeyDown})
+	assert.False(t, app.quitPending)
+
+	app.keyHandler.HandleKey(quit)
+	_, cmd = app.keyHandler.HandleKey(quit)
+	if assert.NotNil(t, cmd) {
+		assert.IsType(t, tea.QuitMsg{}, cmd())
+	}
+}
+
+func TestKeyHandler_QuitWhenIdleIsImmediate(t *testing.T) {
+	cfg := config.TestConfig()
+	app := NewApp(newTestStore(t), cfg)
+	app.view = ViewFeeds
+
+	_, cmd := app.keyHandler.HandleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(cfg.Keys.Bindings.Quit)})
+	if assert.NotNil(t, cmd) {
+		assert.IsType(t, tea.QuitMsg{}, cmd())
+	}
+}
+
+func TestKeyHandler_QuitRequiresModifier(t *testing.T) {
+	cfg := config.TestConfig()
+	cfg.Keys.QuitRequiresModifier = true
+	app := NewApp(newTestStore(t), cfg)
+	quits := func(msg tea.KeyMsg) bool {
+		_, cmd := app.keyHandler.HandleKey(msg)
+		if cmd == nil {
+			return false
+		}
+		_, ok := cmd().(tea.QuitMsg)
+		return ok
+	}
+
+	for _, view := range []View{ViewFeeds, ViewArticles, ViewReader} {
+		app.view = view
+		assert.False(t, quits(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(cfg.Keys.Bindings.Quit)}), "bare quit key in view %v", view)
+	}
+	app.view = ViewFeeds
+	assert.False(t, quits(tea.KeyMsg{Type: tea.KeyEsc}), "esc on the feed list")
+	assert.True(t, quits(tea.KeyMsg{Type: tea.KeyCtrlQ}))
+	assert.True(t, quits(tea.KeyMsg{Type: tea.KeyCtrlC}))
+}
+
+func TestKeyHandler_JumpToUnreadSkipsReadAndWraps(t *testing.T) {
+	cfg := config.TestConfig()
+	app := NewApp(newTestStore(t), cfg)
+	app.view = ViewArticles
+	app.articleList.SetSize(80, 40)
+
+	app.articles = []*storage.Article{
+		{ID: "a0", Title: "zero", Read: true},
+		{ID: "a1", Title: "one"},
+		{ID: "a2", Title: "two", Read: true},
+		{ID: "a3", Title: "three"},
+	}
+	items := make([]list.Item, len(app.articles))
+	for i, a := range app.articles {
+		items[i] = articleItem{article: a}
+	}
+	app.articleList.SetItems(items)
+	app.articleList.Select(0)
+
+	next := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}
+	prev := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")}
+
+	app.Update(next)
+	assert.Equal(t, 1, app.articleList.Index(), "n should skip the read article")
+	app.Update(next)
+	assert.Equal(t, 3, app.articleList.Index(), "n should skip a2")
+	app.Update(next)
+	assert.Equal(t, 1, app.articleList.Index(), "n should wrap to the first unread")
+	assert.Equal(t, MsgWrappedToTop, app.statusText)
+
+	app.Update(prev)
+	assert.Equal(t, 3, app.articleList.Index(), "p should wrap to the last unread")
+	assert.Equal(t, MsgWrappedToEnd, app.statusText)
+
+	for _, a := range app.articles {
+		a.Read = true
+	}
+	app.Update(next)
+	assert.Equal(t, 3, app.articleList.Index(), "selection stays put with nothing unread")
+	assert.Equal(t, MsgNoUnread, app.statusText)
+}
+
+// TestKeyHandler_EveryHandledKeyIsInHelp presses every configured key,
+// with and without the modifier, in each view and checks that any key a
+// view acts on is listed in the ? overlay.
+func TestKeyHandler_EveryHandledKeyIsInHelp(t *testing.T) {
+	cfg := config.TestConfig()
+	candidates := []string{"enter", "esc", "ctrl+c", "tab", helpOverlayKey, findKey, findNextKey, findPrevKey}
+	bindings := reflect.ValueOf(cfg.Keys.Bindings)
+	for i := range bindings.NumField() {
+		k := bindings.Field(i).String()
+		candidates = append(candidates, k, cfg.Keys.Modifier+"+"+k)
+	}
+
+	feed := &storage.Feed{ID: "f1", Title: "Feed", SiteURL: "https://example.com"}
+	article := &storage.Article{ID: "a1", FeedID: "f1", Title: "One", URL: "https://example.com/1"}
+	setup := func(view View) *App {
+		app := NewApp(newTestStore(t), cfg)
+		app.feeds = []*storage.Feed{feed}
+		app.feedList.SetItems([]list.Item{feedItem{feed: feed}})
+		app.currentFeed = feed
+		app.articles = []*storage.Article{article}
+		app.articleList.SetItems(app.articleItems(app.articles))
+		app.currentArticle = article
+		app.find = readerFind{query: "one", lines: []int{0}}
+		app.mediaList.SetItems([]list.Item{mediaItem{url: article.URL}})
+		app.feedToDelete = feed
+		app.view = view
+		return app
+	}
+
+	inHelp := map[string]bool{}
+	for _, group := range setup(ViewFeeds).keyHandler.helpKeys().FullHelp() {
+		for _, b := range group {
+			for _, k := range b.Keys() {
+				inHelp[k] = true
+			}
+		}
+	}
+
+	views := []View{ViewFeeds, ViewArticles, ViewAllUnread, ViewReader, ViewSearch, ViewMedia, ViewTags, ViewDeleteConfirm}
+	for _, view := range views {
+		for _, k := range candidates {
+			app := setup(view)
+			if _, _, handled := app.keyHandler.handleCustomKeys(k); handled {
+				assert.True(t, inHelp[k], "view %d handles %q but the help does not list it", view, k)
+			}
+		}
+	}
+}
+
+func TestKeyHandler_HelpOverlayShowsRemappedKeys(t *testing.T) {
+	cfg := config.TestConfig()
+	cfg.Keys.Bindings.NewFeed = "g"
+	cfg.Keys.Bindings.NextUnread = "J"
+	app := NewApp(newTestStore(t), cfg)
+	app.view = ViewFeeds
+	app.width, app.height = 160, 40
+
+	help := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")}
+	app.Update(help)
+	assert.True(t, app.help.ShowAll, "? should open the help overlay")
+
+	view := app.View()
+	for _, want := range []string{"ctrl+g", "new feed", "J/p", "next/prev unread", "ctrl+s", "search", "ctrl+o", "open feed site", "esc", "back"} {
+		assert.Contains(t, view, want)
+	}
+
+	// Keys behind the overlay are swallowed until it closes.
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	assert.Equal(t, ViewFeeds, app.view)
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, app.help.ShowAll, "esc should close the help overlay")
+	assert.Equal(t, ViewFeeds, app.view)
+}
+
+func TestKeyHandler_UnreadInboxSpansFeeds(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	now := time.Now()
+	feeds := []*storage.Feed{{ID: "f1", Title: "One"}, {ID: "f2", Title: "Two"}}
+	for _, f := range feeds {
+		require.NoError(t, store.SaveFeed(f))
+	}
+	require.NoError(t, store.SaveArticles([]*storage.Article{
+		{ID: "old", FeedID: "f1", Title: "Old", Published: now.Add(-2 * time.Hour)},
+		{ID: "read", FeedID: "f2", Title: "Read", Published: now.Add(-time.Hour), Read: true},
+		{ID: "new", FeedID: "f2", Title: "New", Published: now},
+	}))
+
+	app := NewApp(store, config.TestConfig())
+	app.view = ViewFeeds
+	app.feeds = feeds
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	assert.Equal(t, ViewAllUnread, app.view)
+	require.NotNil(t, cmd)
+	app.Update(cmd())
+
+	items := app.articleList.Items()
+	require.Len(t, items, 2)
+	first := items[0].(articleItem)
+	assert.Equal(t, "new", first.article.ID, "newest unread comes first")
+	assert.Contains(t, first.Description(), "Two", "items name their feed")
+	assert.Equal(t, "old", items[1].(articleItem).article.ID)
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, ViewReader, app.view)
+	assert.Equal(t, "new", app.currentArticle.ID)
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ViewAllUnread, app.view, "esc from the reader returns to the unread view")
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ViewFeeds, app.view)
+}
+
+func TestKeyHandler_ToggleOrderIsRememberedPerFeed(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	now := time.Now()
+	feed := &storage.Feed{ID: "f1", Title: "Serial"}
+	require.NoError(t, store.SaveFeed(feed))
+	require.NoError(t, store.SaveArticles([]*storage.Article{
+		{ID: "part1", FeedID: "f1", Title: "Part 1", Published: now.Add(-time.Hour)},
+		{ID: "part2", FeedID: "f1", Title: "Part 2", Published: now},
+	}))
+
+	app := NewApp(store, config.TestConfig())
+	app.view = ViewArticles
+	app.currentFeed = feed
+	app.Update(app.loadArticles(feed.ID)())
+	assert.Equal(t, "part2", app.articles[0].ID, "newest first by default")
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	require.NotNil(t, cmd)
+	app.Update(cmd())
+	assert.Equal(t, MsgOldestFirst, app.statusText)
+	assert.Equal(t, "part1", app.articles[0].ID)
+
+	stored, err := store.GetFeed(feed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, storage.ReadingOrderOldest, stored.ReadingOrder)
+
+	// Toggling again goes back to newest first.
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	app.Update(cmd())
+	assert.Equal(t, "part2", app.articles[0].ID)
+}
+
+func TestKeyHandler_MoveFeedSavesManualOrder(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	for _, f := range []*storage.Feed{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}, {ID: "c", Title: "C"}} {
+		require.NoError(t, store.SaveFeed(f))
+	}
+	feeds, err := store.GetAllFeeds()
+	require.NoError(t, err)
+
+	app := NewApp(store, config.TestConfig())
+	app.view = ViewFeeds
+	app.Update(feedsLoadedMsg{feeds: feeds})
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyShiftDown})
+	require.NotNil(t, cmd)
+	assert.Nil(t, cmd(), "saving the order reports nothing on success")
+	assert.Equal(t, 1, app.feedList.Index(), "the selection follows the moved feed")
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyShiftUp})
+	require.NotNil(t, cmd)
+	cmd()
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyShiftUp})
+	assert.Nil(t, cmd, "the first feed cannot move further up")
+
+	app.feedList.Select(2)
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyShiftUp})
+	require.NotNil(t, cmd)
+	cmd()
+
+	stored, err := store.GetAllFeeds()
+	require.NoError(t, err)
+	var ids []string
+	for _, f := range stored {
+		ids = append(ids, f.ID)
+	}
+	assert.Equal(t, []string{"a", "c", "b"}, ids)
+}
+
+func TestKeyHandler_TogglePinMovesFeedToTop(t *testing.T) {
+	store, err := storage.NewStore(":memory:")
+	require.NoError(t, err)
+	defer store.Close()
+
+	for _, f := range []*storage.Feed{{ID: "a", Title: "A"}, {ID: "b", Title: "B"}, {ID: "c", Title: "C"}} {
+		require.NoError(t, store.SaveFeed(f))
+	}
+	feeds, err := store.GetAllFeeds()
+	require.NoError(t, err)
+
+	app := NewApp(store, config.TestConfig())
+	app.view = ViewFeeds
+	app.Update(feedsLoadedMsg{feeds: feeds})
+	app.feedList.Select(2)
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	require.NotNil(t, cmd)
+	app.Update(cmd())
+
+	require.Len(t, app.feeds, 3)
+	assert.Equal(t, "c", app.feeds[0].ID, "the pinned feed sorts first")
+	assert.Equal(t, 0, app.feedList.Index(), "the cursor follows the pinned feed")
+	assert.Contains(t, app.feedList.SelectedItem().(feedItem).Title(), "📌")
+
+	stored, err := store.GetFeed("c")
+	require.NoError(t, err)
+	assert.True(t, stored.Pinned, "the pin is saved")
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyShiftDown})
+	assert.Nil(t, cmd, "a pinned feed cannot move below the unpinned ones")
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	require.NotNil(t, cmd)
+	app.Update(cmd())
+	assert.Equal(t, "c", app.feeds[2].ID, "unpinning returns the feed to title order")
+}
+
+func TestKeyHandler_FindInReader(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.width, app.height = 80, 24
+	app.viewport.Width, app.viewport.Height = 80, 10
+	app.view = ViewReader
+	app.currentArticle = &storage.Article{ID: "a", Title: "Long read"}
+
+	lines := make([]string, 60)
+	for i := range lines {
+		lines[i] = "filler"
+	}
+	lines[5] = "first Needle here"
+	lines[40] = "second needle here"
+	app.Update(articleRenderedMsg{content: strings.Join(lines, "\n")})
+
+	typeKeys := func(s string) {
+		for _, r := range s {
+			app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	typeKeys("/")
+	require.True(t, app.findInput.Focused(), "/ opens the find input")
+	typeKeys("needle")
+	assert.Contains(t, app.getCustomStatusBar(), "needle")
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.False(t, app.findInput.Focused())
+	assert.Equal(t, []int{5, 40}, app.find.lines, "matching ignores case")
+	assert.Contains(t, app.getCustomStatusBar(), MsgFindMatch(1, 2, "needle"))
+
+	typeKeys("n")
+	assert.Equal(t, 1, app.find.current)
+	assert.LessOrEqual(t, app.viewport.YOffset, 40)
+	assert.Greater(t, app.viewport.YOffset+app.viewport.Height, 40, "the second match is scrolled into view")
+
+	typeKeys("n")
+	assert.Equal(t, 0, app.find.current, "n wraps to the first match")
+	typeKeys("N")
+	assert.Equal(t, 1, app.find.current, "N wraps back to the last match")
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ViewReader, app.view, "the first esc only clears the find")
+	assert.Empty(t, app.find.lines)
+	assert.Equal(t, app.readerContent, strings.Join(lines, "\n"))
+
+	typeKeys("/")
+	app.findInput.SetValue("absent")
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Contains(t, app.getCustomStatusBar(), MsgFindNoMatch("absent"))
+}
+
+func TestHighlightMatches(t *testing.T) {
+	// Tests render without color, so mark matches visibly instead.
+	saved := FindMatchStyle
+	defer func() { FindMatchStyle = saved }()
+	FindMatchStyle = lipgloss.NewStyle().Transform(func(s string) string { return "[" + s + "]" })
+
+	assert.Equal(t, "[Go] to [go], [GO]!", highlightMatches("Go to go, GO!", "go"))
+	assert.Equal(t, "nothing", highlightMatches("nothing", "go"))
+}
+
+func TestKeyHandler_OpenSiteFromFeedList(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.SetPrintOpen(true)
+	app.view = ViewFeeds
+	app.Update(feedsLoadedMsg{feeds: []*storage.Feed{
+		{ID: "with", Title: "With", SiteURL: "https://site.example/"},
+		{ID: "without", Title: "Without"},
+	}})
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	require.NotNil(t, cmd)
+	msg, ok := cmd().(openPreviewMsg)
+	require.True(t, ok)
+	assert.Contains(t, msg.command, "https://site.example/")
+
+	app.feedList.Select(1)
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	assert.Nil(t, cmd)
+	assert.Equal(t, MsgNoSiteURL, app.statusText)
+}
+
+func TestKeyHandler_OpenAllMediaConfirmsLongLists(t *testing.T) {
+	openAll := tea.KeyMsg{Type: tea.KeyCtrlG}
+	setup := func(n int) *App {
+		app := NewApp(newTestStore(t), config.TestConfig())
+		app.SetPrintOpen(true)
+		app.view = ViewMedia
+		for i := range n {
+			app.mediaURLs = append(app.mediaURLs, fmt.Sprintf("https://example.com/%d.jpg", i))
+		}
+		return app
+	}
+
+	app := setup(3)
+	_, cmd := app.Update(openAll)
+	if assert.NotNil(t, cmd, "a short list opens on the first press") {
+		assert.Len(t, cmd().(tea.BatchMsg), 3)
+	}
+
+	app = setup(7)
+	_, cmd = app.Update(openAll)
+	assert.Nil(t, cmd, "a long list asks first")
+	assert.Contains(t, app.statusText, "Open all 7")
+	_, cmd = app.Update(openAll)
+	if assert.NotNil(t, cmd, "the second press opens them") {
+		assert.Len(t, cmd().(tea.BatchMsg), 7)
+	}
+
+	// Any other key in between drops the pending confirmation.
+	app.Update(openAll)
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd = app.Update(openAll)
+	assert.Nil(t, cmd)
+}
+
+func TestKeyHandler_FilterArticlesByTag(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.view = ViewArticles
+	app.articles = []*storage.Article{
+		{ID: "a1", Title: "One", Tags: []string{"go", "tui"}},
+		{ID: "a2", Title: "Two", Tags: []string{"go"}},
+		{ID: "a3", Title: "Three"},
+	}
+	app.articleList.SetItems(app.articleItems(app.articles))
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	assert.Nil(t, cmd)
+	require.Equal(t, ViewTags, app.view)
+	tags := app.tagList.Items()
+	require.Len(t, tags, 3)
+	assert.Equal(t, tagItem{count: 3}, tags[0], "the first entry lists everything")
+	assert.Equal(t, tagItem{tag: "go", count: 2}, tags[1], "most used tag comes first")
+	assert.Equal(t, tagItem{tag: "tui", count: 1}, tags[2])
+
+	app.tagList.Select(2)
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, ViewArticles, app.view)
+	assert.Equal(t, "tui", app.tagFilter)
+	assert.Len(t, app.articleList.Items(), 1)
+	assert.Contains(t, app.View(), MsgTagFilter("tui"))
+
+	// The first Esc clears the filter and stays in the list.
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ViewArticles, app.view)
+	assert.Empty(t, app.tagFilter)
+	assert.Len(t, app.articleList.Items(), 3)
+}
+
+func TestKeyHandler_FilterFeedsByTag(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.Update(feedsLoadedMsg{feeds: []*storage.Feed{
+		{ID: "f1", Title: "Go Blog", Tags: []string{"Go", "news"}},
+		{ID: "f2", Title: "Rust Blog", Tags: []string{"rust", "news"}},
+		{ID: "f3", Title: "Untagged"},
+	}})
+	assert.Contains(t, app.feedList.Items()[0].(feedItem).Description(), "#Go #news")
+
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	require.Equal(t, ViewTags, app.view)
+	tags := app.tagList.Items()
+	require.Len(t, tags, 4)
+	assert.Equal(t, tagItem{count: 3, feeds: true}, tags[0])
+	assert.Equal(t, tagItem{tag: "news", count: 2, feeds: true}, tags[1])
+
+	app.tagList.Select(1)
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, ViewFeeds, app.view)
+	assert.Len(t, app.feedList.Items(), 2)
+	assert.Contains(t, app.View(), MsgTagFilter("news"))
+
+	// Selecting a feed the filter hides brings the whole list back.
+	app.selectFeed("f3")
+	assert.Empty(t, app.feedTagFilter)
+	assert.Equal(t, "f3", app.feedList.SelectedItem().(feedItem).feed.ID)
+
+	app.setFeedTagFilter("GO")
+	assert.Len(t, app.feedList.Items(), 1, "feed tags match case-insensitively")
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ViewFeeds, app.view)
+	assert.Empty(t, app.feedTagFilter)
+	assert.Len(t, app.feedList.Items(), 3)
+}
+
+func TestKeyHandler_FilterTagWithoutTags(t *testing.T) {
+	app := NewApp(newTestStore(t), config.TestConfig())
+	app.view = ViewArticles
+	app.articles = []*storage.Article{{ID: "a1", Title: "One"}}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	assert.Equal(t, ViewArticles, app.view)
+	assert.Equal(t, MsgNoTags, app.statusText)
+}
@@ -1,7 +1,5 @@
 package tui
 
-import "time"
-
 type View int
 
 const (
@@ -13,13 +11,12 @@ const (
 	ViewRenameFeed
 	ViewSearch
 	ViewMedia
+	ViewAllUnread
+	ViewTags
 )
 
-// UI timing and behavior constants
+// UI behavior constants
 const (
-	// Status display timing
-	DefaultStatusDuration = 500 * time.Millisecond // Maximum duration for status messages
-
 	// UI dimensions and spacing
 	MinReadableWidth      = 40  // Minimum width for readable content
 	MaxReadableWidth      = 120 // Maximum width for optimal readability
@@ -28,13 +25,12 @@ const (
 	PreferredModalWidth   = 60  // Preferred modal width
 	MinInputWidth         = 10  // Minimum input field width
 	NarrowScreenThreshold = 50  // Screen width threshold for narrow screen mode
+	MinTerminalWidth      = 40  // Below this View shows only a "too small" notice
+	MinTerminalHeight     = 10  // Likewise for height
 
 	// Renderer configuration
 	RendererWidthTolerance = 10 // Width change tolerance before re-creating renderer
 
-	// Article pagination
-	DefaultArticleLimit = 50 // Default number of articles to load
-
 	// defaultMaxDescriptionLength is the fallback used when articleItem
 	// has no configured limit (typically only in tests).
 	defaultMaxDescriptionLength = 80
@@ -48,10 +44,6 @@ const (
 	viewportChrome      = 3  // reader, media list (single header + status)
 	minSearchListHeight = 5  // floor when the terminal is very short
 
-	// defaultSearchResultLimit caps how many results a single search
-	// query returns to the UI.
-	defaultSearchResultLimit = 20
-
 	// searchResultDescLength caps the truncated description shown on
 	// each search result row in the result list.
 	searchResultDescLength = 50
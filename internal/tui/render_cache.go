@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"container/list"
+	"time"
+)
+
+// renderKey identifies one glamour rendering of an article. width is the
+// renderer's wrap width, which only moves once the terminal has changed by
+// more than RendererWidthTolerance, so small resizes keep hitting. updated
+// makes an article the feed has since revised render afresh.
+type renderKey struct {
+	articleID string
+	updated   time.Time
+	width     int
+	style     string
+}
+
+type renderEntry struct {
+	key         renderKey
+	content     string
+	readMinutes int
+}
+
+// renderCache holds the most recently opened renderings so flipping back
+// to an article skips glamour. It is only touched from Update, so it needs
+// no locking.
+type renderCache struct {
+	size  int
+	order *list.List // front is the most recently used *renderEntry
+	items map[renderKey]*list.Element
+}
+
+func newRenderCache(size int) *renderCache {
+	return &renderCache{size: size, order: list.New(), items: map[renderKey]*list.Element{}}
+}
+
+func (c *renderCache) get(k renderKey) (renderEntry, bool) {
+	el, ok := c.items[k]
+	if !ok {
+		return renderEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return *el.Value.(*renderEntry), true
+}
+
+// put stores an entry, evicting the least recently used one once the
+// cache is full.
+func (c *renderCache) put(e renderEntry) {
+	if el, ok := c.items[e.key]; ok {
+		el.Value = &e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[e.key] = c.order.PushFront(&e)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*renderEntry).key)
+	}
+}
+
+// clear drops every entry, for when the renderer is rebuilt and nothing
+// cached matches its output any more.
+func (c *renderCache) clear() {
+	c.order.Init()
+	clear(c.items)
+}
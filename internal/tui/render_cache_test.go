@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newRenderCache(2)
+	a, b, d := renderKey{articleID: "a"}, renderKey{articleID: "b"}, renderKey{articleID: "d"}
+	c.put(renderEntry{key: a, content: "A"})
+	c.put(renderEntry{key: b, content: "B"})
+
+	// Reading a makes b the least recently used.
+	_, ok := c.get(a)
+	assert.True(t, ok)
+	c.put(renderEntry{key: d, content: "D"})
+
+	_, ok = c.get(b)
+	assert.False(t, ok, "b should have been evicted")
+	e, ok := c.get(a)
+	assert.True(t, ok)
+	assert.Equal(t, "A", e.content)
+	_, ok = c.get(d)
+	assert.True(t, ok)
+
+	_, ok = c.get(renderKey{articleID: "a", width: 80})
+	assert.False(t, ok, "another width is another rendering")
+
+	c.clear()
+	_, ok = c.get(a)
+	assert.False(t, ok)
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pders01/fwrd/internal/debuglog"
+)
+
+// loadSearchHistory reads the recorded queries for recall in the search
+// view. With search.history_size at 0 none are kept or offered.
+func (a *App) loadSearchHistory() tea.Cmd {
+	if a.config.Search.HistorySize < 1 {
+		return nil
+	}
+	return func() tea.Msg {
+		queries, err := a.store.SearchHistory()
+		if err != nil {
+			debuglog.Warnf("loading search history: %v", err)
+			return nil
+		}
+		return searchHistoryLoadedMsg{queries: queries}
+	}
+}
+
+// recordSearch adds query to the search history. Like the session, a
+// failure is only logged.
+func (a *App) recordSearch(query string) tea.Cmd {
+	if query == "" || a.config.Search.HistorySize < 1 {
+		return nil
+	}
+	limit := a.config.Search.HistorySize
+	return func() tea.Msg {
+		if err := a.store.AddSearchHistory(query, limit); err != nil {
+			debuglog.Warnf("saving search history: %v", err)
+		}
+		return nil
+	}
+}
+
+// browsingSearchHistory reports whether up and down in the search input
+// step through history: while a recalled query is shown, or from an
+// empty input, as in a shell.
+func (a *App) browsingSearchHistory() bool {
+	return a.searchHistoryPos >= 0 || a.searchInput.Value() == ""
+}
+
+// recallSearch moves step entries back (older, step > 0) or forward
+// (newer, step < 0) in the search history and searches for the query
+// there. Moving forward past the newest entry empties the input.
+func (kh *KeyHandler) recallSearch(step int) (tea.Model, tea.Cmd) {
+	a := kh.app
+	pos := a.searchHistoryPos + step
+	if pos >= len(a.searchHistory) || pos == a.searchHistoryPos {
+		return a, nil
+	}
+	if pos < 0 {
+		a.searchHistoryPos = -1
+		a.searchInput.Reset()
+		return a, kh.scheduleSearch("")
+	}
+	a.searchHistoryPos = pos
+	a.searchInput.SetValue(a.searchHistory[pos])
+	a.searchInput.CursorEnd()
+	return a, kh.scheduleSearch(a.searchHistory[pos])
+}
+
+// scheduleSearch runs query once the input has been idle for the
+// debounce delay, superseding any search scheduled before it.
+func (kh *KeyHandler) scheduleSearch(query string) tea.Cmd {
+	kh.app.pendingSearchQuery = query
+	kh.app.searchSeq++
+	seq := kh.app.searchSeq
+	wait := time.Duration(kh.app.searchDebounceMillis) * time.Millisecond
+	return tea.Tick(wait, func(time.Time) tea.Msg { return searchDebounceFireMsg{seq: seq} })
+}
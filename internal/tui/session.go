@@ -0,0 +1,120 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pders01/fwrd/internal/debuglog"
+)
+
+// Metadata keys under which the last-viewed selection is persisted.
+const (
+	sessionFeedKey    = "ui.last_feed_id"
+	sessionArticleKey = "ui.last_article_id"
+	sessionViewKey    = "ui.last_view"
+)
+
+// sessionState is the selection restored on startup when
+// ui.restore_session is enabled.
+type sessionState struct {
+	feedID    string
+	articleID string
+}
+
+// loadFeedsWithSession is the startup variant of loadFeeds: it also reads
+// the persisted selection so the feed list can be positioned on the first
+// render.
+func (a *App) loadFeedsWithSession() tea.Cmd {
+	if !a.config.UI.RestoreSession {
+		return a.loadFeeds()
+	}
+	return func() tea.Msg {
+		feeds, err := a.store.GetAllFeeds()
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		msg := feedsLoadedMsg{feeds: feeds}
+		feedID, err := a.store.GetMeta(sessionFeedKey)
+		if err != nil || feedID == "" {
+			return msg
+		}
+		articleID, _ := a.store.GetMeta(sessionArticleKey)
+		msg.session = &sessionState{feedID: feedID, articleID: articleID}
+		return msg
+	}
+}
+
+// saveSession persists the current selection. articleID may be empty
+// when the user has only entered a feed. Failures are logged, never
+// surfaced: losing the bookmark is not worth an error banner.
+func (a *App) saveSession(feedID, articleID string) tea.Cmd {
+	if !a.config.UI.RestoreSession || feedID == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := a.store.SetMeta(sessionFeedKey, feedID); err != nil {
+			debuglog.Warnf("saving session feed: %v", err)
+			return nil
+		}
+		if err := a.store.SetMeta(sessionArticleKey, articleID); err != nil {
+			debuglog.Warnf("saving session article: %v", err)
+		}
+		return nil
+	}
+}
+
+// restoreFeedSelection moves the feed list cursor to the saved feed and
+// remembers the saved article for when that feed's articles load.
+func (a *App) restoreFeedSelection(s *sessionState) {
+	for i, f := range a.feeds {
+		if f.ID == s.feedID {
+			a.feedList.Select(i)
+			a.pendingRestoreFeedID = s.feedID
+			a.pendingRestoreArticleID = s.articleID
+			return
+		}
+	}
+}
+
+// restoreArticleSelection selects the saved article once, the first time
+// the saved feed's article list is loaded.
+func (a *App) restoreArticleSelection() {
+	if a.pendingRestoreArticleID == "" || a.currentFeed == nil || a.currentFeed.ID != a.pendingRestoreFeedID {
+		return
+	}
+	for i, item := range a.articleList.Items() {
+		if art, ok := item.(articleItem); ok && art.article.ID == a.pendingRestoreArticleID {
+			a.articleList.Select(i)
+			break
+		}
+	}
+	a.pendingRestoreFeedID = ""
+	a.pendingRestoreArticleID = ""
+}
+
+// openStartView switches to the view ui.start_view asks for, returning
+// the command that fills it. The feed list needs none.
+func (a *App) openStartView() tea.Cmd {
+	start := a.config.UI.StartView
+	if start == "last" {
+		start, _ = a.store.GetMeta(sessionViewKey)
+	}
+	if start != "unread" {
+		return nil
+	}
+	a.view = ViewAllUnread
+	return a.loadUnreadArticles()
+}
+
+// quit records the view for ui.start_view = "last" and ends the program.
+// The write is synchronous since no command runs after tea.Quit.
+func (a *App) quit() tea.Cmd {
+	if a.config.UI.StartView == "last" {
+		view := "feeds"
+		if a.view == ViewAllUnread || (a.view == ViewReader && a.readerOrigin == ViewAllUnread) {
+			view = "unread"
+		}
+		if err := a.store.SetMeta(sessionViewKey, view); err != nil {
+			debuglog.Warnf("saving session view: %v", err)
+		}
+	}
+	return tea.Quit
+}
@@ -15,12 +15,59 @@ const (
 	MsgNoResults      = "No results"
 	MsgFeedRenamed    = "Feed renamed"
 	MsgFeedDeleted    = "Feed deleted"
+	MsgArticleGone    = "Article no longer exists"
+	MsgFeedGone       = "Feed no longer exists"
+	MsgNoUnread       = "No unread articles"
+	MsgWrappedToTop   = "Wrapped to top"
+	MsgWrappedToEnd   = "Wrapped to bottom"
+	MsgRawView        = "Showing raw content"
+	MsgRendered       = "Showing rendered content"
+	MsgNoSiteURL      = "Feed names no homepage yet — refresh it first"
+	MsgNoTags         = "No tags on these articles"
+	MsgNoFeedTags     = "No feeds are tagged — add tags with fwrd feed tag"
+	MsgEmptyFeed      = "Feed returned no content"
+	MsgOldestFirst    = "Oldest first"
+	MsgNewestFirst    = "Newest first"
+	MsgSavingForLater = "Saving for later…"
+	MsgSavedForLater  = "Saved for later"
+	MsgNoReadLater    = "No read-it-later service — set one up under [integrations]"
+	MsgNoArticleLink  = "Article has no link to save"
 )
 
 func MsgAddedFeed(title string, count int) string {
 	return fmt.Sprintf("Added feed '%s' (%d articles)", strings.TrimSpace(title), count)
 }
 
+// MsgAlreadySubscribed points at the existing feed when one is added
+// twice.
+func MsgAlreadySubscribed(title string) string {
+	return fmt.Sprintf("Already subscribed to '%s'", strings.TrimSpace(title))
+}
+
+// MsgQuitConfirm asks for a second quit press while work is in flight.
+func MsgQuitConfirm(quitKey string) string {
+	return fmt.Sprintf("Operation in progress — press %s again to quit", quitKey)
+}
+
+// MsgOpenAllConfirm asks for a second press before opening n media items.
+func MsgOpenAllConfirm(n int, key string) string {
+	return fmt.Sprintf("Open all %d media items? Press %s again", n, key)
+}
+
+func MsgOpeningAll(n int) string {
+	return fmt.Sprintf("Opening %d media items…", n)
+}
+
+// MsgSaveForLaterFailed reports why the read-it-later service refused an
+// article.
+func MsgSaveForLaterFailed(err error) string {
+	return "Save for later failed: " + err.Error()
+}
+
+func MsgWouldRun(command string) string {
+	return "Would run: " + command
+}
+
 func MsgResultsCount(n int) string {
 	if n == 1 {
 		return "1 result"
@@ -43,6 +90,47 @@ func MsgThemeApplied(pref, style string) string {
 	return fmt.Sprintf("Theme: %s", pref)
 }
 
+// MsgReaderProgress reports the reader's scroll position and, when
+// known (minutes > 0), the article's estimated reading time.
+func MsgReaderProgress(percent, minutes int) string {
+	if minutes <= 0 {
+		return fmt.Sprintf("%d%%", percent)
+	}
+	return fmt.Sprintf("%d%% • %d min read", percent, minutes)
+}
+
+// MsgFindMatch reports which find-in-page match the reader is showing.
+func MsgFindMatch(i, n int, query string) string {
+	return fmt.Sprintf("Match %d of %d for %q", i, n, query)
+}
+
+// MsgFindNoMatch reports a find-in-page that found nothing.
+func MsgFindNoMatch(query string) string {
+	return fmt.Sprintf("No matches for %q in this article", query)
+}
+
+// MsgTagFilter shows the tag the article list is narrowed to.
+func MsgTagFilter(tag string) string {
+	return fmt.Sprintf("tag: %s", tag)
+}
+
+// MsgUnreadInbox subtitles the unread view with how many articles it holds.
+func MsgUnreadInbox(n int) string {
+	if n == 1 {
+		return "1 unread article across all feeds"
+	}
+	return fmt.Sprintf("%d unread articles across all feeds", n)
+}
+
+// MsgTerminalTooSmall replaces the whole UI when the window cannot fit it.
+func MsgTerminalTooSmall(width, height int) string {
+	return fmt.Sprintf("Terminal too small (%d×%d); need at least %d×%d", width, height, MinTerminalWidth, MinTerminalHeight)
+}
+
+func MsgRefreshProgress(done, total int) string {
+	return fmt.Sprintf("Refreshing %d/%d…", done, total)
+}
+
 func MsgRefreshSummary(updatedFeeds, addedArticles, errors, docCount int) string {
 	base := fmt.Sprintf("Refreshed: %d feeds • %d articles", updatedFeeds, addedArticles)
 	if errors > 0 {
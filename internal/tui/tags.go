@@ -0,0 +1,196 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/pders01/fwrd/internal/storage"
+)
+
+// tagItem is one tag picker entry: a tag and how many loaded articles,
+// or feeds when feeds is set, carry it. The entry with no tag lists
+// every one again.
+type tagItem struct {
+	tag   string
+	count int
+	feeds bool
+}
+
+func (i tagItem) Title() string {
+	if i.tag == "" && i.feeds {
+		return "All feeds"
+	}
+	if i.tag == "" {
+		return "All articles"
+	}
+	return i.tag
+}
+
+func (i tagItem) Description() string {
+	unit := "article"
+	if i.feeds {
+		unit = "feed"
+	}
+	if i.count == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", i.count, unit)
+}
+
+func (i tagItem) FilterValue() string { return i.tag }
+
+// loadedTags counts the tags across articles, most used first and ties
+// by name.
+func loadedTags(articles []*storage.Article) []tagItem {
+	counts := map[string]int{}
+	for _, art := range articles {
+		for _, tag := range art.Tags {
+			counts[tag]++
+		}
+	}
+	return rankTags(counts, false)
+}
+
+// feedTags counts the tags across feeds like loadedTags does. Feed tags
+// are typed by the user and match case-insensitively, so each is counted
+// under the spelling first seen.
+func feedTags(feeds []*storage.Feed) []tagItem {
+	counts := map[string]int{}
+	spelling := map[string]string{}
+	for _, f := range feeds {
+		for _, tag := range f.Tags {
+			key := strings.ToLower(tag)
+			if _, ok := spelling[key]; !ok {
+				spelling[key] = tag
+			}
+			counts[spelling[key]]++
+		}
+	}
+	return rankTags(counts, true)
+}
+
+func rankTags(counts map[string]int, feeds bool) []tagItem {
+	tags := make([]tagItem, 0, len(counts))
+	for tag, n := range counts {
+		tags = append(tags, tagItem{tag: tag, count: n, feeds: feeds})
+	}
+	slices.SortFunc(tags, func(a, b tagItem) int {
+		if a.count != b.count {
+			return b.count - a.count
+		}
+		return strings.Compare(a.tag, b.tag)
+	})
+	return tags
+}
+
+// hasFeedTag reports whether f carries tag, ignoring case.
+func hasFeedTag(f *storage.Feed, tag string) bool {
+	return slices.ContainsFunc(f.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// feedItems builds list items for the feeds that pass the feed tag
+// filter, or for all of them when none is set.
+func (a *App) feedItems(feeds []*storage.Feed) []list.Item {
+	items := make([]list.Item, 0, len(feeds))
+	for _, f := range feeds {
+		if a.feedTagFilter == "" || hasFeedTag(f, a.feedTagFilter) {
+			items = append(items, feedItem{feed: f})
+		}
+	}
+	return items
+}
+
+// setFeedTagFilter narrows the feed list to tag, or lists every feed
+// again when tag is "".
+func (a *App) setFeedTagFilter(tag string) {
+	a.feedTagFilter = tag
+	a.feedList.ResetFilter()
+	a.feedList.SetItems(a.feedItems(a.feeds))
+	a.feedList.Select(0)
+}
+
+// selectFeed moves the feed list cursor to the feed with id. A feed the
+// tag filter hides is shown by dropping the filter first.
+func (a *App) selectFeed(id string) {
+	for pass := 0; pass < 2; pass++ {
+		for i, item := range a.feedList.Items() {
+			if fi, ok := item.(feedItem); ok && fi.feed.ID == id {
+				a.feedList.Select(i)
+				return
+			}
+		}
+		if a.feedTagFilter == "" {
+			return
+		}
+		a.setFeedTagFilter("")
+	}
+}
+
+// articleItems builds list items for the articles that pass the tag
+// filter, or for all of them when none is set. In a feed's article list
+// a divider marks where the articles new since the last visit end.
+func (a *App) articleItems(articles []*storage.Article) []list.Item {
+	items := make([]list.Item, 0, len(articles)+1)
+	for _, art := range articles {
+		if a.tagFilter == "" || slices.Contains(art.Tags, a.tagFilter) {
+			items = append(items, a.newArticleItem(art))
+		}
+	}
+	if a.view == ViewArticles {
+		items = withVisitDivider(items, a.visitCutoff)
+	}
+	return items
+}
+
+// setTagFilter narrows the article list to tag, or lists every loaded
+// article again when tag is "".
+func (a *App) setTagFilter(tag string) {
+	a.tagFilter = tag
+	a.articleList.ResetFilter()
+	a.articleList.SetItems(a.articleItems(a.articles))
+	a.articleList.Select(0)
+}
+
+// openTagPicker lists the tags of the loaded articles to filter by,
+// with the current filter selected.
+func (kh *KeyHandler) openTagPicker() tea.Cmd {
+	tags := loadedTags(kh.app.articles)
+	if len(tags) == 0 {
+		kh.app.setStatus(MsgNoTags, 0)
+		return nil
+	}
+	kh.showTagPicker(tagItem{count: len(kh.app.articles)}, tags, kh.app.tagFilter)
+	return nil
+}
+
+// openFeedTagPicker lists the tags of the feeds to narrow the feed list
+// by, with the current filter selected.
+func (kh *KeyHandler) openFeedTagPicker() tea.Cmd {
+	tags := feedTags(kh.app.feeds)
+	if len(tags) == 0 {
+		kh.app.setStatus(MsgNoFeedTags, 0)
+		return nil
+	}
+	kh.showTagPicker(tagItem{count: len(kh.app.feeds), feeds: true}, tags, kh.app.feedTagFilter)
+	return nil
+}
+
+func (kh *KeyHandler) showTagPicker(all tagItem, tags []tagItem, current string) {
+	items := make([]list.Item, 0, len(tags)+1)
+	items = append(items, all)
+	selected := 0
+	for _, t := range tags {
+		if strings.EqualFold(t.tag, current) && current != "" {
+			selected = len(items)
+		}
+		items = append(items, t)
+	}
+	kh.app.tagList.SetItems(items)
+	kh.app.tagList.Select(selected)
+	kh.app.previousView = kh.app.view
+	kh.app.view = ViewTags
+}
@@ -1,5 +1,11 @@
 package tui
 
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
 // truncateEnd shortens s to at most max characters, appending an ellipsis
 // if truncation occurs. Handles negative or tiny limits gracefully.
 func truncateEnd(s string, limit int) string {
@@ -44,3 +50,25 @@ func truncateMiddle(s string, limit int) string {
 	}
 	return string(r[:left]) + "…" + string(r[n-right:])
 }
+
+// centerLines indents every line of block by the same amount so its
+// widest line sits centered in width columns. A block as wide as width
+// or wider is returned as is.
+func centerLines(block string, width int) string {
+	lines := strings.Split(block, "\n")
+	widest := 0
+	for _, line := range lines {
+		widest = max(widest, lipgloss.Width(line))
+	}
+	pad := (width - widest) / 2
+	if pad <= 0 {
+		return block
+	}
+	indent := strings.Repeat(" ", pad)
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
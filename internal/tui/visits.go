@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+
+	"github.com/pders01/fwrd/internal/storage"
+)
+
+// visitDividerItem is the line in a feed's article list between the
+// articles that arrived since the last visit and the ones that did not.
+// newAbove says which side of it the new ones are on, which depends on
+// the feed's reading order.
+type visitDividerItem struct {
+	newAbove bool
+}
+
+func (d visitDividerItem) Title() string {
+	if d.newAbove {
+		return renderMuted("── ▲ new since last visit ──")
+	}
+	return renderMuted("── ▼ new since last visit ──")
+}
+
+func (d visitDividerItem) Description() string { return "" }
+
+// FilterValue is empty so the divider drops out of a filtered list.
+func (d visitDividerItem) FilterValue() string { return "" }
+
+// newSince reports whether art arrived after cutoff: when it was first
+// saved, or when it was published for articles saved before that was
+// recorded.
+func newSince(art *storage.Article, cutoff time.Time) bool {
+	at := art.FetchedAt
+	if at.IsZero() {
+		at = art.Published
+	}
+	return at.After(cutoff)
+}
+
+// withVisitDivider puts a visitDividerItem where the list first passes
+// between articles new since cutoff and older ones. A zero cutoff, a
+// first visit, gets none, nor does a list that is all new or all old.
+func withVisitDivider(items []list.Item, cutoff time.Time) []list.Item {
+	if cutoff.IsZero() {
+		return items
+	}
+	var seen, prevNew bool
+	for i, item := range items {
+		art, ok := item.(articleItem)
+		if !ok {
+			continue
+		}
+		isNew := newSince(art.article, cutoff)
+		if seen && isNew != prevNew {
+			out := make([]list.Item, 0, len(items)+1)
+			out = append(out, items[:i]...)
+			out = append(out, visitDividerItem{newAbove: prevNew})
+			return append(out, items[i:]...)
+		}
+		seen, prevNew = true, isNew
+	}
+	return items
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -22,12 +23,20 @@ type FilePathValidator struct {
 // NewFilePathValidator creates a new validator with secure defaults
 func NewFilePathValidator() *FilePathValidator {
 	homeDir, _ := os.UserHomeDir()
+	baseDirs := []string{
+		filepath.Join(homeDir, ".fwrd"),
+		filepath.Join(homeDir, ".config", "fwrd"),
+		os.TempDir(),
+	}
+	// The XDG locations, when set, hold the same files.
+	if dir, err := DataDir(); err == nil && !slices.Contains(baseDirs, dir) {
+		baseDirs = append(baseDirs, dir)
+	}
+	if dir, err := ConfigDir(); err == nil && !slices.Contains(baseDirs, dir) {
+		baseDirs = append(baseDirs, dir)
+	}
 	return &FilePathValidator{
-		AllowedBaseDirs: []string{
-			filepath.Join(homeDir, ".fwrd"),
-			filepath.Join(homeDir, ".config", "fwrd"),
-			os.TempDir(),
-		},
+		AllowedBaseDirs:    baseDirs,
 		AllowHomeExpansion: true,
 		AllowRelativePaths: false,
 		MaxPathLength:      4096,
@@ -211,8 +220,8 @@ func (v *FilePathValidator) ValidateDirectory(path string, createIfNotExist bool
 	if err != nil {
 		if os.IsNotExist(err) {
 			if createIfNotExist {
-				// Create directory with secure permissions
-				if mkErr := os.MkdirAll(validatedPath, 0o755); mkErr != nil {
+				// Owner-only: these hold the database and search index.
+				if mkErr := os.MkdirAll(validatedPath, 0o700); mkErr != nil {
 					return "", fmt.Errorf("failed to create directory: %w", mkErr)
 				}
 			} else {
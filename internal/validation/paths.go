@@ -5,6 +5,47 @@ import (
 	"path/filepath"
 )
 
+// DataDir is where fwrd keeps its database and search index:
+// $XDG_DATA_HOME/fwrd when XDG_DATA_HOME is set to an absolute path,
+// otherwise ~/.fwrd. An existing ~/.fwrd still wins over an XDG location
+// that has not been created yet, so exporting XDG_DATA_HOME later doesn't
+// leave an established database behind.
+func DataDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	legacy := filepath.Join(homeDir, ".fwrd")
+	xdg := os.Getenv("XDG_DATA_HOME")
+	if !filepath.IsAbs(xdg) {
+		return legacy, nil
+	}
+	dir := filepath.Join(xdg, "fwrd")
+	if !dirExists(dir) && dirExists(legacy) {
+		return legacy, nil
+	}
+	return dir, nil
+}
+
+// ConfigDir is where fwrd looks for config.toml: $XDG_CONFIG_HOME/fwrd
+// when XDG_CONFIG_HOME is set to an absolute path, otherwise
+// ~/.config/fwrd.
+func ConfigDir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "fwrd"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "fwrd"), nil
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
 // PathHandler provides secure path operations with validation
 type PathHandler struct {
 	validator *FilePathValidator
@@ -33,11 +74,11 @@ func (ph *PathHandler) ExpandAndValidatePath(path string) (string, error) {
 func (ph *PathHandler) GetSecureDBPath(userPath string) (string, error) {
 	if userPath == "" {
 		// Default secure location
-		homeDir, err := os.UserHomeDir()
+		dataDir, err := DataDir()
 		if err != nil {
 			return "", err
 		}
-		userPath = filepath.Join(homeDir, ".fwrd", "fwrd.db")
+		userPath = filepath.Join(dataDir, "fwrd.db")
 	}
 
 	return ph.validator.ValidateFile(userPath)
@@ -47,11 +88,11 @@ func (ph *PathHandler) GetSecureDBPath(userPath string) (string, error) {
 func (ph *PathHandler) GetSecureConfigPath(userPath string) (string, error) {
 	if userPath == "" {
 		// Default secure location
-		homeDir, err := os.UserHomeDir()
+		configDir, err := ConfigDir()
 		if err != nil {
 			return "", err
 		}
-		userPath = filepath.Join(homeDir, ".config", "fwrd", "config.toml")
+		userPath = filepath.Join(configDir, "config.toml")
 	}
 
 	return ph.validator.ValidateFile(userPath)
@@ -61,11 +102,11 @@ func (ph *PathHandler) GetSecureConfigPath(userPath string) (string, error) {
 func (ph *PathHandler) GetSecureIndexPath(userPath string) (string, error) {
 	if userPath == "" {
 		// Default secure location
-		homeDir, err := os.UserHomeDir()
+		dataDir, err := DataDir()
 		if err != nil {
 			return "", err
 		}
-		userPath = filepath.Join(homeDir, ".fwrd", "index.bleve")
+		userPath = filepath.Join(dataDir, "index.bleve")
 	}
 
 	// Validate as directory since bleve indexes are directories
@@ -3,6 +3,7 @@ package validation
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -334,8 +335,11 @@ func TestEnsureSecureDirectory(t *testing.T) {
 
 				// Check if directory was created
 				if tt.shouldCreate {
-					if _, err := os.Stat(tt.path); os.IsNotExist(err) {
+					info, err := os.Stat(tt.path)
+					if os.IsNotExist(err) {
 						t.Errorf("Directory was not created: %s", tt.path)
+					} else if runtime.GOOS != "windows" && info.Mode().Perm() != 0o700 {
+						t.Errorf("Directory mode = %o, want 700", info.Mode().Perm())
 					}
 				}
 			}
@@ -401,6 +405,8 @@ func TestPathHandlerEdgeCases(t *testing.T) {
 }
 
 func TestDefaultPaths(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
 	ph := NewPermissivePathHandler()
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -437,3 +443,60 @@ func TestDefaultPaths(t *testing.T) {
 		t.Errorf("Expected default index path %q, got %q", expectedIndex, indexPath)
 	}
 }
+
+func TestDefaultPathsHonorXDG(t *testing.T) {
+	home := t.TempDir()
+	xdgData := filepath.Join(t.TempDir(), "data")
+	xdgConfig := filepath.Join(t.TempDir(), "config")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", xdgData)
+	t.Setenv("XDG_CONFIG_HOME", xdgConfig)
+
+	ph := NewPermissivePathHandler()
+	dbPath, err := ph.GetSecureDBPath("")
+	if err != nil {
+		t.Fatalf("GetSecureDBPath: %v", err)
+	}
+	if want := filepath.Join(xdgData, "fwrd", "fwrd.db"); dbPath != want {
+		t.Errorf("DB path = %q, want %q", dbPath, want)
+	}
+	indexPath, err := ph.GetSecureIndexPath("")
+	if err != nil {
+		t.Fatalf("GetSecureIndexPath: %v", err)
+	}
+	if want := filepath.Join(xdgData, "fwrd", "index.bleve"); indexPath != want {
+		t.Errorf("index path = %q, want %q", indexPath, want)
+	}
+	configPath, err := ph.GetSecureConfigPath("")
+	if err != nil {
+		t.Fatalf("GetSecureConfigPath: %v", err)
+	}
+	if want := filepath.Join(xdgConfig, "fwrd", "config.toml"); configPath != want {
+		t.Errorf("config path = %q, want %q", configPath, want)
+	}
+
+	// The secure validator accepts files under the XDG directories.
+	if _, err := NewSecurePathHandler().GetSecureDBPath(filepath.Join(xdgData, "fwrd", "other.db")); err != nil {
+		t.Errorf("secure handler rejected a path under XDG_DATA_HOME: %v", err)
+	}
+
+	// Relative XDG values are invalid per the spec and ignored.
+	t.Setenv("XDG_DATA_HOME", "relative/data")
+	if dir, _ := DataDir(); dir != filepath.Join(home, ".fwrd") {
+		t.Errorf("DataDir() with relative XDG_DATA_HOME = %q, want ~/.fwrd", dir)
+	}
+}
+
+func TestDataDirKeepsExistingLegacyDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(t.TempDir(), "data"))
+
+	legacy := filepath.Join(home, ".fwrd")
+	if err := os.MkdirAll(legacy, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if dir, _ := DataDir(); dir != legacy {
+		t.Errorf("DataDir() = %q, want the existing %q", dir, legacy)
+	}
+}
@@ -455,6 +455,37 @@ func TestIsPrivateIP(t *testing.T) {
 	}
 }
 
+func TestValidateIP(t *testing.T) {
+	secure := NewFeedURLValidator()
+	permissive := NewPermissiveFeedURLValidator()
+
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"127.0.1.1", true},
+		{"::1", true},
+		{"0.0.0.0", true},
+		{"::ffff:127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"192.168.0.10", true},
+		{"169.254.169.254", true},
+		{"fd00::1", true},
+		{"93.184.216.34", false},
+		{"2606:2800:220:1::248", false},
+	}
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		if err := secure.ValidateIP(ip); (err != nil) != tt.blocked {
+			t.Errorf("ValidateIP(%s) error = %v, want blocked %v", tt.ip, err, tt.blocked)
+		}
+		if err := permissive.ValidateIP(ip); err != nil {
+			t.Errorf("permissive ValidateIP(%s) error = %v", tt.ip, err)
+		}
+	}
+}
+
 func TestIsSuspiciousHostname(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -669,3 +700,43 @@ func TestEdgeCases(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expected, result)
 	}
 }
+
+func TestMediaURLValidator(t *testing.T) {
+	v := NewMediaURLValidator([]string{"http", "HTTPS", "magnet:", "mailto"})
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "http", input: "http://example.org/a.mp4", want: "http://example.org/a.mp4"},
+		{name: "scheme lower-cased", input: " HTTPS://Example.org/A?b=c ", want: "https://Example.org/A?b=c"},
+		{name: "magnet kept verbatim", input: "magnet:?xt=urn:btih:abc&dn=a%20b", want: "magnet:?xt=urn:btih:abc&dn=a%20b"},
+		{name: "mailto", input: "mailto:someone@example.org", want: "mailto:someone@example.org"},
+		{name: "file", input: "file:///etc/passwd", wantErr: true},
+		{name: "javascript", input: "javascript:alert(1)", wantErr: true},
+		{name: "data", input: "data:text/html,<b>x</b>", wantErr: true},
+		{name: "no scheme", input: "-e calc.exe", wantErr: true},
+		{name: "relative", input: "/etc/passwd", wantErr: true},
+		{name: "embedded space", input: "https://example.org/a b", wantErr: true},
+		{name: "control character", input: "https://example.org/a\nb", wantErr: true},
+		{name: "http without host", input: "http:example.org", wantErr: true},
+		{name: "empty", input: "  ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.ValidateAndNormalize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAndNormalize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateAndNormalize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewMediaURLValidator([]string{"file"}).ValidateAndNormalize("file:///tmp/a.pdf"); err != nil {
+		t.Errorf("file: should open once allowed: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/pders01/fwrd/internal/storage"
+)
+
+// The JSON API under /api is read-only: it serves the same store and
+// search the HTML pages do, for frontends and scripts, and exposes no
+// mutation. It sits behind the same Basic Auth as the rest of the server
+// and is otherwise unauthenticated.
+
+// apiFeed is a feed with its article counts, flattened into one object.
+type apiFeed struct {
+	*storage.Feed
+	Unread int `json:"unread"`
+	Total  int `json:"total"`
+}
+
+type apiArticles struct {
+	Articles []*storage.Article `json:"articles"`
+	// NextCursor is passed back as ?cursor= for the following page;
+	// empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type apiSearchResult struct {
+	Article *storage.Article `json:"article"`
+	Score   float64          `json:"score"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleAPIFeeds(w http.ResponseWriter, _ *http.Request) {
+	feeds, err := s.store.GetAllFeeds()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to load feeds: "+err.Error())
+		return
+	}
+	stats, err := s.store.FeedStats()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to load feed stats: "+err.Error())
+		return
+	}
+	out := make([]apiFeed, 0, len(feeds))
+	for _, f := range feeds {
+		st := stats[f.ID]
+		out = append(out, apiFeed{Feed: f, Unread: st.Unread, Total: st.Total})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleAPIArticles pages through a feed's articles in the feed's own
+// reading order, articlesPerPage at a time.
+func (s *Server) handleAPIArticles(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	feed, err := s.store.GetFeed(id)
+	if errors.Is(err, storage.ErrFeedNotFound) || (err == nil && feed == nil) {
+		writeAPIError(w, http.StatusNotFound, "feed not found")
+		return
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to load feed: "+err.Error())
+		return
+	}
+	// Fetch one extra to detect whether a further page exists.
+	articles, err := s.store.GetArticlesSorted(id, articlesPerPage+1, r.URL.Query().Get("cursor"), feed.OldestFirst())
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "failed to load articles: "+err.Error())
+		return
+	}
+	out := apiArticles{Articles: articles}
+	if len(articles) > articlesPerPage {
+		out.Articles = articles[:articlesPerPage]
+		out.NextCursor = out.Articles[articlesPerPage-1].ID
+	}
+	if out.Articles == nil {
+		out.Articles = []*storage.Article{}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleAPISearch(w http.ResponseWriter, r *http.Request) {
+	if s.searcher == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "search unavailable")
+		return
+	}
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		writeAPIError(w, http.StatusBadRequest, "missing q parameter")
+		return
+	}
+	results, err := s.searcher.Search(q, articlesPerPage)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "search failed: "+err.Error())
+		return
+	}
+	out := make([]apiSearchResult, 0, len(results))
+	for _, res := range results {
+		if res.Article == nil {
+			continue
+		}
+		// The index holds a thin article; hydrate it as handleSearch does.
+		a := res.Article
+		if full, err := s.store.GetArticle(a.ID); err == nil && full != nil {
+			a = full
+		}
+		out = append(out, apiSearchResult{Article: a, Score: res.Score})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// writeJSON encodes v as the response body. v is built from store types
+// that always encode, so an error here means the client went away.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, apiError{Error: msg})
+}
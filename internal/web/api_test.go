@@ -0,0 +1,124 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/pders01/fwrd/internal/config"
+	"github.com/pders01/fwrd/internal/search"
+	"github.com/pders01/fwrd/internal/storage"
+)
+
+func getJSON(t *testing.T, h http.Handler, path string, wantStatus int, v any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+	if rec.Code != wantStatus {
+		t.Fatalf("GET %s: status %d, want %d: %s", path, rec.Code, wantStatus, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("GET %s: Content-Type %q", path, ct)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("GET %s: decoding %q: %v", path, rec.Body.String(), err)
+	}
+}
+
+func TestAPIFeeds(t *testing.T) {
+	srv, store := newTestServer(t)
+	feedID, _ := seed(t, store)
+
+	var feeds []struct {
+		ID     string `json:"id"`
+		Title  string `json:"title"`
+		Unread int    `json:"unread"`
+		Total  int    `json:"total"`
+	}
+	getJSON(t, srv.Handler(), "/api/feeds", http.StatusOK, &feeds)
+	if len(feeds) != 1 {
+		t.Fatalf("got %d feeds, want 1", len(feeds))
+	}
+	f := feeds[0]
+	if f.ID != feedID || f.Title != "Example" || f.Unread != 1 || f.Total != 1 {
+		t.Errorf("feed = %+v", f)
+	}
+}
+
+func TestAPIArticlesPaginates(t *testing.T) {
+	srv, store := newTestServer(t)
+	if err := store.SaveFeed(&storage.Feed{ID: "f", URL: "http://example.com/f"}); err != nil {
+		t.Fatalf("SaveFeed: %v", err)
+	}
+	var arts []*storage.Article
+	for i := range articlesPerPage + 5 {
+		arts = append(arts, &storage.Article{ID: "f:" + strconv.Itoa(i), FeedID: "f", Title: strconv.Itoa(i)})
+	}
+	if err := store.SaveArticles(arts); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+	h := srv.Handler()
+
+	var page apiArticles
+	getJSON(t, h, "/api/feeds/f/articles", http.StatusOK, &page)
+	if len(page.Articles) != articlesPerPage || page.NextCursor == "" {
+		t.Fatalf("first page: %d articles, cursor %q", len(page.Articles), page.NextCursor)
+	}
+	var rest apiArticles
+	getJSON(t, h, "/api/feeds/f/articles?cursor="+url.QueryEscape(page.NextCursor), http.StatusOK, &rest)
+	if len(rest.Articles) != 5 || rest.NextCursor != "" {
+		t.Errorf("second page: %d articles, cursor %q", len(rest.Articles), rest.NextCursor)
+	}
+
+	var apiErr apiError
+	getJSON(t, h, "/api/feeds/missing/articles", http.StatusNotFound, &apiErr)
+	if apiErr.Error == "" {
+		t.Error("expected an error message for a missing feed")
+	}
+}
+
+// stubSearcher returns a thin article, as the search index does.
+type stubSearcher struct{ id string }
+
+func (s stubSearcher) Search(string, int) ([]*search.Result, error) {
+	return []*search.Result{{Article: &storage.Article{ID: s.id}, IsArticle: true, Score: 1.5}}, nil
+}
+func (s stubSearcher) SearchInArticle(*storage.Article, string) ([]*search.Result, error) {
+	return nil, nil
+}
+
+func TestAPISearch(t *testing.T) {
+	srv, store := newTestServer(t)
+	_, articleID := seed(t, store)
+
+	var apiErr apiError
+	getJSON(t, srv.Handler(), "/api/search?q=hello", http.StatusServiceUnavailable, &apiErr)
+
+	srv, err := NewServer(store, nil, stubSearcher{id: articleID}, &config.Config{})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	h := srv.Handler()
+	getJSON(t, h, "/api/search", http.StatusBadRequest, &apiErr)
+
+	var results []apiSearchResult
+	getJSON(t, h, "/api/search?q=hello", http.StatusOK, &results)
+	if len(results) != 1 || results[0].Score != 1.5 {
+		t.Fatalf("results = %+v", results)
+	}
+	if a := results[0].Article; a.Title != "Hello" || a.FeedID == "" {
+		t.Errorf("result article not hydrated from the store: %+v", a)
+	}
+}
+
+func TestAPIIsReadOnly(t *testing.T) {
+	srv, _ := newTestServer(t)
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/feeds", http.NoBody))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST /api/feeds: status %d, want 405", rec.Code)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -208,6 +209,54 @@ func TestOPMLImportViaUpload(t *testing.T) {
 	}
 }
 
+func TestOPMLImportSkipsFeedAlreadySubscribedUnderAnotherURL(t *testing.T) {
+	srv, store, backend := newManagerServer(t)
+	h := srv.Handler()
+	old := httptest.NewServer(http.RedirectHandler(backend.URL, http.StatusMovedPermanently))
+	t.Cleanup(old.Close)
+
+	for _, u := range []string{backend.URL, old.URL} {
+		doc := `<?xml version="1.0"?><opml version="2.0"><body>` +
+			`<outline type="rss" text="Backend" xmlUrl="` + u + `"/>` +
+			`</body></opml>`
+		rec := postMultipart(t, h, "/opml/import", "file", "feeds.opml", doc)
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("status %d, want 303: %s", rec.Code, rec.Body.String())
+		}
+		if u != old.URL {
+			continue
+		}
+		flash := flashFromRec(t, rec)
+		if flash == nil || flash.Kind != flashNotice || !strings.Contains(flash.Text, "1 already present") {
+			t.Errorf("flash = %+v, want a notice counting the feed as already present", flash)
+		}
+	}
+	if feeds, _ := store.GetAllFeeds(); len(feeds) != 1 {
+		t.Fatalf("the redirected URL should not add a second feed, got %+v", feeds)
+	}
+}
+
+func TestOPMLGroupedExportImportKeepsTags(t *testing.T) {
+	srv, store, backend := newManagerServer(t)
+	h := srv.Handler()
+
+	doc, err := opml.Export([]*storage.Feed{{URL: backend.URL, Title: "Backend", Tags: []string{"Tech", "News"}}}, time.Time{})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	rec := postMultipart(t, h, "/opml/import", "file", "feeds.opml", string(doc))
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("status %d, want 303: %s", rec.Code, rec.Body.String())
+	}
+	feeds, _ := store.GetAllFeeds()
+	if len(feeds) != 1 {
+		t.Fatalf("import should add the backend feed once, got %+v", feeds)
+	}
+	if got := feeds[0].Tags; !slices.Equal(got, []string{"News", "Tech"}) {
+		t.Errorf("imported tags = %v, want the exported categories [News Tech]", got)
+	}
+}
+
 func TestOPMLImportDisabledWhenNoManager(t *testing.T) {
 	srv, _ := newTestServer(t) // nil manager
 	doc := `<opml version="2.0"><body><outline type="rss" xmlUrl="http://x/f"/></body></opml>`
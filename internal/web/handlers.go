@@ -1,6 +1,7 @@
 package web
 
 import (
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pders01/fwrd/internal/feed"
 	"github.com/pders01/fwrd/internal/opml"
 	"github.com/pders01/fwrd/internal/search"
 	"github.com/pders01/fwrd/internal/storage"
@@ -103,10 +105,14 @@ func (s *Server) handleFeeds(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	feed, err := s.store.GetFeed(id)
-	if err != nil || feed == nil {
+	if errors.Is(err, storage.ErrFeedNotFound) || (err == nil && feed == nil) {
 		http.NotFound(w, r)
 		return
 	}
+	if err != nil {
+		http.Error(w, "failed to load feed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 	cursor := r.URL.Query().Get("cursor")
 	// Fetch one extra to detect whether a further page exists.
 	articles, err := s.store.GetArticlesWithCursor(id, articlesPerPage+1, cursor)
@@ -131,10 +137,14 @@ func (s *Server) handleArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	article, err := s.store.GetArticle(id)
-	if err != nil || article == nil {
+	if errors.Is(err, storage.ErrArticleNotFound) || (err == nil && article == nil) {
 		http.NotFound(w, r)
 		return
 	}
+	if err != nil {
+		http.Error(w, "failed to load article: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 	// Feed lookup is best-effort: render the article even if the parent
 	// feed record has gone missing.
 	feed, _ := s.store.GetFeed(article.FeedID)
@@ -186,7 +196,7 @@ func (s *Server) handleAddFeed(w http.ResponseWriter, r *http.Request) {
 	}
 	s.writeMu.Lock()
 	defer s.writeMu.Unlock()
-	f, err := s.manager.AddFeed(feedURL)
+	f, err := s.manager.AddFeedContext(r.Context(), feedURL)
 	if err != nil {
 		setFlash(w, flashError, "Couldn't add "+feedURL+": "+err.Error())
 		redirect(w, r, "/feeds")
@@ -204,7 +214,7 @@ func (s *Server) handleRefreshFeed(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	s.writeMu.Lock()
 	defer s.writeMu.Unlock()
-	if err := s.manager.RefreshFeed(id); err != nil {
+	if err := s.manager.RefreshFeedContext(r.Context(), id); err != nil {
 		// The feed page shows the persisted error badge; the flash names it.
 		setFlash(w, flashError, "Refresh failed: "+err.Error())
 		redirect(w, r, "/feeds/"+id)
@@ -344,11 +354,25 @@ func (s *Server) handleOPMLImport(w http.ResponseWriter, r *http.Request) {
 		}
 		// Best-effort: a feed that fails to fetch is skipped so one bad
 		// entry doesn't abort the whole import.
-		if _, err := s.manager.AddFeed(f.URL); err != nil {
+		nf, err := s.manager.AddFeed(f.URL)
+		var subscribed *feed.AlreadySubscribedError
+		if errors.As(err, &subscribed) {
+			// Listed under an old or alternate URL that leads to a feed
+			// already stored.
+			skipped++
+			continue
+		}
+		if err != nil {
 			failed++
 			continue
 		}
 		added++
+		// OPML categories become tags, as fwrd feed import does. The feed
+		// is in either way, so a failed save only loses its tags.
+		if len(f.Categories) > 0 {
+			nf.Tags = f.Categories
+			_ = s.store.SaveFeed(nf)
+		}
 	}
 	msg := fmt.Sprintf("Imported %d feed(s)", added)
 	if skipped > 0 {
@@ -3,7 +3,8 @@
 // sanitized HTML — the form RSS content is authored in — rather than
 // degrading it to terminal markdown the way the TUI must. State-changing
 // actions (add/delete/refresh feeds, mark read) are exposed as no-JS POST
-// forms guarded by a same-origin check.
+// forms guarded by a same-origin check. A read-only JSON API under /api
+// serves the same feeds, articles and search to other frontends.
 package web
 
 import (
@@ -131,6 +132,9 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("GET /favicon.svg", s.handleFavicon)
 	mux.HandleFunc("GET /favicon.ico", s.handleFavicon)
 	mux.HandleFunc("GET /opml/export", s.handleOPMLExport)
+	mux.HandleFunc("GET /api/feeds", s.handleAPIFeeds)
+	mux.HandleFunc("GET /api/feeds/{id}/articles", s.handleAPIArticles)
+	mux.HandleFunc("GET /api/search", s.handleAPISearch)
 
 	mux.HandleFunc("POST /feeds", s.handleAddFeed)
 	mux.HandleFunc("POST /opml/import", s.handleOPMLImport)
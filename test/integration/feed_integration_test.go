@@ -220,7 +220,7 @@ func TestIntegration_MediaExtraction(t *testing.T) {
 	// Check media URLs were extracted
 	var foundImage bool
 	for _, article := range articles {
-		for _, url := range article.MediaURLs {
+		for _, url := range article.MediaURLs() {
 			if strings.HasSuffix(url, "/image1.jpg") {
 				foundImage = true
 				break